@@ -22,6 +22,7 @@ import (
 
 	"github.com/crunchydata/postgres-operator/internal/bridge"
 	"github.com/crunchydata/postgres-operator/internal/bridge/crunchybridgecluster"
+	"github.com/crunchydata/postgres-operator/internal/controller/pgswitchover"
 	"github.com/crunchydata/postgres-operator/internal/controller/pgupgrade"
 	"github.com/crunchydata/postgres-operator/internal/controller/postgrescluster"
 	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
@@ -30,6 +31,7 @@ import (
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/logging"
 	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/pki"
 	"github.com/crunchydata/postgres-operator/internal/registration"
 	"github.com/crunchydata/postgres-operator/internal/upgradecheck"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
@@ -119,6 +121,32 @@ func initManager() (runtime.Options, error) {
 	return options, nil
 }
 
+// initCertificatePolicy returns the keys and lifetimes of certificates that
+// the operator issues. PostgresClusters can override each of these.
+func initCertificatePolicy() (pki.Policy, error) {
+	var policy pki.Policy
+	var err error
+
+	s := os.Getenv("PGO_CERTIFICATE_KEY_ALGORITHM")
+	if policy.KeyAlgorithm, err = pki.ParseKeyAlgorithm(s); err != nil {
+		return policy, fmt.Errorf("value for PGO_CERTIFICATE_KEY_ALGORITHM is invalid: %w", err)
+	}
+
+	if s := os.Getenv("PGO_CERTIFICATE_LEAF_LIFETIME"); s != "" {
+		if policy.LeafLifetime, err = time.ParseDuration(s); err != nil || policy.LeafLifetime <= 0 {
+			return policy, fmt.Errorf("value for PGO_CERTIFICATE_LEAF_LIFETIME is invalid: %q", s)
+		}
+	}
+
+	if s := os.Getenv("PGO_CERTIFICATE_RENEW_BEFORE"); s != "" {
+		if policy.RenewBefore, err = time.ParseDuration(s); err != nil || policy.RenewBefore <= 0 {
+			return policy, fmt.Errorf("value for PGO_CERTIFICATE_RENEW_BEFORE is invalid: %q", s)
+		}
+	}
+
+	return policy, nil
+}
+
 func main() {
 	// This context is canceled by SIGINT, SIGTERM, or by calling shutdown.
 	ctx, shutdown := context.WithCancel(runtime.SignalHandler())
@@ -170,8 +198,11 @@ func main() {
 	assertNoError(mgr.Add(registrar))
 	token, _ := registrar.CheckToken()
 
+	certificates, err := initCertificatePolicy()
+	assertNoError(err)
+
 	// add all PostgreSQL Operator controllers to the runtime manager
-	addControllersToManager(mgr, openshift, log, registrar)
+	addControllersToManager(mgr, openshift, log, registrar, certificates)
 
 	if features.Enabled(feature.BridgeIdentifiers) {
 		constructor := func() *bridge.Client {
@@ -212,14 +243,18 @@ func main() {
 
 // addControllersToManager adds all PostgreSQL Operator controllers to the provided controller
 // runtime manager.
-func addControllersToManager(mgr runtime.Manager, openshift bool, log logging.Logger, reg registration.Registration) {
+func addControllersToManager(
+	mgr runtime.Manager, openshift bool, log logging.Logger, reg registration.Registration,
+	certificates pki.Policy,
+) {
 	pgReconciler := &postgrescluster.Reconciler{
-		Client:       mgr.GetClient(),
-		IsOpenShift:  openshift,
-		Owner:        postgrescluster.ControllerName,
-		Recorder:     mgr.GetEventRecorderFor(postgrescluster.ControllerName),
-		Registration: reg,
-		Tracer:       otel.Tracer(postgrescluster.ControllerName),
+		CertificatePolicy: certificates,
+		Client:            mgr.GetClient(),
+		IsOpenShift:       openshift,
+		Owner:             postgrescluster.ControllerName,
+		Recorder:          mgr.GetEventRecorderFor(postgrescluster.ControllerName),
+		Registration:      reg,
+		Tracer:            otel.Tracer(postgrescluster.ControllerName),
 	}
 
 	if err := pgReconciler.SetupWithManager(mgr); err != nil {
@@ -239,6 +274,17 @@ func addControllersToManager(mgr runtime.Manager, openshift bool, log logging.Lo
 		os.Exit(1)
 	}
 
+	switchoverReconciler := &pgswitchover.PGSwitchoverReconciler{
+		Client:   mgr.GetClient(),
+		Owner:    "pgswitchover-controller",
+		Recorder: mgr.GetEventRecorderFor("pgswitchover-controller"),
+	}
+
+	if err := switchoverReconciler.SetupWithManager(mgr); err != nil {
+		log.Error(err, "unable to create PGSwitchover controller")
+		os.Exit(1)
+	}
+
 	pgAdminReconciler := &standalone_pgadmin.PGAdminReconciler{
 		Client:      mgr.GetClient(),
 		Owner:       "pgadmin-controller",
@@ -116,3 +116,37 @@ func TestInitManager(t *testing.T) {
 		})
 	})
 }
+
+func TestInitCertificatePolicy(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		policy, err := initCertificatePolicy()
+		assert.NilError(t, err)
+		assert.Assert(t, reflect.ValueOf(policy).IsZero(), "got %+v", policy)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Setenv("PGO_CERTIFICATE_KEY_ALGORITHM", "RSA-4096")
+		t.Setenv("PGO_CERTIFICATE_LEAF_LIFETIME", "2160h")
+		t.Setenv("PGO_CERTIFICATE_RENEW_BEFORE", "720h")
+
+		policy, err := initCertificatePolicy()
+		assert.NilError(t, err)
+		assert.Equal(t, string(policy.KeyAlgorithm), "RSA-4096")
+		assert.Equal(t, policy.LeafLifetime, 90*24*time.Hour)
+		assert.Equal(t, policy.RenewBefore, 30*24*time.Hour)
+	})
+
+	for _, tt := range []struct{ name, value string }{
+		{"PGO_CERTIFICATE_KEY_ALGORITHM", "DSA"},
+		{"PGO_CERTIFICATE_LEAF_LIFETIME", "forever"},
+		{"PGO_CERTIFICATE_LEAF_LIFETIME", "-1h"},
+		{"PGO_CERTIFICATE_RENEW_BEFORE", "0s"},
+	} {
+		t.Run(tt.name+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+
+			_, err := initCertificatePolicy()
+			assert.ErrorContains(t, err, tt.name+" is invalid")
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pgswitchover
+
+import (
+	"context"
+	"io"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+const (
+	// AnnotationAllowSwitchover must be on both PostgresClusters of a
+	// switchover with the name of that PGSwitchover as its value.
+	AnnotationAllowSwitchover = "postgres-operator.crunchydata.com/allow-switchover"
+
+	// ConditionProgressing is the type used in a condition to indicate that
+	// a switchover is in progress.
+	ConditionProgressing = "Progressing"
+
+	// ConditionSucceeded is the type used in a condition to indicate that the
+	// target cluster is primary and the source cluster follows it.
+	ConditionSucceeded = "Succeeded"
+
+	// ConditionWritesStopped is the type used in a condition to indicate that
+	// the source cluster is a standby that no longer accepts writes.
+	ConditionWritesStopped = "WritesStopped"
+
+	// ConditionTargetCaughtUp is the type used in a condition to indicate that
+	// the target cluster has replayed all the WAL of the source cluster.
+	ConditionTargetCaughtUp = "TargetCaughtUp"
+
+	// ConditionTargetPromoted is the type used in a condition to indicate that
+	// the target cluster is primary.
+	ConditionTargetPromoted = "TargetPromoted"
+
+	// ConditionSourceFollowing is the type used in a condition to indicate that
+	// the source cluster is replaying WAL of the target cluster.
+	ConditionSourceFollowing = "SourceFollowing"
+)
+
+// PGSwitchoverReconciler reconciles a PGSwitchover object
+type PGSwitchoverReconciler struct {
+	Client  client.Client
+	Owner   client.FieldOwner
+	PodExec func(
+		ctx context.Context, namespace, pod, container string,
+		stdin io.Reader, stdout, stderr io.Writer, command ...string,
+	) error
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups="postgres-operator.crunchydata.com",resources="pgswitchovers",verbs={list,watch}
+//+kubebuilder:rbac:groups="postgres-operator.crunchydata.com",resources="postgresclusters",verbs={list,watch}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PGSwitchoverReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.PodExec == nil {
+		var err error
+		r.PodExec, err = runtime.NewPodExecutor(mgr.GetConfig())
+		if err != nil {
+			return err
+		}
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta1.PGSwitchover{}).
+		Watches(
+			v1beta1.NewPostgresCluster(),
+			r.watchPostgresClusters(),
+		).
+		Complete(r)
+}
+
+//+kubebuilder:rbac:groups="postgres-operator.crunchydata.com",resources="pgswitchovers",verbs={list}
+
+// findSwitchoversForPostgresCluster returns PGSwitchovers that have cluster as
+// their source or target.
+func (r *PGSwitchoverReconciler) findSwitchoversForPostgresCluster(
+	ctx context.Context, cluster client.ObjectKey,
+) []*v1beta1.PGSwitchover {
+	var matching []*v1beta1.PGSwitchover
+	var switchovers v1beta1.PGSwitchoverList
+
+	if r.Client.List(ctx, &switchovers, &client.ListOptions{
+		Namespace: cluster.Namespace,
+	}) == nil {
+		for i := range switchovers.Items {
+			if switchovers.Items[i].Spec.SourceClusterName == cluster.Name ||
+				switchovers.Items[i].Spec.TargetClusterName == cluster.Name {
+				matching = append(matching, &switchovers.Items[i])
+			}
+		}
+	}
+	return matching
+}
+
+// watchPostgresClusters returns a [handler.EventHandler] for PostgresClusters.
+func (r *PGSwitchoverReconciler) watchPostgresClusters() handler.Funcs {
+	handle := func(ctx context.Context, cluster client.Object, q workqueue.RateLimitingInterface) {
+		key := client.ObjectKeyFromObject(cluster)
+
+		for _, switchover := range r.findSwitchoversForPostgresCluster(ctx, key) {
+			q.Add(ctrl.Request{
+				NamespacedName: client.ObjectKeyFromObject(switchover),
+			})
+		}
+	}
+
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.RateLimitingInterface) {
+			handle(ctx, e.Object, q)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+			handle(ctx, e.ObjectNew, q)
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+			handle(ctx, e.Object, q)
+		},
+	}
+}
+
+//+kubebuilder:rbac:groups="postgres-operator.crunchydata.com",resources="pgswitchovers",verbs={get}
+//+kubebuilder:rbac:groups="postgres-operator.crunchydata.com",resources="pgswitchovers/status",verbs={patch}
+
+// Reconcile does the work to move the current state of the world toward the
+// desired state described in a [v1beta1.PGSwitchover] identified by req.
+func (r *PGSwitchoverReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Retrieve the switchover from the client cache, if it exists. A deferred
+	// function below will send any changes to its Status field.
+	//
+	// NOTE: No DeepCopy is necessary here because controller-runtime makes a
+	// copy before returning from its cache.
+	// - https://github.com/kubernetes-sigs/controller-runtime/issues/1235
+	switchover := &v1beta1.PGSwitchover{}
+	err = r.Client.Get(ctx, req.NamespacedName, switchover)
+
+	if err == nil {
+		// Write any changes to the switchover status on the way out.
+		before := switchover.DeepCopy()
+		defer func() {
+			if !equality.Semantic.DeepEqual(before.Status, switchover.Status) {
+				status := r.Client.Status().Patch(ctx, switchover, client.MergeFrom(before), r.Owner)
+
+				if err == nil && status != nil {
+					err = status
+				} else if status != nil {
+					log.Error(status, "Patching PGSwitchover status")
+				}
+			}
+		}()
+	} else {
+		// NotFound cannot be fixed by requeuing so ignore it.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// A switchover happens once. Delete it and create another to switch back.
+	if meta.IsStatusConditionTrue(switchover.Status.Conditions, ConditionSucceeded) {
+		return ctrl.Result{}, nil
+	}
+
+	result, err = r.reconcileSwitchover(ctx, switchover)
+	switchover.Status.ObservedGeneration = switchover.GetGeneration()
+
+	log.V(1).Info("Reconciled", "requeue", !result.IsZero() || err != nil)
+	return result, err
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pgswitchover
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestFindSwitchoversForPostgresCluster(t *testing.T) {
+	ctx := context.Background()
+
+	switchover := func(namespace, name, source, target string) *v1beta1.PGSwitchover {
+		s := &v1beta1.PGSwitchover{}
+		s.Namespace, s.Name = namespace, name
+		s.Spec.SourceClusterName, s.Spec.TargetClusterName = source, target
+		return s
+	}
+
+	reconciler := &PGSwitchoverReconciler{
+		Client: fake.NewClientBuilder().WithScheme(runtime.Scheme).WithObjects(
+			switchover("ns1", "away", "east", "west"),
+			switchover("ns1", "back", "west", "east"),
+			switchover("ns1", "other", "north", "south"),
+			switchover("ns2", "elsewhere", "east", "west"),
+		).Build(),
+	}
+
+	var names []string
+	for _, s := range reconciler.findSwitchoversForPostgresCluster(ctx,
+		client.ObjectKey{Namespace: "ns1", Name: "east"}) {
+		names = append(names, s.Name)
+	}
+	assert.DeepEqual(t, names, []string{"away", "back"})
+
+	assert.Equal(t, len(reconciler.findSwitchoversForPostgresCluster(ctx,
+		client.ObjectKey{Namespace: "ns1", Name: "central"})), 0)
+}
@@ -0,0 +1,366 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pgswitchover
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/patroni"
+	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// pollInterval is how long to wait before checking on steps that do not
+// cause any events, such as replaying WAL.
+const pollInterval = 10 * time.Second
+
+// participant is one of the PostgresClusters in a switchover.
+type participant struct {
+	Cluster *v1beta1.PostgresCluster
+
+	// The Pod of the Patroni leader when PostgreSQL is running there.
+	Leader *corev1.Pod
+}
+
+// IsPrimary returns whether or not the leader of p is a primary.
+func (p *participant) IsPrimary() bool {
+	return p.Leader != nil && patroni.PodIsPrimary(p.Leader)
+}
+
+// IsStandbyLeader returns whether or not the leader of p is a standby leader.
+func (p *participant) IsStandbyLeader() bool {
+	return p.Leader != nil && patroni.PodIsStandbyLeader(p.Leader)
+}
+
+// The client used by the controller sets up a cache and an informer for any GVK
+// that it GETs. That informer needs the "watch" permission.
+// - https://github.com/kubernetes-sigs/controller-runtime/issues/1249
+// - https://github.com/kubernetes-sigs/controller-runtime/issues/1454
+//+kubebuilder:rbac:groups="postgres-operator.crunchydata.com",resources="postgresclusters",verbs={get,watch}
+//+kubebuilder:rbac:groups="",resources="pods",verbs={list,watch}
+
+// observeCluster returns the PostgresCluster named name and its Patroni leader.
+// It returns nil when the cluster does not exist.
+func (r *PGSwitchoverReconciler) observeCluster(
+	ctx context.Context, namespace, name string,
+) (*participant, error) {
+	cluster := v1beta1.NewPostgresCluster()
+	err := errors.WithStack(
+		r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, cluster))
+
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	result := &participant{Cluster: cluster}
+	if err == nil {
+		var pods corev1.PodList
+		err = errors.WithStack(
+			r.Client.List(ctx, &pods,
+				client.InNamespace(namespace),
+				client.MatchingLabels{
+					naming.LabelCluster: name,
+					naming.LabelRole:    naming.RolePatroniLeader,
+				},
+			))
+
+		for i := range pods.Items {
+			if pod := &pods.Items[i]; pod.DeletionTimestamp == nil {
+				for _, status := range pod.Status.ContainerStatuses {
+					if status.Name == naming.ContainerDatabase && status.State.Running != nil {
+						result.Leader = pod
+					}
+				}
+			}
+		}
+	}
+
+	return result, err
+}
+
+//+kubebuilder:rbac:groups="",resources="pods/exec",verbs={create}
+
+// executor returns a function that runs commands in the database container
+// of pod.
+func (r *PGSwitchoverReconciler) executor(pod *corev1.Pod) postgres.Executor {
+	return func(
+		ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+	) error {
+		return r.PodExec(ctx, pod.Namespace, pod.Name, naming.ContainerDatabase,
+			stdin, stdout, stderr, command...)
+	}
+}
+
+//+kubebuilder:rbac:groups="postgres-operator.crunchydata.com",resources="postgresclusters",verbs={patch}
+
+// patchStandby changes the standby specification of cluster to standby.
+func (r *PGSwitchoverReconciler) patchStandby(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, standby *v1beta1.PostgresStandbySpec,
+) error {
+	before := cluster.DeepCopy()
+	cluster.Spec.Standby = standby
+
+	return errors.WithStack(r.Client.Patch(ctx, cluster, client.MergeFrom(before), r.Owner))
+}
+
+// followSpec returns the standby specification that makes a cluster stream
+// from target through its primary Service. That Service sends traffic to the
+// Patroni leader of target, whether it is a standby or not.
+func followSpec(target *v1beta1.PostgresCluster) *v1beta1.PostgresStandbySpec {
+	service := naming.ClusterPrimaryService(target)
+
+	standby := &v1beta1.PostgresStandbySpec{
+		Enabled: true,
+		Host:    service.Name + "." + service.Namespace + ".svc",
+	}
+	if target.Spec.Port != nil {
+		standby.Port = initialize.Pointer(*target.Spec.Port)
+	}
+	return standby
+}
+
+// validateSwitchover returns the reason and message of a condition explaining
+// why switchover cannot start with source and target. The reason is empty
+// when it can.
+func validateSwitchover(
+	switchover *v1beta1.PGSwitchover, source, target *participant,
+) (string, string) {
+	sourceName, targetName := source.Cluster.Name, target.Cluster.Name
+
+	// Each switchover names two clusters, but we also want to ensure that each
+	// cluster is managed by at most one switchover. Having an annotation on
+	// the clusters also provides some assurance that the user that created
+	// the switchover has authority to edit those clusters.
+	for _, cluster := range []*v1beta1.PostgresCluster{source.Cluster, target.Cluster} {
+		if cluster.GetAnnotations()[AnnotationAllowSwitchover] != switchover.Name {
+			return "PGClusterMissingRequiredAnnotation", fmt.Sprintf(
+				"PostgresCluster %s lacks annotation for switchover %s",
+				cluster.Name, switchover.Name)
+		}
+	}
+
+	if standby := source.Cluster.Spec.Standby; standby != nil && standby.Enabled {
+		return "SwitchoverInvalid", fmt.Sprintf(
+			"PostgresCluster %s is a standby", sourceName)
+	}
+
+	// The source cannot archive WAL after it stops accepting writes, so the
+	// target must stream the last of it.
+	if standby := target.Cluster.Spec.Standby; standby == nil || !standby.Enabled || standby.Host == "" {
+		return "SwitchoverInvalid", fmt.Sprintf(
+			"PostgresCluster %s is not a streaming standby", targetName)
+	}
+
+	if id := source.Cluster.Status.Patroni.SystemIdentifier; id == "" ||
+		id != target.Cluster.Status.Patroni.SystemIdentifier {
+		return "SwitchoverInvalid", fmt.Sprintf(
+			"PostgresCluster %s is not a copy of %s", targetName, sourceName)
+	}
+
+	if !source.IsPrimary() {
+		return "PGClusterNotReady", fmt.Sprintf(
+			"PostgresCluster %s has no running primary", sourceName)
+	}
+
+	if !target.IsStandbyLeader() {
+		return "PGClusterNotReady", fmt.Sprintf(
+			"PostgresCluster %s has no running standby leader", targetName)
+	}
+
+	return "", ""
+}
+
+// reconcileSwitchover moves switchover through its steps: it stops writes on
+// the source cluster by making it a standby of the target cluster, waits for
+// the target to replay the last of its WAL, promotes the target, and waits
+// for the source to replay WAL from the target. Each step is reported in
+// a condition of its own.
+func (r *PGSwitchoverReconciler) reconcileSwitchover(
+	ctx context.Context, switchover *v1beta1.PGSwitchover,
+) (ctrl.Result, error) {
+	before := switchover.DeepCopy()
+	conditions := &switchover.Status.Conditions
+	setCondition := func(kind string, status metav1.ConditionStatus, reason, message string) {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			ObservedGeneration: switchover.GetGeneration(),
+			Type:               kind,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+
+	source, err := r.observeCluster(ctx, switchover.Namespace, switchover.Spec.SourceClusterName)
+	var target *participant
+	if err == nil {
+		target, err = r.observeCluster(ctx, switchover.Namespace, switchover.Spec.TargetClusterName)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// ClusterNotFound cannot be fixed by requeuing. We will reconcile again
+	// when a matching PostgresCluster is created.
+	if source == nil || target == nil {
+		name := switchover.Spec.SourceClusterName
+		if source != nil {
+			name = switchover.Spec.TargetClusterName
+		}
+
+		setCondition(ConditionProgressing, metav1.ConditionFalse, "PGClusterNotFound",
+			fmt.Sprintf("PostgresCluster %s not found", name))
+
+		return ctrl.Result{}, nil
+	}
+
+	sourceName, targetName := source.Cluster.Name, target.Cluster.Name
+
+	// Check the clusters only before the first step. After that, they are
+	// expected to be in the middle of changing roles.
+	if meta.FindStatusCondition(*conditions, ConditionWritesStopped) == nil {
+		if reason, message := validateSwitchover(switchover, source, target); reason != "" {
+			setCondition(ConditionProgressing, metav1.ConditionFalse, reason, message)
+
+			// Instances that are starting do not change either PostgresCluster.
+			if reason == "PGClusterNotReady" {
+				return ctrl.Result{RequeueAfter: pollInterval}, nil
+			}
+			return ctrl.Result{}, nil
+		}
+	}
+
+	setCondition(ConditionProgressing, metav1.ConditionTrue, "SwitchoverProgressing",
+		fmt.Sprintf("Switching over from %s to %s", sourceName, targetName))
+
+	// Stop writes by making the source a standby of the target. Patroni stops
+	// PostgreSQL cleanly and starts it again in recovery. What it replayed up
+	// to then is the last of its WAL.
+	if !meta.IsStatusConditionTrue(*conditions, ConditionWritesStopped) {
+		setCondition(ConditionWritesStopped, metav1.ConditionFalse, "Demoting",
+			fmt.Sprintf("Waiting for %s to stop writes and follow %s", sourceName, targetName))
+
+		if follow := followSpec(target.Cluster); !equality.Semantic.DeepEqual(
+			source.Cluster.Spec.Standby, follow) {
+			// Record this step before taking it. The source is no longer
+			// valid afterward, and validation does not happen again once
+			// this condition exists.
+			if err := errors.WithStack(r.Client.Status().Patch(
+				ctx, switchover, client.MergeFrom(before), r.Owner)); err != nil {
+				return ctrl.Result{}, err
+			}
+			if err := r.patchStandby(ctx, source.Cluster, follow); err != nil {
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Eventf(switchover, corev1.EventTypeNormal, "Demoting",
+				"Demoting %s to a standby of %s", sourceName, targetName)
+		}
+
+		if !source.IsStandbyLeader() {
+			return ctrl.Result{RequeueAfter: pollInterval}, nil
+		}
+
+		lsn, _, err := postgres.WALPosition(ctx, r.executor(source.Leader), "")
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		switchover.Status.FinalLSN = lsn
+		setCondition(ConditionWritesStopped, metav1.ConditionTrue, "Demoted",
+			fmt.Sprintf("%s stopped writes at %s", sourceName, lsn))
+	}
+
+	final := switchover.Status.FinalLSN
+
+	// Wait for the target to replay all the WAL of the source.
+	if !meta.IsStatusConditionTrue(*conditions, ConditionTargetCaughtUp) {
+		setCondition(ConditionTargetCaughtUp, metav1.ConditionFalse, "CatchingUp",
+			fmt.Sprintf("Waiting for %s to replay WAL to %s", targetName, final))
+
+		if target.Leader == nil {
+			return ctrl.Result{RequeueAfter: pollInterval}, nil
+		}
+
+		lsn, past, err := postgres.WALPosition(ctx, r.executor(target.Leader), final)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if past < 0 {
+			setCondition(ConditionTargetCaughtUp, metav1.ConditionFalse, "CatchingUp",
+				fmt.Sprintf("%s replayed WAL to %s of %s", targetName, lsn, final))
+
+			return ctrl.Result{RequeueAfter: pollInterval}, nil
+		}
+
+		setCondition(ConditionTargetCaughtUp, metav1.ConditionTrue, "CaughtUp",
+			fmt.Sprintf("%s replayed WAL to %s", targetName, lsn))
+	}
+
+	// Promote the target by making it no longer a standby.
+	if !meta.IsStatusConditionTrue(*conditions, ConditionTargetPromoted) {
+		setCondition(ConditionTargetPromoted, metav1.ConditionFalse, "Promoting",
+			fmt.Sprintf("Waiting for %s to become primary", targetName))
+
+		if standby := target.Cluster.Spec.Standby; standby != nil && standby.Enabled {
+			promoted := standby.DeepCopy()
+			promoted.Enabled = false
+
+			if err := r.patchStandby(ctx, target.Cluster, promoted); err != nil {
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Eventf(switchover, corev1.EventTypeNormal, "Promoting",
+				"Promoting %s to primary", targetName)
+		}
+
+		if !target.IsPrimary() {
+			return ctrl.Result{RequeueAfter: pollInterval}, nil
+		}
+
+		setCondition(ConditionTargetPromoted, metav1.ConditionTrue, "Promoted",
+			fmt.Sprintf("%s is primary", targetName))
+	}
+
+	// The promoted target writes WAL on a new timeline. The source follows it
+	// once it replays past the last of its own WAL.
+	setCondition(ConditionSourceFollowing, metav1.ConditionFalse, "Waiting",
+		fmt.Sprintf("Waiting for %s to replay WAL from %s", sourceName, targetName))
+
+	if source.Leader == nil {
+		return ctrl.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	lsn, past, err := postgres.WALPosition(ctx, r.executor(source.Leader), final)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if past <= 0 {
+		return ctrl.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	setCondition(ConditionSourceFollowing, metav1.ConditionTrue, "Following",
+		fmt.Sprintf("%s replayed WAL from %s to %s", sourceName, targetName, lsn))
+	setCondition(ConditionProgressing, metav1.ConditionFalse, "SwitchoverCompleted",
+		fmt.Sprintf("Switched over from %s to %s", sourceName, targetName))
+	setCondition(ConditionSucceeded, metav1.ConditionTrue, "SwitchoverSucceeded",
+		fmt.Sprintf("%s is primary and %s follows it", targetName, sourceName))
+
+	r.Recorder.Eventf(switchover, corev1.EventTypeNormal, "SwitchoverSucceeded",
+		"Switched over from %s to %s", sourceName, targetName)
+
+	return ctrl.Result{}, nil
+}
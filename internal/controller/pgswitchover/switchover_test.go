@@ -0,0 +1,379 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pgswitchover
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/internal/testing/events"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// leaderPod returns a running Patroni leader of cluster in role.
+func leaderPod(cluster, role string) *corev1.Pod {
+	pod := &corev1.Pod{}
+	pod.Namespace, pod.Name = "ns1", cluster+"-00-abcd-0"
+	pod.Labels = map[string]string{
+		naming.LabelCluster: cluster,
+		naming.LabelRole:    naming.RolePatroniLeader,
+	}
+	pod.Annotations = map[string]string{"status": `{"role":"` + role + `"}`}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  naming.ContainerDatabase,
+		State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+	}}
+	return pod
+}
+
+func TestFollowSpec(t *testing.T) {
+	target := v1beta1.NewPostgresCluster()
+	target.Namespace, target.Name = "ns1", "west"
+
+	assert.DeepEqual(t, followSpec(target), &v1beta1.PostgresStandbySpec{
+		Enabled: true, Host: "west-primary.ns1.svc",
+	})
+
+	target.Spec.Port = initialize.Int32(5433)
+	assert.DeepEqual(t, followSpec(target), &v1beta1.PostgresStandbySpec{
+		Enabled: true, Host: "west-primary.ns1.svc", Port: initialize.Int32(5433),
+	})
+}
+
+func TestValidateSwitchover(t *testing.T) {
+	setup := func() (*v1beta1.PGSwitchover, *participant, *participant) {
+		switchover := &v1beta1.PGSwitchover{}
+		switchover.Name = "move"
+
+		source := &participant{Cluster: v1beta1.NewPostgresCluster(), Leader: leaderPod("east", "master")}
+		source.Cluster.Name = "east"
+		source.Cluster.Annotations = map[string]string{AnnotationAllowSwitchover: "move"}
+		source.Cluster.Status.Patroni.SystemIdentifier = "7000"
+
+		target := &participant{Cluster: v1beta1.NewPostgresCluster(), Leader: leaderPod("west", "standby_leader")}
+		target.Cluster.Name = "west"
+		target.Cluster.Annotations = map[string]string{AnnotationAllowSwitchover: "move"}
+		target.Cluster.Spec.Standby = &v1beta1.PostgresStandbySpec{Enabled: true, Host: "east.example.com"}
+		target.Cluster.Status.Patroni.SystemIdentifier = "7000"
+
+		return switchover, source, target
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		reason, message := validateSwitchover(setup())
+		assert.Equal(t, reason, "")
+		assert.Equal(t, message, "")
+	})
+
+	for _, tt := range []struct {
+		name, reason, message string
+		mutate                func(*participant, *participant)
+	}{
+		{
+			name: "SourceAnnotation", reason: "PGClusterMissingRequiredAnnotation",
+			message: "PostgresCluster east lacks annotation for switchover move",
+			mutate: func(source, _ *participant) {
+				source.Cluster.Annotations[AnnotationAllowSwitchover] = "other"
+			},
+		},
+		{
+			name: "TargetAnnotation", reason: "PGClusterMissingRequiredAnnotation",
+			message: "PostgresCluster west lacks annotation for switchover move",
+			mutate: func(_, target *participant) {
+				target.Cluster.Annotations = nil
+			},
+		},
+		{
+			name: "SourceStandby", reason: "SwitchoverInvalid",
+			message: "PostgresCluster east is a standby",
+			mutate: func(source, _ *participant) {
+				source.Cluster.Spec.Standby = &v1beta1.PostgresStandbySpec{Enabled: true, RepoName: "repo1"}
+			},
+		},
+		{
+			name: "TargetPrimary", reason: "SwitchoverInvalid",
+			message: "PostgresCluster west is not a streaming standby",
+			mutate: func(_, target *participant) {
+				target.Cluster.Spec.Standby.Enabled = false
+			},
+		},
+		{
+			name: "TargetRepository", reason: "SwitchoverInvalid",
+			message: "PostgresCluster west is not a streaming standby",
+			mutate: func(_, target *participant) {
+				target.Cluster.Spec.Standby = &v1beta1.PostgresStandbySpec{Enabled: true, RepoName: "repo1"}
+			},
+		},
+		{
+			name: "SystemIdentifier", reason: "SwitchoverInvalid",
+			message: "PostgresCluster west is not a copy of east",
+			mutate: func(_, target *participant) {
+				target.Cluster.Status.Patroni.SystemIdentifier = "7001"
+			},
+		},
+		{
+			name: "SourceNotRunning", reason: "PGClusterNotReady",
+			message: "PostgresCluster east has no running primary",
+			mutate: func(source, _ *participant) {
+				source.Leader = nil
+			},
+		},
+		{
+			name: "TargetNotRunning", reason: "PGClusterNotReady",
+			message: "PostgresCluster west has no running standby leader",
+			mutate: func(_, target *participant) {
+				target.Leader = leaderPod("west", "replica")
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			switchover, source, target := setup()
+			tt.mutate(source, target)
+
+			reason, message := validateSwitchover(switchover, source, target)
+			assert.Equal(t, reason, tt.reason)
+			assert.Equal(t, message, tt.message)
+		})
+	}
+}
+
+func TestReconcileSwitchover(t *testing.T) {
+	ctx := context.Background()
+
+	switchover := &v1beta1.PGSwitchover{}
+	switchover.Namespace, switchover.Name = "ns1", "move"
+	switchover.Spec.SourceClusterName = "east"
+	switchover.Spec.TargetClusterName = "west"
+
+	source := v1beta1.NewPostgresCluster()
+	source.Namespace, source.Name = "ns1", "east"
+	source.Annotations = map[string]string{AnnotationAllowSwitchover: "move"}
+	source.Status.Patroni.SystemIdentifier = "7000"
+
+	target := v1beta1.NewPostgresCluster()
+	target.Namespace, target.Name = "ns1", "west"
+	target.Annotations = map[string]string{AnnotationAllowSwitchover: "move"}
+	target.Spec.Standby = &v1beta1.PostgresStandbySpec{Enabled: true, Host: "east.example.com"}
+	target.Status.Patroni.SystemIdentifier = "7000"
+
+	sourcePod, targetPod := leaderPod("east", "master"), leaderPod("west", "standby_leader")
+
+	cc := fake.NewClientBuilder().WithScheme(runtime.Scheme).
+		WithStatusSubresource(&v1beta1.PGSwitchover{}).
+		WithObjects(switchover, source, target, sourcePod, targetPod).
+		Build()
+
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &PGSwitchoverReconciler{Client: cc, Owner: "test", Recorder: recorder}
+
+	// The WAL position of each Pod as psql prints it.
+	positions := map[string]string{}
+	reconciler.PodExec = func(
+		ctx context.Context, namespace, pod, container string,
+		stdin io.Reader, stdout, _ io.Writer, command ...string,
+	) error {
+		assert.Equal(t, container, naming.ContainerDatabase)
+		assert.Assert(t, strings.Contains(strings.Join(command, " "), "psql"))
+		_, _ = stdout.Write([]byte(positions[pod]))
+		return nil
+	}
+
+	reconcile := func(t *testing.T) (ctrl.Result, *v1beta1.PGSwitchover) {
+		t.Helper()
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{
+			NamespacedName: client.ObjectKeyFromObject(switchover),
+		})
+		assert.NilError(t, err)
+
+		latest := &v1beta1.PGSwitchover{}
+		assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(switchover), latest))
+		return result, latest
+	}
+
+	setRole := func(t *testing.T, pod *corev1.Pod, role string) {
+		t.Helper()
+		assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(pod), pod))
+		pod.Annotations["status"] = `{"role":"` + role + `"}`
+		assert.NilError(t, cc.Update(ctx, pod))
+	}
+
+	condition := func(switchover *v1beta1.PGSwitchover, kind string) metav1.Condition {
+		found := meta.FindStatusCondition(switchover.Status.Conditions, kind)
+		assert.Assert(t, found != nil, "missing condition %q", kind)
+		return *found
+	}
+
+	t.Run("Demoting", func(t *testing.T) {
+		result, status := reconcile(t)
+		assert.Equal(t, result.RequeueAfter, pollInterval)
+		assert.Equal(t, condition(status, ConditionProgressing).Status, metav1.ConditionTrue)
+		assert.Equal(t, condition(status, ConditionWritesStopped).Reason, "Demoting")
+
+		assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(source), source))
+		assert.DeepEqual(t, source.Spec.Standby, &v1beta1.PostgresStandbySpec{
+			Enabled: true, Host: "west-primary.ns1.svc",
+		})
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "Demoting")
+		assert.Equal(t, recorder.Events[0].Note, "Demoting east to a standby of west")
+	})
+
+	t.Run("CatchingUp", func(t *testing.T) {
+		setRole(t, sourcePod, "standby_leader")
+		positions[sourcePod.Name] = `{"lsn" : "0/3000148", "past" : 0}`
+		positions[targetPod.Name] = `{"lsn" : "0/3000060", "past" : -232}`
+
+		result, status := reconcile(t)
+		assert.Equal(t, result.RequeueAfter, pollInterval)
+		assert.Equal(t, status.Status.FinalLSN, "0/3000148")
+		assert.Equal(t, condition(status, ConditionWritesStopped).Status, metav1.ConditionTrue)
+		assert.Equal(t, condition(status, ConditionWritesStopped).Message,
+			"east stopped writes at 0/3000148")
+		assert.Equal(t, condition(status, ConditionTargetCaughtUp).Status, metav1.ConditionFalse)
+		assert.Equal(t, condition(status, ConditionTargetCaughtUp).Message,
+			"west replayed WAL to 0/3000060 of 0/3000148")
+	})
+
+	t.Run("Promoting", func(t *testing.T) {
+		positions[targetPod.Name] = `{"lsn" : "0/3000148", "past" : 0}`
+
+		result, status := reconcile(t)
+		assert.Equal(t, result.RequeueAfter, pollInterval)
+		assert.Equal(t, condition(status, ConditionTargetCaughtUp).Status, metav1.ConditionTrue)
+		assert.Equal(t, condition(status, ConditionTargetPromoted).Reason, "Promoting")
+
+		assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(target), target))
+		assert.DeepEqual(t, target.Spec.Standby, &v1beta1.PostgresStandbySpec{
+			Enabled: false, Host: "east.example.com",
+		})
+
+		assert.Equal(t, len(recorder.Events), 2)
+		assert.Equal(t, recorder.Events[1].Reason, "Promoting")
+	})
+
+	t.Run("Following", func(t *testing.T) {
+		setRole(t, targetPod, "master")
+
+		result, status := reconcile(t)
+		assert.Equal(t, result.RequeueAfter, pollInterval)
+		assert.Equal(t, condition(status, ConditionTargetPromoted).Status, metav1.ConditionTrue)
+		assert.Equal(t, condition(status, ConditionSourceFollowing).Status, metav1.ConditionFalse)
+
+		// Validation happens only before the first step.
+		assert.Equal(t, condition(status, ConditionProgressing).Status, metav1.ConditionTrue)
+	})
+
+	t.Run("Succeeded", func(t *testing.T) {
+		positions[sourcePod.Name] = `{"lsn" : "0/3000220", "past" : 216}`
+
+		result, status := reconcile(t)
+		assert.Assert(t, result.IsZero())
+		assert.Equal(t, condition(status, ConditionSourceFollowing).Status, metav1.ConditionTrue)
+		assert.Equal(t, condition(status, ConditionProgressing).Status, metav1.ConditionFalse)
+		assert.Equal(t, condition(status, ConditionProgressing).Reason, "SwitchoverCompleted")
+		assert.Equal(t, condition(status, ConditionSucceeded).Status, metav1.ConditionTrue)
+		assert.Equal(t, condition(status, ConditionSucceeded).Message, "west is primary and east follows it")
+
+		assert.Equal(t, len(recorder.Events), 3)
+		assert.Equal(t, recorder.Events[2].Reason, "SwitchoverSucceeded")
+
+		// Nothing happens after success.
+		delete(positions, sourcePod.Name)
+		result, _ = reconcile(t)
+		assert.Assert(t, result.IsZero())
+		assert.Equal(t, len(recorder.Events), 3)
+	})
+}
+
+func TestReconcileSwitchoverNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	switchover := &v1beta1.PGSwitchover{}
+	switchover.Namespace, switchover.Name = "ns1", "move"
+	switchover.Spec.SourceClusterName = "east"
+	switchover.Spec.TargetClusterName = "west"
+
+	source := v1beta1.NewPostgresCluster()
+	source.Namespace, source.Name = "ns1", "east"
+
+	reconciler := &PGSwitchoverReconciler{
+		Client: fake.NewClientBuilder().WithScheme(runtime.Scheme).WithObjects(source).Build(),
+	}
+
+	result, err := reconciler.reconcileSwitchover(ctx, switchover)
+	assert.NilError(t, err)
+	assert.Assert(t, result.IsZero())
+
+	progressing := meta.FindStatusCondition(switchover.Status.Conditions, ConditionProgressing)
+	assert.Assert(t, progressing != nil)
+	assert.Equal(t, progressing.Status, metav1.ConditionFalse)
+	assert.Equal(t, progressing.Reason, "PGClusterNotFound")
+	assert.Assert(t, cmp.Contains(progressing.Message, "west"))
+}
+
+func TestReconcileSwitchoverRecordsDemotion(t *testing.T) {
+	ctx := context.Background()
+
+	switchover := &v1beta1.PGSwitchover{}
+	switchover.Namespace, switchover.Name = "ns1", "move"
+	switchover.Spec.SourceClusterName = "east"
+	switchover.Spec.TargetClusterName = "west"
+
+	source := v1beta1.NewPostgresCluster()
+	source.Namespace, source.Name = "ns1", "east"
+	source.Annotations = map[string]string{AnnotationAllowSwitchover: "move"}
+	source.Status.Patroni.SystemIdentifier = "7000"
+
+	target := v1beta1.NewPostgresCluster()
+	target.Namespace, target.Name = "ns1", "west"
+	target.Annotations = map[string]string{AnnotationAllowSwitchover: "move"}
+	target.Spec.Standby = &v1beta1.PostgresStandbySpec{Enabled: true, Host: "east.example.com"}
+	target.Status.Patroni.SystemIdentifier = "7000"
+
+	// The status of the switchover cannot be written.
+	expected := errors.New("status unavailable")
+	cc := fake.NewClientBuilder().WithScheme(runtime.Scheme).
+		WithStatusSubresource(&v1beta1.PGSwitchover{}).
+		WithObjects(switchover, source, target,
+			leaderPod("east", "master"), leaderPod("west", "standby_leader")).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(
+				context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption,
+			) error {
+				return expected
+			},
+		}).
+		Build()
+
+	reconciler := &PGSwitchoverReconciler{
+		Client: cc, Owner: "test", Recorder: events.NewRecorder(t, runtime.Scheme),
+	}
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{
+		NamespacedName: client.ObjectKeyFromObject(switchover),
+	})
+	assert.ErrorIs(t, err, expected)
+
+	// The source is not demoted until the switchover records that it will be.
+	assert.NilError(t, cc.Get(ctx, client.ObjectKeyFromObject(source), source))
+	assert.Assert(t, source.Spec.Standby == nil)
+}
@@ -7,6 +7,7 @@ package pgupgrade
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	batchv1 "k8s.io/api/batch/v1"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/crunchydata/postgres-operator/internal/config"
 	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/internal/maintenance"
 	"github.com/crunchydata/postgres-operator/internal/registration"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
@@ -407,6 +409,35 @@ func (r *PGUpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, nil
 	}
 
+	// Upgrades are disruptive, so they start only during a maintenance window
+	// of the cluster. Once started, they continue regardless of windows.
+	if open, next, _ := maintenance.Status(
+		world.Cluster.Spec.MaintenanceWindows, time.Now(),
+	); upgradeJob == nil && !open {
+		message := "Waiting for a maintenance window of PostgresCluster " +
+			upgrade.Spec.PostgresClusterName
+		if !next.IsZero() {
+			message = fmt.Sprintf("Waiting until %s for a maintenance window of PostgresCluster %s",
+				next.UTC().Format(time.RFC3339), upgrade.Spec.PostgresClusterName)
+		}
+		meta.SetStatusCondition(&upgrade.Status.Conditions, metav1.Condition{
+			ObservedGeneration: upgrade.Generation,
+			Type:               ConditionPGUpgradeProgressing,
+			Status:             metav1.ConditionFalse,
+			Reason:             "PGClusterMaintenanceWindowClosed",
+			Message:            message,
+		})
+
+		// The opening of a window does not cause an event that triggers
+		// another reconcile. Arrive a moment after it opens.
+		if !next.IsZero() {
+			return runtime.RequeueWithoutBackoff(time.Until(next) + time.Second), nil
+		}
+		return ctrl.Result{}, nil
+	}
+
+	setStatusToProgressingIfReasonWas("PGClusterMaintenanceWindowClosed", upgrade)
+
 	// If we have reached this point, all preconditions for upgrade are satisfied.
 	// If the jobs have already run to completion
 	// - delete the replica-create jobs to kick off a backup
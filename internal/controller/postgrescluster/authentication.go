@@ -0,0 +1,151 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// +kubebuilder:rbac:groups="",resources="secrets",verbs={get}
+
+// setPostgresHBARules appends the rules of cluster.Spec.Authentication to the
+// mandatory rules of outHBAs so that rules required by the operator are
+// matched first. The default rules are removed when there are any. Rules that
+// cannot work or that reference missing Secrets are skipped with a warning
+// event. Rules with secret options are returned as files, by name, and
+// outHBAs includes those files in their place.
+func (r *Reconciler) setPostgresHBARules(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, outHBAs *postgres.HBAs,
+) (map[string]string, error) {
+	if cluster.Spec.Authentication == nil || len(cluster.Spec.Authentication.Rules) == 0 {
+		return nil, nil
+	}
+
+	secrets := make(map[string]*corev1.Secret)
+	lookup := func(ref corev1.SecretKeySelector) ([]byte, error) {
+		if _, ok := secrets[ref.Name]; !ok {
+			secret := &corev1.Secret{}
+			err := errors.WithStack(r.Client.Get(ctx,
+				client.ObjectKey{Namespace: cluster.Namespace, Name: ref.Name}, secret))
+			if apierrors.IsNotFound(err) {
+				secret = nil
+			} else if err != nil {
+				return nil, err
+			}
+			secrets[ref.Name] = secret
+		}
+		if secret := secrets[ref.Name]; secret != nil {
+			return secret.Data[ref.Key], nil
+		}
+		return nil, nil
+	}
+
+	files := make(map[string]string)
+	var rules []postgres.HostBasedAuthentication
+	for i, rule := range cluster.Spec.Authentication.Rules {
+		path := field.NewPath("spec", "authentication", "rules").Index(i)
+		errs := field.ErrorList{}
+
+		// PostgreSQL 16 is the first to include files in pg_hba.conf.
+		if len(rule.SecretOptions) > 0 && cluster.Spec.PostgresVersion < 16 {
+			errs = append(errs, field.Invalid(path.Child("secretOptions"), len(rule.SecretOptions),
+				"requires PostgreSQL 16 or later"))
+		}
+
+		options := make(map[string]string, len(rule.SecretOptions))
+		for name, ref := range rule.SecretOptions {
+			value, err := lookup(ref)
+			if err != nil {
+				return nil, err
+			}
+			if len(value) == 0 {
+				errs = append(errs, field.Invalid(path.Child("secretOptions").Key(name), ref.Name,
+					"unable to find key "+ref.Key+" of this Secret"))
+			}
+			options[name] = string(value)
+		}
+
+		hba, err := postgres.NewHBAFromSpec(rule, options)
+		if err != nil {
+			errs = append(errs, field.Invalid(path, rule.Method, err.Error()))
+		}
+		if len(errs) > 0 {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidHBARule",
+				errs.ToAggregate().Error())
+			continue
+		}
+
+		// Write rules with secret options to a file named after their spec.
+		// A change in the order of rules changes the names of their files,
+		// so PostgreSQL does not load them until the Pod has the new files.
+		if len(rule.SecretOptions) > 0 {
+			name, err := safeHash32(func(w io.Writer) error {
+				return json.NewEncoder(w).Encode(rule)
+			})
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+
+			name = "pg_hba-" + name + ".conf"
+			files[name] = hba.String() + "\n"
+			hba = postgres.NewHBAInclude(postgres.HBAFilePath(name))
+		}
+		rules = append(rules, *hba)
+	}
+
+	outHBAs.Mandatory = append(outHBAs.Mandatory, rules...)
+	outHBAs.Default = nil
+	return files, nil
+}
+
+// +kubebuilder:rbac:groups="",resources="secrets",verbs={create,delete,patch}
+
+// reconcilePostgresHBASecret writes the Secret that holds pg_hba.conf files
+// for instance Pods. It deletes the Secret when there are no files.
+func (r *Reconciler) reconcilePostgresHBASecret(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, files map[string]string,
+) error {
+	secret := &corev1.Secret{ObjectMeta: naming.PostgresHBASecret(cluster)}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+
+	if len(files) == 0 {
+		// Check the client cache first using Get.
+		err := errors.WithStack(r.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret))
+		if err == nil {
+			err = errors.WithStack(r.deleteControlled(ctx, cluster, secret))
+		}
+		return client.IgnoreNotFound(err)
+	}
+
+	secret.Annotations = naming.Merge(cluster.Spec.Metadata.GetAnnotationsOrNil())
+	secret.Labels = naming.Merge(cluster.Spec.Metadata.GetLabelsOrNil(),
+		map[string]string{
+			naming.LabelCluster: cluster.Name,
+		})
+
+	secret.Type = corev1.SecretTypeOpaque
+	secret.Data = make(map[string][]byte, len(files))
+	for name, content := range files {
+		secret.Data[name] = []byte(content)
+	}
+
+	err := errors.WithStack(r.setControllerReference(cluster, secret))
+	if err == nil {
+		err = errors.WithStack(r.apply(ctx, secret))
+	}
+	return err
+}
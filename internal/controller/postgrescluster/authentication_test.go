@@ -0,0 +1,213 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/internal/testing/events"
+	"github.com/crunchydata/postgres-operator/internal/testing/require"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestSetPostgresHBARules(t *testing.T) {
+	ctx := context.Background()
+
+	directory := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "directory"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	}
+
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &Reconciler{
+		Client:   fake.NewClientBuilder().WithScheme(runtime.Scheme).WithObjects(directory).Build(),
+		Recorder: recorder,
+	}
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	cluster.Spec.PostgresVersion = 16
+
+	printed := func(hbas []postgres.HostBasedAuthentication) []string {
+		result := make([]string, len(hbas))
+		for i := range hbas {
+			result[i] = hbas[i].String()
+		}
+		return result
+	}
+
+	t.Run("NoRules", func(t *testing.T) {
+		hbas := postgres.NewHBAs()
+		files, err := reconciler.setPostgresHBARules(ctx, cluster, &hbas)
+		assert.NilError(t, err)
+		assert.Assert(t, files == nil)
+		assert.DeepEqual(t, printed(hbas.Mandatory), printed(postgres.NewHBAs().Mandatory))
+		assert.DeepEqual(t, printed(hbas.Default), printed(postgres.NewHBAs().Default))
+	})
+
+	t.Run("Rules", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Authentication = &v1beta1.PostgresAuthenticationSpec{
+			Rules: []v1beta1.PostgresHBARuleSpec{
+				{
+					Method:  "ldap",
+					Options: map[string]string{"ldapserver": "ldap.example.com"},
+					SecretOptions: map[string]corev1.SecretKeySelector{
+						"ldapbindpasswd": {
+							LocalObjectReference: corev1.LocalObjectReference{Name: "directory"},
+							Key:                  "password",
+						},
+					},
+				},
+				{Connection: "host", Method: "reject"},
+			},
+		}
+
+		hbas := postgres.NewHBAs()
+		files, err := reconciler.setPostgresHBARules(ctx, cluster, &hbas)
+		assert.NilError(t, err)
+		assert.Assert(t, hbas.Default == nil)
+		assert.Equal(t, len(recorder.Events), 0)
+
+		// The rule with a secret option is in a file rather than Patroni.
+		assert.Equal(t, len(files), 1)
+		var name string
+		for name = range files {
+			assert.Assert(t, cmp.Regexp(`^pg_hba-[^/]+[.]conf$`, name))
+			assert.Equal(t, files[name],
+				`hostssl all all all ldap  ldapbindpasswd="hunter2" ldapserver="ldap.example.com"`+"\n")
+		}
+
+		mandatory := printed(postgres.NewHBAs().Mandatory)
+		assert.DeepEqual(t, printed(hbas.Mandatory), append(mandatory,
+			`include "/pgconf/tls/`+name+`"`,
+			`host all all all reject`,
+		))
+
+		// The name of the file changes with the rule.
+		cluster.Spec.Authentication.Rules[0].Options["ldapport"] = "636"
+		again, err := reconciler.setPostgresHBARules(ctx, cluster, new(postgres.HBAs))
+		assert.NilError(t, err)
+		assert.Equal(t, len(again), 1)
+		_, ok := again[name]
+		assert.Assert(t, !ok)
+	})
+
+	t.Run("OldPostgreSQL", func(t *testing.T) {
+		recorder.Events = nil
+
+		cluster := cluster.DeepCopy()
+		cluster.Spec.PostgresVersion = 15
+		cluster.Spec.Authentication = &v1beta1.PostgresAuthenticationSpec{
+			Rules: []v1beta1.PostgresHBARuleSpec{{
+				Method:  "ldap",
+				Options: map[string]string{"ldapserver": "ldap.example.com"},
+				SecretOptions: map[string]corev1.SecretKeySelector{
+					"ldapbindpasswd": {
+						LocalObjectReference: corev1.LocalObjectReference{Name: "directory"},
+						Key:                  "password",
+					},
+				},
+			}},
+		}
+
+		hbas := postgres.NewHBAs()
+		files, err := reconciler.setPostgresHBARules(ctx, cluster, &hbas)
+		assert.NilError(t, err)
+		assert.Equal(t, len(files), 0)
+		assert.DeepEqual(t, printed(hbas.Mandatory), printed(postgres.NewHBAs().Mandatory))
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "InvalidHBARule")
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "spec.authentication.rules[0].secretOptions"))
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "requires PostgreSQL 16 or later"))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		recorder.Events = nil
+
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Authentication = &v1beta1.PostgresAuthenticationSpec{
+			Rules: []v1beta1.PostgresHBARuleSpec{
+				{
+					Method:  "radius",
+					Options: map[string]string{"radiusservers": "radius.local"},
+					SecretOptions: map[string]corev1.SecretKeySelector{
+						"radiussecrets": {
+							LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+							Key:                  "secret",
+						},
+					},
+				},
+				{Address: "192.168.0.1", Method: "md5"},
+				{Method: "scram-sha-256"},
+			},
+		}
+
+		hbas := postgres.NewHBAs()
+		files, err := reconciler.setPostgresHBARules(ctx, cluster, &hbas)
+		assert.NilError(t, err)
+		assert.Equal(t, len(files), 0)
+
+		mandatory := printed(postgres.NewHBAs().Mandatory)
+		assert.DeepEqual(t, printed(hbas.Mandatory), append(mandatory,
+			`hostssl all all all scram-sha-256`,
+		))
+
+		assert.Equal(t, len(recorder.Events), 2)
+		assert.Equal(t, recorder.Events[0].Reason, "InvalidHBARule")
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "spec.authentication.rules[0].secretOptions[radiussecrets]"))
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "unable to find key secret"))
+		assert.Equal(t, recorder.Events[1].Reason, "InvalidHBARule")
+		assert.Assert(t, cmp.Contains(recorder.Events[1].Note, "spec.authentication.rules[1]"))
+		assert.Assert(t, cmp.Contains(recorder.Events[1].Note, "not in CIDR notation"))
+	})
+}
+
+func TestReconcilePostgresHBASecret(t *testing.T) {
+	ctx := context.Background()
+	_, cc := setupKubernetes(t)
+	require.ParallelCapacity(t, 1)
+
+	reconciler := &Reconciler{Client: cc, Owner: client.FieldOwner(t.Name())}
+
+	ns := setupNamespace(t, cc)
+	cluster := testCluster()
+	cluster.Namespace = ns.Name
+	assert.NilError(t, cc.Create(ctx, cluster))
+	t.Cleanup(func() { assert.Check(t, cc.Delete(ctx, cluster)) })
+
+	key := naming.AsObjectKey(naming.PostgresHBASecret(cluster))
+
+	assert.NilError(t, reconciler.reconcilePostgresHBASecret(ctx, cluster,
+		map[string]string{"pg_hba-x.conf": "hostssl all all all reject\n"}))
+
+	secret := &corev1.Secret{}
+	assert.NilError(t, cc.Get(ctx, key, secret))
+	assert.DeepEqual(t, secret.Data, map[string][]byte{
+		"pg_hba-x.conf": []byte("hostssl all all all reject\n"),
+	})
+	assert.Assert(t, metav1.IsControlledBy(secret, cluster))
+
+	// The Secret is deleted when there are no files.
+	assert.NilError(t, reconciler.reconcilePostgresHBASecret(ctx, cluster, nil))
+	err := cc.Get(ctx, key, secret)
+	assert.Assert(t, apierrors.IsNotFound(err), "expected NotFound, got %v", err)
+
+	// Nothing happens when the Secret does not exist.
+	assert.NilError(t, reconciler.reconcilePostgresHBASecret(ctx, cluster, nil))
+}
@@ -0,0 +1,259 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/pki"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// defaultCertificateExpiryWarning is how long before a certificate expires to
+// report it when cluster.Spec.CertificateExpiryWarning is not set.
+const defaultCertificateExpiryWarning = 30 * 24 * time.Hour
+
+// certificateExpiry reports when each certificate used by a cluster expires.
+// The controller-runtime manager serves this registry on its metrics endpoint.
+var certificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "postgres_operator",
+	Name:      "certificate_expiration_timestamp_seconds",
+	Help:      "The time when a certificate used by a PostgresCluster expires, in seconds since the Unix epoch.",
+}, []string{"namespace", "cluster", "secret", "key", "source"})
+
+func init() {
+	metrics.Registry.MustRegister(certificateExpiry)
+}
+
+// certificateExpiryWarning returns how long before a certificate expires to
+// report it.
+func certificateExpiryWarning(cluster *v1beta1.PostgresCluster) time.Duration {
+	if cluster.Spec.CertificateExpiryWarning != nil {
+		return cluster.Spec.CertificateExpiryWarning.Duration
+	}
+	return defaultCertificateExpiryWarning
+}
+
+// certificateSecretSources returns the source of each Secret that contains
+// certificates used by cluster, other than those the operator labels with
+// the name of cluster.
+func certificateSecretSources(cluster *v1beta1.PostgresCluster) map[string]string {
+	sources := make(map[string]string)
+
+	if cluster.Spec.CertificateIssuer == nil {
+		sources[naming.RootCertSecret] = v1beta1.CertificateSourceOperator
+	} else if issuer := certificateIssuerSecret(cluster); issuer != "" {
+		sources[issuer] = v1beta1.CertificateSourceIssuer
+	}
+
+	custom := []*corev1.SecretProjection{
+		cluster.Spec.CustomTLSSecret,
+		cluster.Spec.CustomReplicationClientTLSSecret,
+	}
+	if cluster.Spec.Proxy != nil && cluster.Spec.Proxy.PGBouncer != nil {
+		custom = append(custom, cluster.Spec.Proxy.PGBouncer.CustomTLSSecret)
+	}
+	for _, projection := range custom {
+		if projection != nil {
+			sources[projection.Name] = v1beta1.CertificateSourceCustom
+		}
+	}
+
+	return sources
+}
+
+// certificateStatuses returns the certificates in secret sorted by key. Each
+// key reports the certificate in it that expires first. Secrets written by
+// the operator hold copies of the root certificate authority, so only the
+// root Secret reports that.
+func certificateStatuses(secret *corev1.Secret, source string) []v1beta1.CertificateStatus {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		if !strings.HasSuffix(key, ".crt") {
+			continue
+		}
+		if source == v1beta1.CertificateSourceOperator &&
+			(key == rootCertFile || key == rootNextCertificate || key == rootPreviousCertificate) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var statuses []v1beta1.CertificateStatus
+	for _, key := range keys {
+		var bundle pki.CertificateBundle
+		if err := bundle.UnmarshalText(secret.Data[key]); err != nil || len(bundle) == 0 {
+			continue
+		}
+
+		first := bundle[0]
+		for _, certificate := range bundle[1:] {
+			if certificate.NotAfter().Before(first.NotAfter()) {
+				first = certificate
+			}
+		}
+
+		statuses = append(statuses, v1beta1.CertificateStatus{
+			Secret:   secret.Name,
+			Key:      key,
+			Source:   source,
+			Subject:  first.Subject(),
+			Issuer:   first.Issuer(),
+			NotAfter: metav1.NewTime(first.NotAfter()),
+		})
+	}
+	return statuses
+}
+
+// +kubebuilder:rbac:groups="",resources="secrets",verbs={get,list}
+
+// reconcileCertificateInventory reports in cluster the certificates that it
+// uses and when they expire. It sets a condition and emits a warning event
+// for each certificate that expires within the warning window.
+func (r *Reconciler) reconcileCertificateInventory(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, now time.Time,
+) error {
+	sources := certificateSecretSources(cluster)
+	secrets := make(map[string]*corev1.Secret)
+
+	labeled := &corev1.SecretList{}
+	err := errors.WithStack(r.Client.List(ctx, labeled,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{naming.LabelCluster: cluster.Name},
+	))
+	for i := range labeled.Items {
+		secrets[labeled.Items[i].Name] = &labeled.Items[i]
+		if _, ok := sources[labeled.Items[i].Name]; !ok {
+			sources[labeled.Items[i].Name] = v1beta1.CertificateSourceOperator
+		}
+		if labeled.Items[i].Labels[naming.LabelClusterCertificate] == certManagerCertificateLabel {
+			sources[labeled.Items[i].Name] = v1beta1.CertificateSourceIssuer
+		}
+	}
+	for name := range sources {
+		if _, ok := secrets[name]; ok || err != nil {
+			continue
+		}
+		secret := &corev1.Secret{}
+		err = errors.WithStack(r.Client.Get(ctx,
+			client.ObjectKey{Namespace: cluster.Namespace, Name: name}, secret))
+		if err == nil {
+			secrets[name] = secret
+		} else if apierrors.IsNotFound(err) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var statuses []v1beta1.CertificateStatus
+	for _, name := range names {
+		statuses = append(statuses, certificateStatuses(secrets[name], sources[name])...)
+	}
+	cluster.Status.Certificates = statuses
+
+	deleteCertificateMetrics(cluster)
+	for _, status := range statuses {
+		certificateExpiry.WithLabelValues(cluster.Namespace, cluster.Name,
+			status.Secret, status.Key, status.Source,
+		).Set(float64(status.NotAfter.Unix()))
+	}
+
+	r.setCertificateExpiryCondition(cluster, now)
+	return nil
+}
+
+// setCertificateExpiryCondition reports in cluster the certificates in its
+// status that expire within the warning window. The condition is removed when
+// there are none.
+func (r *Reconciler) setCertificateExpiryCondition(
+	cluster *v1beta1.PostgresCluster, now time.Time,
+) {
+	deadline := now.Add(certificateExpiryWarning(cluster))
+
+	// Certificates already in the condition were reported by an event.
+	reported := sets.New[string]()
+	if condition := meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.CertificateExpiring); condition != nil {
+		reported.Insert(strings.Split(condition.Message, "; ")...)
+	}
+
+	var expiring []string
+	for _, status := range cluster.Status.Certificates {
+		if status.NotAfter.Time.After(deadline) {
+			continue
+		}
+
+		verb := "expires"
+		if !status.NotAfter.Time.After(now) {
+			verb = "expired"
+		}
+		message := status.Source + " certificate " + status.Key + " in Secret " +
+			status.Secret + " " + verb + " at " + status.NotAfter.UTC().Format(time.RFC3339)
+
+		expiring = append(expiring, message)
+		if !reported.Has(message) {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "CertificateExpiring", message)
+		}
+	}
+
+	if len(expiring) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, v1beta1.CertificateExpiring)
+		return
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    v1beta1.CertificateExpiring,
+		Status:  metav1.ConditionTrue,
+		Reason:  "CertificateExpiring",
+		Message: strings.Join(expiring, "; "),
+
+		ObservedGeneration: cluster.GetGeneration(),
+	})
+}
+
+// certificateExpiryRequeue returns how long to wait before the next
+// certificate in cluster enters the warning window. That does not cause an
+// event that triggers another reconcile.
+func certificateExpiryRequeue(cluster *v1beta1.PostgresCluster, now time.Time) time.Duration {
+	warning := certificateExpiryWarning(cluster)
+
+	var requeue time.Duration
+	for _, status := range cluster.Status.Certificates {
+		if wait := status.NotAfter.Time.Add(-warning).Sub(now); wait > 0 &&
+			(requeue == 0 || wait < requeue) {
+			requeue = wait
+		}
+	}
+	return requeue
+}
+
+// deleteCertificateMetrics stops reporting the certificates of cluster.
+func deleteCertificateMetrics(cluster *v1beta1.PostgresCluster) {
+	certificateExpiry.DeletePartialMatch(prometheus.Labels{
+		"namespace": cluster.Namespace, "cluster": cluster.Name,
+	})
+}
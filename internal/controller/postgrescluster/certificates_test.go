@@ -0,0 +1,192 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/pki"
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/internal/testing/events"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestCertificateStatuses(t *testing.T) {
+	root, err := pki.NewRootCertificateAuthority()
+	assert.NilError(t, err)
+	leaf, err := root.GenerateLeafCertificate("some-cn", nil)
+	assert.NilError(t, err)
+
+	rootText, _ := root.Certificate.MarshalText()
+	leafText, _ := leaf.Certificate.MarshalText()
+
+	secret := &corev1.Secret{}
+	secret.Name = "some-secret"
+	secret.Data = map[string][]byte{
+		"ca.crt":  rootText,
+		"tls.crt": append(append([]byte{}, rootText...), leafText...),
+		"tls.key": []byte("not a certificate"),
+		"bad.crt": []byte("not a certificate"),
+	}
+
+	t.Run("Operator", func(t *testing.T) {
+		statuses := certificateStatuses(secret, v1beta1.CertificateSourceOperator)
+		assert.Equal(t, len(statuses), 1)
+
+		// The leaf expires before the root that precedes it.
+		assert.Equal(t, statuses[0].Secret, "some-secret")
+		assert.Equal(t, statuses[0].Key, "tls.crt")
+		assert.Equal(t, statuses[0].Source, v1beta1.CertificateSourceOperator)
+		assert.Equal(t, statuses[0].Subject, "CN=some-cn")
+		assert.Equal(t, statuses[0].Issuer, "CN=postgres-operator-ca")
+		assert.Assert(t, statuses[0].NotAfter.Time.Equal(leaf.Certificate.NotAfter()))
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		statuses := certificateStatuses(secret, v1beta1.CertificateSourceCustom)
+		assert.Equal(t, len(statuses), 2)
+
+		assert.Equal(t, statuses[0].Key, "ca.crt")
+		assert.Equal(t, statuses[0].Subject, "CN=postgres-operator-ca")
+		assert.Equal(t, statuses[1].Key, "tls.crt")
+		assert.Equal(t, statuses[1].Subject, "CN=some-cn")
+	})
+}
+
+func TestReconcileCertificateInventory(t *testing.T) {
+	ctx := context.Background()
+
+	root, err := pki.NewRootCertificateAuthority()
+	assert.NilError(t, err)
+	leaf, err := root.GenerateLeafCertificate("some-cn", nil)
+	assert.NilError(t, err)
+
+	rootText, _ := root.Certificate.MarshalText()
+	leafText, _ := leaf.Certificate.MarshalText()
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	cluster.Spec.CustomTLSSecret = &corev1.SecretProjection{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "custom-tls"},
+	}
+
+	rootSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: naming.RootCertSecret},
+		Data:       map[string][]byte{"root.crt": rootText},
+	}
+	customSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "custom-tls"},
+		Data:       map[string][]byte{"tls.crt": leafText},
+	}
+	operatorSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1", Name: "hippo-replication-cert",
+			Labels: map[string]string{naming.LabelCluster: "hippo"},
+		},
+		Data: map[string][]byte{"tls.crt": leafText, "ca.crt": rootText},
+	}
+	otherSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1", Name: "other-cert",
+			Labels: map[string]string{naming.LabelCluster: "other"},
+		},
+		Data: map[string][]byte{"tls.crt": leafText},
+	}
+
+	cc := fake.NewClientBuilder().WithScheme(runtime.Scheme).
+		WithObjects(rootSecret, customSecret, operatorSecret, otherSecret).Build()
+
+	t.Run("Valid", func(t *testing.T) {
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Client: cc, Recorder: recorder}
+
+		cluster := cluster.DeepCopy()
+		now := time.Now()
+		assert.NilError(t, reconciler.reconcileCertificateInventory(ctx, cluster, now))
+
+		var found []string
+		for _, status := range cluster.Status.Certificates {
+			found = append(found, status.Source+" "+status.Secret+"/"+status.Key)
+		}
+		assert.DeepEqual(t, found, []string{
+			"Custom custom-tls/tls.crt",
+			"Operator hippo-replication-cert/tls.crt",
+			"Operator pgo-root-cacert/root.crt",
+		})
+
+		assert.Equal(t, len(recorder.Events), 0)
+		assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions,
+			v1beta1.CertificateExpiring) == nil)
+
+		assert.Equal(t,
+			testutil.ToFloat64(certificateExpiry.WithLabelValues(
+				"ns1", "hippo", "custom-tls", "tls.crt", "Custom")),
+			float64(leaf.Certificate.NotAfter().Unix()))
+
+		// The next reconcile is when the leaf enters the warning window.
+		requeue := certificateExpiryRequeue(cluster, now)
+		expected := leaf.Certificate.NotAfter().Add(-defaultCertificateExpiryWarning).Sub(now)
+		assert.Equal(t, requeue, expected)
+	})
+
+	t.Run("Expiring", func(t *testing.T) {
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Client: cc, Recorder: recorder}
+
+		cluster := cluster.DeepCopy()
+		cluster.Spec.CertificateExpiryWarning = &metav1.Duration{Duration: time.Hour}
+
+		now := leaf.Certificate.NotAfter().Add(-time.Minute)
+		assert.NilError(t, reconciler.reconcileCertificateInventory(ctx, cluster, now))
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.CertificateExpiring)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionTrue)
+		assert.Assert(t, cmp.Contains(condition.Message, "Custom certificate tls.crt in Secret custom-tls expires"))
+		assert.Assert(t, cmp.Contains(condition.Message, "hippo-replication-cert"))
+
+		assert.Equal(t, len(recorder.Events), 2)
+		for _, event := range recorder.Events {
+			assert.Equal(t, event.Type, corev1.EventTypeWarning)
+			assert.Equal(t, event.Reason, "CertificateExpiring")
+		}
+
+		// Only the root remains to enter the window.
+		requeue := certificateExpiryRequeue(cluster, now)
+		assert.Equal(t, requeue, root.Certificate.NotAfter().Add(-time.Hour).Sub(now))
+
+		// Certificates are reported once while they remain the same.
+		assert.NilError(t, reconciler.reconcileCertificateInventory(ctx, cluster, now))
+		assert.Equal(t, len(recorder.Events), 2)
+
+		// Another event is emitted when they expire.
+		assert.NilError(t, reconciler.reconcileCertificateInventory(ctx, cluster, now.Add(time.Hour)))
+		assert.Equal(t, len(recorder.Events), 4)
+		assert.Assert(t, cmp.Contains(recorder.Events[3].Note, "expired"))
+
+		t.Run("Renewed", func(t *testing.T) {
+			cluster.Status.Certificates = nil
+			reconciler.setCertificateExpiryCondition(cluster, now.Add(-24*time.Hour))
+			assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions,
+				v1beta1.CertificateExpiring) == nil)
+		})
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		deleteCertificateMetrics(cluster)
+		assert.Equal(t, testutil.CollectAndCount(certificateExpiry), 0)
+	})
+}
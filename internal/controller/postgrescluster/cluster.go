@@ -185,7 +185,7 @@ func (r *Reconciler) reconcileClusterPrimaryService(
 // generateClusterReplicaService returns a v1.Service that exposes PostgreSQL
 // replica instances.
 func (r *Reconciler) generateClusterReplicaService(
-	cluster *v1beta1.PostgresCluster) (*corev1.Service, error,
+	cluster *v1beta1.PostgresCluster, instances *observedInstances) (*corev1.Service, error,
 ) {
 	service := &corev1.Service{ObjectMeta: naming.ClusterReplicaService(cluster)}
 	service.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
@@ -251,6 +251,13 @@ func (r *Reconciler) generateClusterReplicaService(
 		naming.LabelRole:    naming.RolePatroniReplica,
 	}
 
+	// Instances that delay applying WAL are far behind the primary by design.
+	// Select only those that do not when there are any, but wait until all
+	// their Pods have the label so the Service does not drop any of them.
+	if hasDelayedInstanceSets(cluster) && hasLoadBalanceLabels(instances) {
+		service.Spec.Selector[naming.LabelLoadBalance] = "true"
+	}
+
 	err := errors.WithStack(r.setControllerReference(cluster, service))
 
 	return service, err
@@ -259,12 +266,16 @@ func (r *Reconciler) generateClusterReplicaService(
 // +kubebuilder:rbac:groups="",resources="services",verbs={create,patch}
 
 // reconcileClusterReplicaService writes the Service that exposes PostgreSQL
-// replica instances.
+// replica instances. It labels the Pods of instances the Service selects.
 func (r *Reconciler) reconcileClusterReplicaService(
-	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
 ) (*corev1.Service, error) {
-	service, err := r.generateClusterReplicaService(cluster)
+	err := r.reconcileLoadBalanceLabels(ctx, cluster, instances)
 
+	var service *corev1.Service
+	if err == nil {
+		service, err = r.generateClusterReplicaService(cluster, instances)
+	}
 	if err == nil {
 		err = errors.WithStack(r.apply(ctx, service))
 	}
@@ -282,7 +293,7 @@ func (r *Reconciler) reconcileClusterReplicaService(
 func (r *Reconciler) reconcileDataSource(ctx context.Context,
 	cluster *v1beta1.PostgresCluster, observed *observedInstances,
 	clusterVolumes []corev1.PersistentVolumeClaim,
-	rootCA *pki.RootCertificateAuthority,
+	rootCA pki.Issuer,
 	backupsSpecFound bool,
 ) (bool, error) {
 
@@ -716,7 +716,7 @@ func TestGenerateClusterReplicaServiceIntent(t *testing.T) {
 	cluster.Name = "pg2"
 	cluster.Spec.Port = initialize.Int32(9876)
 
-	service, err := reconciler.generateClusterReplicaService(cluster)
+	service, err := reconciler.generateClusterReplicaService(cluster, nil)
 	assert.NilError(t, err)
 
 	alwaysExpect := func(t testing.TB, service *corev1.Service) {
@@ -774,7 +774,7 @@ type: ClusterIP
 			cluster := cluster.DeepCopy()
 			cluster.Spec.ReplicaService = &v1beta1.ServiceSpec{Type: test.Type}
 
-			service, err := reconciler.generateClusterReplicaService(cluster)
+			service, err := reconciler.generateClusterReplicaService(cluster, nil)
 			assert.NilError(t, err)
 			alwaysExpect(t, service)
 			test.Expect(t, service)
@@ -794,7 +794,7 @@ type: ClusterIP
 			Labels:      map[string]string{"happy": "label"},
 		}
 
-		service, err := reconciler.generateClusterReplicaService(cluster)
+		service, err := reconciler.generateClusterReplicaService(cluster, nil)
 		assert.NilError(t, err)
 
 		// Annotations present in the metadata.
@@ -812,6 +812,40 @@ postgres-operator.crunchydata.com/role: replica
 		// Labels not in the selector.
 		assert.Assert(t, cmp.MarshalMatches(service.Spec.Selector, `
 postgres-operator.crunchydata.com/cluster: pg2
+postgres-operator.crunchydata.com/role: replica
+		`))
+	})
+
+	t.Run("DelayedInstances", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+			{Name: "fast"},
+			{Name: "slow", RecoveryMinApplyDelay: initialize.String("1h")},
+		}
+
+		pod := &corev1.Pod{}
+		observed := &observedInstances{forCluster: []*Instance{
+			{Spec: &cluster.Spec.InstanceSets[0], Pods: []*corev1.Pod{pod}},
+			{Spec: &cluster.Spec.InstanceSets[1], Pods: []*corev1.Pod{{}}},
+		}}
+
+		// All replicas are selected until every instance that is not delayed
+		// has the label.
+		service, err := reconciler.generateClusterReplicaService(cluster, observed)
+		assert.NilError(t, err)
+		assert.Assert(t, cmp.MarshalMatches(service.Spec.Selector, `
+postgres-operator.crunchydata.com/cluster: pg2
+postgres-operator.crunchydata.com/role: replica
+		`))
+
+		// Only instances that are not delayed are selected.
+		pod.Labels = map[string]string{naming.LabelLoadBalance: "true"}
+
+		service, err = reconciler.generateClusterReplicaService(cluster, observed)
+		assert.NilError(t, err)
+		assert.Assert(t, cmp.MarshalMatches(service.Spec.Selector, `
+postgres-operator.crunchydata.com/cluster: pg2
+postgres-operator.crunchydata.com/loadbalance: "true"
 postgres-operator.crunchydata.com/role: replica
 		`))
 	})
@@ -51,11 +51,12 @@ const (
 
 // Reconciler holds resources for the PostgresCluster reconciler
 type Reconciler struct {
-	Client          client.Client
-	DiscoveryClient *discovery.DiscoveryClient
-	IsOpenShift     bool
-	Owner           client.FieldOwner
-	PodExec         func(
+	CertificatePolicy pki.Policy
+	Client            client.Client
+	DiscoveryClient   *discovery.DiscoveryClient
+	IsOpenShift       bool
+	Owner             client.FieldOwner
+	PodExec           func(
 		ctx context.Context, namespace, pod, container string,
 		stdin io.Reader, stdout, stderr io.Writer, command ...string,
 	) error
@@ -161,7 +162,7 @@ func (r *Reconciler) Reconcile(
 		primaryCertificate           *corev1.SecretProjection
 		primaryService               *corev1.Service
 		replicaService               *corev1.Service
-		rootCA                       *pki.RootCertificateAuthority
+		rootCA                       pki.Issuer
 		monitoringSecret             *corev1.Secret
 		exporterQueriesConfig        *corev1.ConfigMap
 		exporterWebConfig            *corev1.ConfigMap
@@ -230,17 +231,35 @@ func (r *Reconciler) Reconcile(
 	pgHBAs := postgres.NewHBAs()
 	pgmonitor.PostgreSQLHBAs(cluster, &pgHBAs)
 	pgbouncer.PostgreSQL(cluster, &pgHBAs)
+	postgres.UserCertificateHBAs(cluster, &pgHBAs)
+	var pgHBAFiles map[string]string
+	if err == nil {
+		pgHBAFiles, err = r.setPostgresHBARules(ctx, cluster, &pgHBAs)
+	}
+
+	r.validatePostgresParameters(cluster)
+	r.validateSynchronousReplication(cluster)
+	r.validateMaintenanceWindows(cluster)
 
 	pgParameters := postgres.NewParameters()
 	pgaudit.PostgreSQLParameters(&pgParameters)
 	pgbackrest.PostgreSQL(cluster, &pgParameters, backupsSpecFound)
 	pgmonitor.PostgreSQLParameters(cluster, &pgParameters)
+	postgres.SetExtensionLibraries(cluster, &pgParameters)
 
 	// Set huge_pages = try if a hugepages resource limit > 0, otherwise set "off"
 	postgres.SetHugePages(cluster, &pgParameters)
 
+	// Compute memory parameters from the resources of instances, if enabled
+	postgres.SetTuningParameters(cluster, &pgParameters)
+
 	if err == nil {
 		rootCA, err = r.reconcileRootCertificate(ctx, cluster)
+
+		// Nothing can be issued until the certificate issuer is ready.
+		if err == nil && rootCA == nil {
+			return runtime.RequeueWithoutBackoff(certificateIssuerRetry), patchClusterStatus()
+		}
 	}
 
 	if err == nil {
@@ -250,7 +269,19 @@ func (r *Reconciler) Reconcile(
 		// return a bool indicating that the controller should return early while any
 		// required Jobs are running, after which it will indicate that an early
 		// return is no longer needed, and reconciliation can proceed normally.
-		returnEarly, err := r.reconcileDirMoveJobs(ctx, cluster)
+		//
+		// Moves that are waiting for a maintenance window also return early.
+		// Nothing else can use the volumes until their directories are moved.
+		returnEarly, movesDeferred, err := r.reconcileDirMoveJobs(ctx, cluster)
+		if err == nil && movesDeferred {
+			now := time.Now()
+			setMaintenanceCondition(cluster, now, []string{"move existing directories"})
+
+			if requeue := maintenanceRequeue(cluster, now); requeue > 0 {
+				return runtime.RequeueWithoutBackoff(requeue), patchClusterStatus()
+			}
+			return reconcile.Result{}, patchClusterStatus()
+		}
 		if err != nil || returnEarly {
 			return runtime.ErrorWithBackoff(errors.Join(err, patchClusterStatus()))
 		}
@@ -305,6 +336,9 @@ func (r *Reconciler) Reconcile(
 			return runtime.ErrorWithBackoff(errors.Join(err, patchClusterStatus()))
 		}
 	}
+	if err == nil {
+		err = r.reconcilePostgresHBASecret(ctx, cluster, pgHBAFiles)
+	}
 	if err == nil {
 		clusterConfigMap, err = r.reconcileClusterConfigMap(ctx, cluster, pgHBAs, pgParameters)
 	}
@@ -318,7 +352,7 @@ func (r *Reconciler) Reconcile(
 		primaryService, err = r.reconcileClusterPrimaryService(ctx, cluster, patroniLeaderService)
 	}
 	if err == nil {
-		replicaService, err = r.reconcileClusterReplicaService(ctx, cluster)
+		replicaService, err = r.reconcileClusterReplicaService(ctx, cluster, instances)
 	}
 	if err == nil {
 		primaryCertificate, err = r.reconcileClusterCertificate(ctx, rootCA, cluster, primaryService, replicaService)
@@ -343,15 +377,45 @@ func (r *Reconciler) Reconcile(
 			ctx, cluster, clusterConfigMap, clusterReplicationSecret, rootCA,
 			clusterPodService, instanceServiceAccount, instances, patroniLeaderService,
 			primaryCertificate, clusterVolumes, exporterQueriesConfig, exporterWebConfig,
-			backupsSpecFound,
+			backupsSpecFound, pgParameters,
 		)
 	}
+	if err == nil {
+		if requeue := rolloutRequeue(cluster); requeue > 0 &&
+			(result.RequeueAfter == 0 || requeue < result.RequeueAfter) {
+			result.RequeueAfter = requeue
+		}
+	}
 
 	if err == nil {
-		err = r.reconcilePostgresDatabases(ctx, cluster, instances)
+		err = r.reconcilePostgresTablespaces(ctx, cluster, instances)
+	}
+	if err == nil {
+		now := time.Now()
+		err = r.reconcilePostgresDatabases(ctx, cluster, instances, now)
+
+		if requeue := databasesRequeue(cluster, now); requeue > 0 &&
+			(result.RequeueAfter == 0 || requeue < result.RequeueAfter) {
+			result.RequeueAfter = requeue
+		}
+	}
+	if err == nil {
+		err = r.reconcilePostgresUsers(ctx, cluster, instances, rootCA)
+
+		if requeue := privilegesRequeue(cluster); requeue > 0 &&
+			(result.RequeueAfter == 0 || requeue < result.RequeueAfter) {
+			result.RequeueAfter = requeue
+		}
 	}
 	if err == nil {
-		err = r.reconcilePostgresUsers(ctx, cluster, instances)
+		if requeue := passwordRotationRequeue(cluster, time.Now()); requeue > 0 &&
+			(result.RequeueAfter == 0 || requeue < result.RequeueAfter) {
+			result.RequeueAfter = requeue
+		}
+		if requeue := rootRotationRequeue(cluster); requeue > 0 &&
+			(result.RequeueAfter == 0 || requeue < result.RequeueAfter) {
+			result.RequeueAfter = requeue
+		}
 	}
 
 	if err == nil {
@@ -383,9 +447,45 @@ func (r *Reconciler) Reconcile(
 		err = r.reconcilePGAdmin(ctx, cluster)
 	}
 	if err == nil {
-		// This is after [Reconciler.rolloutInstances] to ensure that recreating
-		// Pods takes precedence.
-		err = r.handlePatroniRestarts(ctx, cluster, instances)
+		// Report pending restarts in status before acting on them. Restarts
+		// wait for the next reconcile whenever the report changes.
+		if setPendingRestartCondition(cluster, instances, time.Now()) {
+			result.Requeue = true
+		} else {
+			// This is after [Reconciler.rolloutInstances] to ensure that
+			// recreating Pods takes precedence.
+			err = r.handlePatroniRestarts(ctx, cluster, instances)
+		}
+	}
+	if err == nil {
+		now := time.Now()
+		err = r.reconcileCertificateInventory(ctx, cluster, now)
+
+		if requeue := certificateExpiryRequeue(cluster, now); requeue > 0 &&
+			(result.RequeueAfter == 0 || requeue < result.RequeueAfter) {
+			result.RequeueAfter = requeue
+		}
+	}
+	if err == nil {
+		now := time.Now()
+		setMaintenanceCondition(cluster, now, pendingMaintenance(cluster, instances))
+
+		if requeue := maintenanceRequeue(cluster, now); requeue > 0 &&
+			(result.RequeueAfter == 0 || requeue < result.RequeueAfter) {
+			result.RequeueAfter = requeue
+		}
+	}
+
+	// Certificates from cert-manager are issued in the background. Wait for
+	// them without backoff; their Secrets also trigger another reconcile.
+	if issuer, ok := rootCA.(*certManagerIssuer); ok {
+		setCertManagerCondition(cluster, err)
+
+		if errors.Is(err, errCertificatePending) {
+			err, result = nil, runtime.RequeueWithoutBackoff(certificateIssuerRetry)
+		} else if err == nil {
+			err = r.pruneCertManagerCertificates(ctx, cluster, issuer)
+		}
 	}
 
 	// at this point everything reconciled successfully, and we can update the
@@ -496,6 +596,7 @@ func (r *Reconciler) SetupWithManager(mgr manager.Manager) error {
 		Owns(&batchv1.CronJob{}).
 		Owns(&policyv1.PodDisruptionBudget{}).
 		Watches(&corev1.Pod{}, r.watchPods()).
+		Watches(&corev1.Secret{}, r.watchReferencedSecrets()).
 		Watches(&appsv1.StatefulSet{},
 			r.controllerRefHandlerFuncs()). // watch all StatefulSets
 		Complete(r)
@@ -85,6 +85,9 @@ func (r *Reconciler) handleDelete(
 		return nil, err
 	}
 
+	// Stop reporting the certificates of the cluster.
+	deleteCertificateMetrics(cluster)
+
 	// Our finalizer logic is finished; remove our finalizer.
 	// The Finalizers field is shared by multiple controllers, but the
 	// server-side merge strategy does not work on our custom resource due to a
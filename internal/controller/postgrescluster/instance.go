@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"sort"
 	"strings"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
 	"github.com/crunchydata/postgres-operator/internal/feature"
 	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/kubeapi"
 	"github.com/crunchydata/postgres-operator/internal/logging"
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/internal/patroni"
@@ -318,6 +320,7 @@ func (r *Reconciler) observeInstances(
 	// This may happen in cases where the Pod is restarted, the cluster
 	// is shutdown, etc. Only save values for instances defined in the spec.
 	previousDesiredRequests := make(map[string]string)
+	previousSets := make(map[string]v1beta1.PostgresInstanceSetStatus)
 	if autogrow {
 		for _, statusIS := range cluster.Status.InstanceSets {
 			if statusIS.DesiredPGDataVolume != nil {
@@ -325,6 +328,7 @@ func (r *Reconciler) observeInstances(
 					previousDesiredRequests[k] = v
 				}
 			}
+			previousSets[statusIS.Name] = statusIS
 		}
 	}
 
@@ -348,11 +352,9 @@ func (r *Reconciler) observeInstances(
 				// The 'suggested-pgdata-pvc-size' annotation value is stored in the PostgresCluster
 				// status so that 1) it is available to the function 'reconcilePostgresDataVolume'
 				// and 2) so that the value persists after Pod restart and cluster shutdown events.
-				for _, pod := range instance.Pods {
-					// don't set an empty status
-					if pod.Annotations["suggested-pgdata-pvc-size"] != "" {
-						status.DesiredPGDataVolume[instance.Name] = pod.Annotations["suggested-pgdata-pvc-size"]
-					}
+				// don't set an empty status
+				if size := suggestedVolumeSize(instance, "pgdata"); size != "" {
+					status.DesiredPGDataVolume[instance.Name] = size
 				}
 			}
 		}
@@ -363,6 +365,7 @@ func (r *Reconciler) observeInstances(
 				status.DesiredPGDataVolume[instance.Name] = r.storeDesiredRequest(ctx, cluster,
 					name, status.DesiredPGDataVolume[instance.Name], previousDesiredRequests[instance.Name])
 			}
+			r.storeDesiredVolumes(ctx, cluster, name, observed.bySet[name], previousSets[name], &status)
 		}
 
 		cluster.Status.InstanceSets = append(cluster.Status.InstanceSets, status)
@@ -376,6 +379,89 @@ func (r *Reconciler) observeInstances(
 func (r *Reconciler) storeDesiredRequest(
 	ctx context.Context, cluster *v1beta1.PostgresCluster,
 	instanceSetName, desiredRequest, desiredRequestBackup string,
+) string {
+	// Determine if the limit is set for this instance set.
+	var limitSet bool
+	for _, specInstance := range cluster.Spec.InstanceSets {
+		if specInstance.Name == instanceSetName {
+			limitSet = !specInstance.DataVolumeClaimSpec.Resources.Limits.Storage().IsZero()
+		}
+	}
+
+	return r.storeDesiredVolumeRequest(ctx, cluster, "pgData", cluster.Name+"/"+instanceSetName,
+		limitSet, desiredRequest, desiredRequestBackup)
+}
+
+// storeDesiredVolumes saves the sizes that the instances of an instance set
+// suggest for their pgWAL and tablespace volumes to status. Values missing
+// from the Pods are taken from previous.
+func (r *Reconciler) storeDesiredVolumes(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instanceSetName string,
+	instances []*Instance, previous v1beta1.PostgresInstanceSetStatus,
+	status *v1beta1.PostgresInstanceSetStatus,
+) {
+	var spec *v1beta1.PostgresInstanceSetSpec
+	for i := range cluster.Spec.InstanceSets {
+		if cluster.Spec.InstanceSets[i].Name == instanceSetName {
+			spec = &cluster.Spec.InstanceSets[i]
+		}
+	}
+	if spec == nil {
+		return
+	}
+
+	owner := cluster.Name + "/" + instanceSetName
+
+	if spec.WALVolumeClaimSpec != nil {
+		limitSet := !spec.WALVolumeClaimSpec.Resources.Limits.Storage().IsZero()
+		for _, instance := range instances {
+			if desired := r.storeDesiredVolumeRequest(ctx, cluster, "pgWAL", owner, limitSet,
+				suggestedVolumeSize(instance, "pgwal"),
+				previous.DesiredPGWALVolume[instance.Name]); desired != "" {
+				if status.DesiredPGWALVolume == nil {
+					status.DesiredPGWALVolume = make(map[string]string)
+				}
+				status.DesiredPGWALVolume[instance.Name] = desired
+			}
+		}
+	}
+
+	for _, tablespace := range spec.TablespaceVolumes {
+		limitSet := !tablespace.DataVolumeClaimSpec.Resources.Limits.Storage().IsZero()
+		for _, instance := range instances {
+			if desired := r.storeDesiredVolumeRequest(ctx, cluster, tablespace.Name+" tablespace", owner, limitSet,
+				suggestedVolumeSize(instance, postgres.TablespaceVolumeMount(tablespace.Name).Name),
+				previous.DesiredTablespaceVolumes[tablespace.Name][instance.Name]); desired != "" {
+				if status.DesiredTablespaceVolumes == nil {
+					status.DesiredTablespaceVolumes = make(map[string]map[string]string)
+				}
+				if status.DesiredTablespaceVolumes[tablespace.Name] == nil {
+					status.DesiredTablespaceVolumes[tablespace.Name] = make(map[string]string)
+				}
+				status.DesiredTablespaceVolumes[tablespace.Name][instance.Name] = desired
+			}
+		}
+	}
+}
+
+// suggestedVolumeSize returns the size that the Pods of instance suggest for
+// their volume named volume, if any.
+func suggestedVolumeSize(instance *Instance, volume string) string {
+	var size string
+	for _, pod := range instance.Pods {
+		if suggested := pod.Annotations["suggested-"+volume+"-pvc-size"]; suggested != "" {
+			size = suggested
+		}
+	}
+	return size
+}
+
+// storeDesiredVolumeRequest returns the request value to save in the
+// PostgresCluster status for a volume of owner. If the value has grown and
+// the volume has a limit, create an Event.
+func (r *Reconciler) storeDesiredVolumeRequest(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	volume, owner string, limitSet bool, desiredRequest, desiredRequestBackup string,
 ) string {
 	var current resource.Quantity
 	var previous resource.Quantity
@@ -386,8 +472,8 @@ func (r *Reconciler) storeDesiredRequest(
 	if desiredRequest != "" {
 		current, err = resource.ParseQuantity(desiredRequest)
 		if err != nil {
-			log.Error(err, "Unable to parse pgData volume request from status ("+
-				desiredRequest+") for "+cluster.Name+"/"+instanceSetName)
+			log.Error(err, "Unable to parse "+volume+" volume request from status ("+
+				desiredRequest+") for "+owner)
 			// If there was an error parsing the value, treat as unset (equivalent to zero).
 			desiredRequest = ""
 			current, _ = resource.ParseQuantity("")
@@ -399,8 +485,8 @@ func (r *Reconciler) storeDesiredRequest(
 	if desiredRequestBackup != "" {
 		previous, err = resource.ParseQuantity(desiredRequestBackup)
 		if err != nil {
-			log.Error(err, "Unable to parse pgData volume request from status backup ("+
-				desiredRequestBackup+") for "+cluster.Name+"/"+instanceSetName)
+			log.Error(err, "Unable to parse "+volume+" volume request from status backup ("+
+				desiredRequestBackup+") for "+owner)
 			// If there was an error parsing the value, treat as unset (equivalent to zero).
 			desiredRequestBackup = ""
 			previous, _ = resource.ParseQuantity("")
@@ -408,18 +494,10 @@ func (r *Reconciler) storeDesiredRequest(
 		}
 	}
 
-	// Determine if the limit is set for this instance set.
-	var limitSet bool
-	for _, specInstance := range cluster.Spec.InstanceSets {
-		if specInstance.Name == instanceSetName {
-			limitSet = !specInstance.DataVolumeClaimSpec.Resources.Limits.Storage().IsZero()
-		}
-	}
-
 	if limitSet && current.Value() > previous.Value() {
 		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "VolumeAutoGrow",
-			"pgData volume expansion to %v requested for %s/%s.",
-			current.String(), cluster.Name, instanceSetName)
+			"%s volume expansion to %v requested for %s.",
+			volume, current.String(), owner)
 	}
 
 	// If the desired size was not observed, update with previously stored value.
@@ -585,7 +663,7 @@ func (r *Reconciler) reconcileInstanceSets(
 	cluster *v1beta1.PostgresCluster,
 	clusterConfigMap *corev1.ConfigMap,
 	clusterReplicationSecret *corev1.Secret,
-	rootCA *pki.RootCertificateAuthority,
+	rootCA pki.Issuer,
 	clusterPodService *corev1.Service,
 	instanceServiceAccount *corev1.ServiceAccount,
 	instances *observedInstances,
@@ -593,7 +671,7 @@ func (r *Reconciler) reconcileInstanceSets(
 	primaryCertificate *corev1.SecretProjection,
 	clusterVolumes []corev1.PersistentVolumeClaim,
 	exporterQueriesConfig, exporterWebConfig *corev1.ConfigMap,
-	backupsSpecFound bool,
+	backupsSpecFound bool, pgParameters postgres.Parameters,
 ) error {
 
 	// Go through the observed instances and check if a primary has been determined.
@@ -619,21 +697,50 @@ func (r *Reconciler) reconcileInstanceSets(
 		numInstancePods += len(instances.forCluster[i].Pods)
 	}
 
+	// Instances on a storage class other than their spec, or on a larger data
+	// volume when shrinking, are replaced by additional instances.
+	migrations := observeStorageMigrations(cluster, instances, clusterVolumes,
+		r.observeVolumeShrink(ctx, cluster, instances, clusterVolumes))
+
 	// Range over instance sets to scale up and ensure that each set has
 	// at least the number of replicas defined in the spec. The set can
 	// have more replicas than defined
 	for i := range cluster.Spec.InstanceSets {
 		set := &cluster.Spec.InstanceSets[i]
 		_, err := r.scaleUpInstances(
-			ctx, cluster, instances, set,
+			ctx, cluster, instances, set, migrations[set.Name].surge(),
 			clusterConfigMap, clusterReplicationSecret,
 			rootCA, clusterPodService, instanceServiceAccount,
 			patroniLeaderService, primaryCertificate,
 			findAvailableInstanceNames(*set, instances, clusterVolumes),
 			numInstancePods, clusterVolumes, exporterQueriesConfig, exporterWebConfig,
-			backupsSpecFound,
+			backupsSpecFound, pgParameters,
 		)
 
+		// Report the PostgreSQL parameters in effect for the set. Those that
+		// changed since the last report might need PostgreSQL to restart.
+		for j := range cluster.Status.InstanceSets {
+			if cluster.Status.InstanceSets[j].Name == set.Name {
+				parameters := patroni.InstanceParameters(cluster, set, pgParameters)
+				if previous := cluster.Status.InstanceSets[j].Parameters; previous != nil {
+					addPendingRestart(cluster, patroni.PendingRestartInstanceParameters(
+						cluster.Spec.PostgresVersion, previous, parameters))
+				}
+				cluster.Status.InstanceSets[j].Parameters = parameters
+
+				// Measuring apply lag execs into every delayed instance, so do
+				// it at most once per interval.
+				if status := &cluster.Status.InstanceSets[j]; set.RecoveryMinApplyDelay == nil {
+					status.ApplyLagSeconds, status.ApplyLagObserved = nil, nil
+				} else if status.ApplyLagObserved == nil ||
+					time.Since(status.ApplyLagObserved.Time) >= applyLagInterval {
+					now := metav1.Now()
+					status.ApplyLagSeconds = r.observeApplyLag(ctx, set, instances)
+					status.ApplyLagObserved = &now
+				}
+			}
+		}
+
 		if err == nil {
 			err = r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, set)
 		}
@@ -645,7 +752,13 @@ func (r *Reconciler) reconcileInstanceSets(
 	// Scaledown is called on the whole cluster in order to consider all
 	// instances. This is necessary because we have no way to determine
 	// which instance or instance set contains the primary pod.
-	err := r.scaleDownInstances(ctx, cluster, instances)
+	err := r.scaleDownInstances(ctx, cluster, instances, migrations)
+	if err != nil {
+		return err
+	}
+
+	// Retire instances on a previous storage class once they are replaced.
+	err = r.migrateInstanceStorage(ctx, cluster, instances, migrations)
 	if err != nil {
 		return err
 	}
@@ -665,6 +778,118 @@ func (r *Reconciler) reconcileInstanceSets(
 	return err
 }
 
+// applyLagInterval is the least amount of time between measurements of the
+// apply lag of delayed instances.
+const applyLagInterval = time.Minute
+
+// observeApplyLag returns the largest apply lag, in seconds, of running
+// instances in set when set has a recoveryMinApplyDelay. It returns nil when
+// set is not delayed or no lag could be measured.
+func (r *Reconciler) observeApplyLag(
+	ctx context.Context, set *v1beta1.PostgresInstanceSetSpec, instances *observedInstances,
+) *int64 {
+	if set.RecoveryMinApplyDelay == nil {
+		return nil
+	}
+
+	log := logging.FromContext(ctx)
+	var result *int64
+
+	for _, instance := range instances.bySet[set.Name] {
+		if terminating, known := instance.IsTerminating(); terminating || !known {
+			continue
+		}
+		if running, known := instance.IsRunning(naming.ContainerDatabase); !running || !known {
+			continue
+		}
+		if len(instance.Pods) == 0 {
+			continue
+		}
+
+		pod := instance.Pods[0]
+		exec := func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+			return r.PodExec(ctx, pod.Namespace, pod.Name, naming.ContainerDatabase, stdin, stdout, stderr, command...)
+		}
+
+		lag, known, err := postgres.ApplyLag(ctx, exec)
+		if err != nil {
+			log.Error(err, "unable to measure apply lag", "instance", instance.Name)
+			continue
+		}
+		if seconds := int64(lag.Seconds()); known && (result == nil || seconds > *result) {
+			result = &seconds
+		}
+	}
+	return result
+}
+
+// hasDelayedInstanceSets returns whether or not any instance set of cluster
+// has a recoveryMinApplyDelay.
+func hasDelayedInstanceSets(cluster *v1beta1.PostgresCluster) bool {
+	for i := range cluster.Spec.InstanceSets {
+		if cluster.Spec.InstanceSets[i].RecoveryMinApplyDelay != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:rbac:groups="",resources="pods",verbs={patch}
+
+// reconcileLoadBalanceLabels adds the load balance label to the Pods of
+// instances that do not delay applying WAL when any instances do. It removes
+// the label otherwise. Labeling Pods in place, rather than through their
+// StatefulSet, keeps them from restarting.
+func (r *Reconciler) reconcileLoadBalanceLabels(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) error {
+	delayed := hasDelayedInstanceSets(cluster)
+
+	for _, instance := range instances.forCluster {
+		want := delayed && instance.Spec != nil && instance.Spec.RecoveryMinApplyDelay == nil
+
+		for _, pod := range instance.Pods {
+			if _, has := pod.Labels[naming.LabelLoadBalance]; has == want || pod.DeletionTimestamp != nil {
+				continue
+			}
+
+			var value any
+			if want {
+				value = "true"
+			}
+
+			patch, err := kubeapi.NewMergePatch().
+				Add("metadata", "labels", naming.LabelLoadBalance)(value).Bytes()
+			if err == nil {
+				err = r.patch(ctx, pod, client.RawPatch(client.Merge.Type(), patch))
+			}
+			if err = client.IgnoreNotFound(err); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	return nil
+}
+
+// hasLoadBalanceLabels returns whether or not every Pod of instances that do
+// not delay applying WAL has the load balance label.
+func hasLoadBalanceLabels(instances *observedInstances) bool {
+	if instances == nil {
+		return false
+	}
+	for _, instance := range instances.forCluster {
+		if instance.Spec == nil || instance.Spec.RecoveryMinApplyDelay != nil {
+			continue
+		}
+		for _, pod := range instance.Pods {
+			if pod.DeletionTimestamp == nil && pod.Labels[naming.LabelLoadBalance] != "true" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // +kubebuilder:rbac:groups="policy",resources="poddisruptionbudgets",verbs={list}
 
 // cleanupPodDisruptionBudgets removes pdbs that do not have an
@@ -720,6 +945,15 @@ func findAvailableInstanceNames(set v1beta1.PostgresInstanceSetSpec,
 		if pvc.GetDeletionTimestamp() != nil {
 			continue
 		}
+		// ignore PGDATA PVCs on a storage class other than the one specified
+		if class := set.DataVolumeClaimSpec.StorageClassName; class != nil &&
+			pvc.Spec.StorageClassName != nil && *class != *pvc.Spec.StorageClassName {
+			continue
+		}
+		// ignore PGDATA PVCs that are larger than specified when shrinking
+		if set.DataVolumeShrink == v1beta1.VolumeShrinkEnabled && oversizedVolume(&set, &pvc) {
+			continue
+		}
 		pvcSet := pvc.GetLabels()[naming.LabelInstanceSet]
 		pvcRole := pvc.GetLabels()[naming.LabelRole]
 		if pvcRole == naming.RolePostgresData && pvcSet == set.Name {
@@ -885,7 +1119,10 @@ func (r *Reconciler) rolloutInstance(
 
 // rolloutInstances compares instances to cluster and calls redeploy on those
 // that need their Pod recreated. It considers the overall availability of
-// cluster and minimizes Patroni failovers.
+// cluster and minimizes Patroni failovers. Instance sets with a rollout
+// strategy are limited by that strategy, and only while instances of other
+// sets are available. Available instances are redeployed only during a
+// maintenance window.
 func (r *Reconciler) rolloutInstances(
 	ctx context.Context,
 	cluster *v1beta1.PostgresCluster,
@@ -904,12 +1141,20 @@ func (r *Reconciler) rolloutInstances(
 		numSpecified += int(*set.Replicas)
 	}
 
+	// Track the progress of each instance set separately.
+	rollouts := make(map[string]*instanceSetRollout)
+	for i := range cluster.Spec.InstanceSets {
+		set := &cluster.Spec.InstanceSets[i]
+		rollouts[set.Name] = &instanceSetRollout{spec: set}
+	}
+
 	for _, instance := range instances.forCluster {
 		// Skip instances that have no set in cluster spec. They should not be
 		// redeployed and should not count toward availability.
 		if instance.Spec == nil {
 			continue
 		}
+		rollout := rollouts[instance.Spec.Name]
 
 		// Skip instances that are or might be terminating. They should not be
 		// redeployed right now and cannot count toward availability.
@@ -919,21 +1164,34 @@ func (r *Reconciler) rolloutInstances(
 
 		if available, known := instance.IsAvailable(); known && available {
 			numAvailable++
+			rollout.available++
 		}
 
 		if matches, known := instance.PodMatchesPodTemplate(); known && !matches {
 			consider = append(consider, instance)
+			rollout.pending++
 			continue
+		} else if known {
+			rollout.updated = append(rollout.updated, instance)
 		}
 	}
 
 	const maxUnavailable = 1
 	numUnavailable := numSpecified - numAvailable
+	allowed := maintenanceAllowed(cluster, time.Now())
 
 	// When multiple instances need to redeploy, sort them so the lowest
-	// priority instances are first.
-	if len(consider) > 1 {
+	// priority instances are first. The primary moves to the front when its
+	// set switches over first.
+	if n := len(consider); n > 1 {
 		sort.Sort(byPriority(consider))
+
+		if primary, known := consider[n-1].IsPrimary(); known && primary {
+			strategy := consider[n-1].Spec.RolloutStrategy
+			if strategy != nil && strategy.Primary == v1beta1.RolloutSwitchoverFirst {
+				consider = append(consider[n-1:], consider[:n-1]...)
+			}
+		}
 	}
 
 	span.SetAttributes(
@@ -947,14 +1205,554 @@ func (r *Reconciler) rolloutInstances(
 	// unavailable instances.
 	// - https://issue.k8s.io/67250
 	for _, instance := range consider {
-		if err == nil {
-			if available, known := instance.IsAvailable(); known && !available {
-				err = redeploy(ctx, instance)
-			} else if numUnavailable < maxUnavailable {
+		if err != nil {
+			break
+		}
+
+		rollout := rollouts[instance.Spec.Name]
+		strategy := instance.Spec.RolloutStrategy
+		primary, known := instance.IsPrimary()
+		primary = primary && known
+
+		if available, known := instance.IsAvailable(); known && !available {
+			err = redeploy(ctx, instance)
+			rollout.pending--
+			continue
+		}
+
+		switch {
+		case !allowed:
+			rollout.wait(rolloutMaintenanceWindow, "waiting for a maintenance window")
+
+		case primary && strategy != nil &&
+			strategy.Primary != v1beta1.RolloutSwitchoverFirst && len(consider) > 1:
+			rollout.wait("PrimaryLast", "waiting for replicas to be replaced")
+
+		case numUnavailable >= maxUnavailable && (strategy == nil || primary):
+			rollout.wait("MaxUnavailable", "waiting for instances to be available")
+
+		case numUnavailable-rollout.unavailable() >= maxUnavailable:
+			rollout.wait("MaxUnavailable", "waiting for instances of other sets to be available")
+
+		case primary || strategy == nil:
+			err = redeploy(ctx, instance)
+			rollout.pending--
+			rollout.redeployed++
+			numUnavailable++
+
+		default:
+			if ok := r.rolloutAllowed(ctx, instances, rollout); ok {
 				err = redeploy(ctx, instance)
+				rollout.pending--
+				rollout.redeployed++
 				numUnavailable++
 			}
 		}
+
+		// Changing the primary affects every instance; stop and wait for
+		// Patroni to settle before replacing anything else.
+		if primary && rollout.redeployed > 0 {
+			break
+		}
+	}
+
+	// Report progress in the status of each instance set.
+	for i := range cluster.Status.InstanceSets {
+		status := &cluster.Status.InstanceSets[i]
+		status.Rollout = nil
+
+		if rollout := rollouts[status.Name]; rollout != nil && rollout.pending > 0 {
+			status.Rollout = &v1beta1.PostgresInstanceSetRolloutStatus{
+				Pending: int32(rollout.pending), //nolint:gosec
+				Waiting: rollout.waiting,
+				Message: rollout.message,
+			}
+		}
+	}
+
+	span.RecordError(err)
+	return err
+}
+
+// instanceSetRollout tracks the progress of replacing Pods in one instance set.
+type instanceSetRollout struct {
+	spec    *v1beta1.PostgresInstanceSetSpec
+	updated []*Instance
+
+	// Number of instances that are available, that need to be replaced,
+	// and that were available before being replaced just now.
+	available, pending, redeployed int
+
+	// Why the rollout is waiting, if it is.
+	waiting, message string
+
+	// Replication lag of replicas, measured at most once.
+	lag      map[string]int64
+	lagError error
+	measured bool
+}
+
+// unavailable returns the number of instances in the set of rollout that are
+// not available, including those replaced just now.
+func (rollout *instanceSetRollout) unavailable() int {
+	return int(initialize.FromPointer(rollout.spec.Replicas)) - rollout.available + rollout.redeployed
+}
+
+func (rollout *instanceSetRollout) wait(reason, message string) {
+	if rollout.waiting == "" {
+		rollout.waiting, rollout.message = reason, message
+	}
+}
+
+// rolloutAllowed returns whether or not another replica in the set of rollout
+// can be replaced according to its strategy. When it cannot, the reason is
+// recorded in rollout.
+func (r *Reconciler) rolloutAllowed(
+	ctx context.Context, instances *observedInstances, rollout *instanceSetRollout,
+) bool {
+	strategy := rollout.spec.RolloutStrategy
+	replicas := int(initialize.FromPointer(rollout.spec.Replicas))
+
+	// Scale percentages down, but always allow at least one.
+	allowed := 1
+	if strategy.MaxUnavailable != nil {
+		scaled, err := intstr.GetScaledValueFromIntOrPercent(strategy.MaxUnavailable, replicas, false)
+		if err == nil && scaled > 1 {
+			allowed = scaled
+		}
+	}
+	if unavailable := rollout.unavailable(); unavailable >= allowed {
+		rollout.wait("MaxUnavailable",
+			fmt.Sprintf("%d of %d instances are unavailable", unavailable, replicas))
+		return false
+	}
+
+	// Wait between Pods by looking at when replaced Pods became ready.
+	if pause := time.Duration(initialize.FromPointer(strategy.PauseSeconds)) * time.Second; pause > 0 {
+		if rollout.redeployed > 0 {
+			rollout.wait("Pause", "waiting between Pods")
+			return false
+		}
+		for _, instance := range rollout.updated {
+			for _, condition := range instance.Pods[0].Status.Conditions {
+				if condition.Type == corev1.PodReady &&
+					condition.Status == corev1.ConditionTrue &&
+					time.Since(condition.LastTransitionTime.Time) < pause {
+					rollout.wait("Pause", fmt.Sprintf(
+						"waiting %v after %v became ready", pause, instance.Name))
+					return false
+				}
+			}
+		}
+	}
+
+	// Wait for replaced replicas to catch up to the primary.
+	if strategy.MaxReplicationLag != nil {
+		if !rollout.measured {
+			rollout.measured = true
+			rollout.lagError = errors.New("no running primary")
+
+			if pod, _ := instances.writablePod(naming.ContainerDatabase); pod != nil {
+				exec := func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+					return r.PodExec(ctx, pod.Namespace, pod.Name, naming.ContainerDatabase, stdin, stdout, stderr, command...)
+				}
+				rollout.lag, rollout.lagError = postgres.ReplicationLag(ctx, exec)
+			}
+		}
+		if rollout.lagError != nil {
+			rollout.wait("ReplicationLag",
+				fmt.Sprintf("unable to measure replication lag: %v", rollout.lagError))
+			return false
+		}
+
+		threshold := strategy.MaxReplicationLag.Value()
+		for _, instance := range rollout.updated {
+			if primary, known := instance.IsPrimary(); !known || primary {
+				continue
+			}
+			if lag, streaming := rollout.lag[instance.Pods[0].Name]; !streaming {
+				rollout.wait("ReplicationLag",
+					fmt.Sprintf("waiting for %v to be streaming", instance.Name))
+				return false
+			} else if lag > threshold {
+				rollout.wait("ReplicationLag",
+					fmt.Sprintf("waiting for %v to be within %v of the primary",
+						instance.Name, strategy.MaxReplicationLag))
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// rolloutRequeue returns how long to wait before checking again on a rollout
+// or storage migration that is waiting for time to pass or replicas to catch
+// up. Those things do not cause events that trigger another reconcile.
+func rolloutRequeue(cluster *v1beta1.PostgresCluster) time.Duration {
+	for _, status := range cluster.Status.InstanceSets {
+		if status.Rollout != nil &&
+			(status.Rollout.Waiting == "Pause" || status.Rollout.Waiting == "ReplicationLag") {
+			return 10 * time.Second
+		}
+		if len(status.StorageMigration) > 0 {
+			return 10 * time.Second
+		}
+	}
+	return 0
+}
+
+// instanceSetMigration tracks moving the instances of one set to data volumes
+// that match its spec. Each instance on another storage class, or on a larger
+// volume when shrinking, is replaced by a new instance that syncs as a replica
+// before the old instance and its volumes are deleted.
+type instanceSetMigration struct {
+	spec *v1beta1.PostgresInstanceSetSpec
+
+	// Instances with a data volume that does not match the spec, and
+	// instances with a matching data volume or without a data volume yet.
+	stale, current []*Instance
+
+	// The storage class of each data volume, by instance name.
+	classes map[string]string
+
+	// What about each stale data volume does not match, by instance name.
+	reasons map[string]string
+}
+
+// surge returns the number of instances needed beyond the replicas of the set
+// while migration is in progress.
+func (migration *instanceSetMigration) surge() int {
+	if migration == nil {
+		return 0
+	}
+	return len(migration.stale)
+}
+
+// isStale returns whether or not the instance named name is being replaced.
+func (migration *instanceSetMigration) isStale(name string) bool {
+	if migration != nil {
+		for _, instance := range migration.stale {
+			if instance.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// observeStorageMigrations compares the data volumes of instances to the
+// storage class of their instance set. It returns a migration for each set
+// that has instances on another storage class. Sets that do not specify a
+// storage class use the default and are not migrated. Sets in shrink also
+// migrate instances with data volumes larger than their spec requests.
+func observeStorageMigrations(
+	cluster *v1beta1.PostgresCluster, observed *observedInstances,
+	clusterVolumes []corev1.PersistentVolumeClaim, shrink map[string]bool,
+) map[string]*instanceSetMigration {
+	classes := make(map[string]string)
+	volumes := make(map[string]*corev1.PersistentVolumeClaim)
+	for i := range clusterVolumes {
+		pvc := &clusterVolumes[i]
+		if pvc.GetLabels()[naming.LabelRole] == naming.RolePostgresData {
+			volumes[pvc.GetLabels()[naming.LabelInstance]] = pvc
+
+			if pvc.Spec.StorageClassName != nil {
+				classes[pvc.GetLabels()[naming.LabelInstance]] = *pvc.Spec.StorageClassName
+			}
+		}
+	}
+
+	migrations := make(map[string]*instanceSetMigration)
+	for i := range cluster.Spec.InstanceSets {
+		set := &cluster.Spec.InstanceSets[i]
+		desired := set.DataVolumeClaimSpec.StorageClassName
+
+		if desired == nil && !shrink[set.Name] {
+			continue
+		}
+
+		migration := &instanceSetMigration{
+			spec: set, classes: classes, reasons: make(map[string]string),
+		}
+
+		for _, instance := range observed.bySet[set.Name] {
+			class, found := classes[instance.Name]
+			pvc := volumes[instance.Name]
+
+			var reason string
+			switch {
+			case desired != nil && found && class != *desired:
+				reason = fmt.Sprintf("storage class %q", class)
+			case shrink[set.Name] && pvc != nil && oversizedVolume(set, pvc):
+				reason = fmt.Sprintf("volume size %v", pvc.Spec.Resources.Requests.Storage())
+			}
+
+			switch {
+			case reason == "":
+				migration.current = append(migration.current, instance)
+
+			// An instance without a StatefulSet is already being deleted.
+			case instance.Runner != nil:
+				migration.stale = append(migration.stale, instance)
+				migration.reasons[instance.Name] = reason
+			}
+		}
+
+		if len(migration.stale) > 0 {
+			migrations[set.Name] = migration
+		}
+	}
+
+	return migrations
+}
+
+// volumeShrinkMaxPercent is the most of a smaller data volume that data can
+// use for a shrink to proceed. It matches the usage at which AutoGrowVolumes
+// suggests a larger volume so that new volumes do not grow right away.
+const volumeShrinkMaxPercent = 75
+
+// oversizedVolume returns whether or not pvc requests more storage than the
+// data volume spec of set.
+func oversizedVolume(
+	set *v1beta1.PostgresInstanceSetSpec, pvc *corev1.PersistentVolumeClaim,
+) bool {
+	request := set.DataVolumeClaimSpec.Resources.Requests.Storage()
+	return !request.IsZero() && pvc.Spec.Resources.Requests.Storage().Cmp(*request) > 0
+}
+
+// observeVolumeShrink reports in the status of each set with dataVolumeShrink
+// how much of its requested storage the data on the primary would use. It
+// returns the sets that can replace instances with smaller data volumes.
+func (r *Reconciler) observeVolumeShrink(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	observed *observedInstances, clusterVolumes []corev1.PersistentVolumeClaim,
+) map[string]bool {
+	var used int64
+	var usedError error
+	var measured bool
+
+	// Measure the primary at most once, and only when needed.
+	measure := func() {
+		if !measured {
+			measured = true
+			usedError = errors.New("no running primary")
+
+			if pod, _ := observed.writablePod(naming.ContainerDatabase); pod != nil {
+				exec := func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+					return r.PodExec(ctx, pod.Namespace, pod.Name, naming.ContainerDatabase, stdin, stdout, stderr, command...)
+				}
+				used, usedError = postgres.DataVolumeUsed(ctx, exec)
+			}
+		}
+	}
+
+	shrink := make(map[string]bool)
+	for i := range cluster.Spec.InstanceSets {
+		set := &cluster.Spec.InstanceSets[i]
+		if set.DataVolumeShrink != v1beta1.VolumeShrinkDryRun &&
+			set.DataVolumeShrink != v1beta1.VolumeShrinkEnabled {
+			continue
+		}
+
+		var oversized int32
+		for j := range clusterVolumes {
+			pvc := &clusterVolumes[j]
+			if pvc.GetDeletionTimestamp() == nil &&
+				pvc.GetLabels()[naming.LabelRole] == naming.RolePostgresData &&
+				pvc.GetLabels()[naming.LabelInstanceSet] == set.Name &&
+				oversizedVolume(set, pvc) {
+				oversized++
+			}
+		}
+		if oversized == 0 {
+			continue
+		}
+
+		request := set.DataVolumeClaimSpec.Resources.Requests.Storage()
+		projection := &v1beta1.PostgresVolumeShrinkStatus{
+			Oversized: oversized,
+			Request:   request,
+		}
+
+		if measure(); usedError != nil {
+			projection.Message = fmt.Sprintf("unable to measure data volume: %v", usedError)
+		} else {
+			// Round up so that data fits when it is reported to fit.
+			percent := (used*100 + request.Value() - 1) / request.Value()
+
+			projection.Used = resource.NewQuantity(used, resource.BinarySI)
+			projection.ProjectedUsagePercent = initialize.Int32(int32(min(percent, math.MaxInt32))) //nolint:gosec
+			projection.Fits = percent <= volumeShrinkMaxPercent
+			projection.Message = fmt.Sprintf(
+				"data would use %d%% of %v; at most %d%% is allowed",
+				percent, request, volumeShrinkMaxPercent)
+		}
+
+		previous := instanceSetStatus(cluster, set.Name).DataVolumeShrink
+
+		if set.DataVolumeShrink == v1beta1.VolumeShrinkEnabled {
+			shrink[set.Name] = projection.Fits
+
+			// Report when the shrink becomes blocked rather than on every reconcile.
+			if !projection.Fits && (previous == nil || previous.Fits) {
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "VolumeShrinkBlocked",
+					"Data volumes of %v/%v cannot shrink: %s",
+					cluster.Name, set.Name, projection.Message)
+			}
+		}
+
+		for j := range cluster.Status.InstanceSets {
+			if cluster.Status.InstanceSets[j].Name == set.Name {
+				cluster.Status.InstanceSets[j].DataVolumeShrink = projection
+			}
+		}
+	}
+
+	return shrink
+}
+
+// migrateInstanceStorage retires instances on a previous storage class once
+// their replacements are ready and streaming from the primary. A primary on
+// the previous storage class switches over to one of its replacements during
+// a maintenance window. Progress is reported in the status of each set.
+func (r *Reconciler) migrateInstanceStorage(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	observed *observedInstances, migrations map[string]*instanceSetMigration,
+) error {
+	var err error
+	var lag map[string]int64
+	var lagError error
+	var measured bool
+
+	// Measure replication lag at most once, and only when needed.
+	streaming := func(instance *Instance) bool {
+		if primary, known := instance.IsPrimary(); known && primary {
+			return true
+		}
+		if !measured {
+			measured = true
+			lagError = errors.New("no running primary")
+
+			if pod, _ := observed.writablePod(naming.ContainerDatabase); pod != nil {
+				exec := func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+					return r.PodExec(ctx, pod.Namespace, pod.Name, naming.ContainerDatabase, stdin, stdout, stderr, command...)
+				}
+				lag, lagError = postgres.ReplicationLag(ctx, exec)
+			}
+		}
+		_, ok := lag[instance.Pods[0].Name]
+		return lagError == nil && ok
+	}
+
+	for i := range cluster.Status.InstanceSets {
+		status := &cluster.Status.InstanceSets[i]
+		status.StorageMigration = nil
+
+		migration := migrations[status.Name]
+		if migration == nil {
+			continue
+		}
+
+		report := func(instance *Instance, phase, message string) {
+			status.StorageMigration = append(status.StorageMigration,
+				v1beta1.PostgresInstanceStorageMigrationStatus{
+					Name:             instance.Name,
+					StorageClassName: migration.classes[instance.Name],
+					Phase:            phase,
+					Message:          message,
+				})
+		}
+
+		// Replacements are synced when they are ready and streaming.
+		var candidate *Instance
+		var synced int
+		for _, instance := range migration.current {
+			ready, known := instance.IsReady()
+			terminating, _ := instance.IsTerminating()
+
+			if !known || !ready || terminating || len(instance.Pods) != 1 || !streaming(instance) {
+				report(instance, v1beta1.StorageMigrationSyncing,
+					"waiting to be ready and streaming from the primary")
+				continue
+			}
+
+			report(instance, v1beta1.StorageMigrationSynced, "")
+			synced++
+
+			if primary, known := instance.IsPrimary(); candidate == nil && known && !primary {
+				candidate = instance
+			}
+		}
+
+		replicas := int(initialize.FromPointer(migration.spec.Replicas))
+		for _, instance := range migration.stale {
+			primary, known := instance.IsPrimary()
+			primary = primary && known
+
+			switch {
+			case err != nil:
+				report(instance, v1beta1.StorageMigrationReplacing, "")
+
+			case synced < replicas:
+				report(instance, v1beta1.StorageMigrationReplacing, fmt.Sprintf(
+					"waiting for %d of %d replacements to sync", replicas-synced, replicas))
+
+			case primary && !maintenanceAllowed(cluster, time.Now()):
+				report(instance, v1beta1.StorageMigrationReplacing,
+					"waiting for a maintenance window")
+
+			case primary && (candidate == nil || len(instance.Pods) != 1):
+				report(instance, v1beta1.StorageMigrationReplacing,
+					"waiting for a replica to switch over to")
+
+			case primary:
+				report(instance, v1beta1.StorageMigrationSwitchingOver, fmt.Sprintf(
+					"switching over to %v", candidate.Name))
+				err = r.switchoverInstance(ctx, instance, candidate)
+
+				if err == nil {
+					r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "StorageMigrationSwitchover",
+						"Switched over from %v to %v to move off of %s",
+						instance.Name, candidate.Name, migration.reasons[instance.Name])
+				}
+
+			default:
+				report(instance, v1beta1.StorageMigrationRetiring, "")
+				err = r.deleteInstance(ctx, cluster, instance.Name)
+
+				if err == nil {
+					r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "StorageMigrationRetired",
+						"Retired %v and its volumes on %s",
+						instance.Name, migration.reasons[instance.Name])
+				}
+			}
+		}
+
+		sort.Slice(status.StorageMigration, func(i, j int) bool {
+			return status.StorageMigration[i].Name < status.StorageMigration[j].Name
+		})
+	}
+
+	return err
+}
+
+// switchoverInstance asks Patroni to change the primary from the Pod of
+// instance to the Pod of candidate.
+func (r *Reconciler) switchoverInstance(
+	ctx context.Context, instance, candidate *Instance,
+) error {
+	pod := instance.Pods[0]
+	exec := func(_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+		return r.PodExec(ctx, pod.Namespace, pod.Name, naming.ContainerDatabase, stdin, stdout, stderr, command...)
+	}
+
+	ctx, span := r.Tracer.Start(ctx, "patroni-change-primary")
+	defer span.End()
+
+	success, err := patroni.Executor(exec).ChangePrimaryAndWait(ctx, pod.Name, candidate.Pods[0].Name)
+	if err = errors.WithStack(err); err == nil && !success {
+		err = errors.New("unable to switchover")
 	}
 
 	span.RecordError(err)
@@ -970,11 +1768,13 @@ func (r *Reconciler) rolloutInstances(
 //     have 0 replicas
 //
 // If either of these conditions are met then the primary instance will be
-// marked for deletion and deleted after all other instances
+// marked for deletion and deleted after all other instances. Instances being
+// replaced by a storage migration are left to migrateInstanceStorage.
 func (r *Reconciler) scaleDownInstances(
 	ctx context.Context,
 	cluster *v1beta1.PostgresCluster,
 	observedInstances *observedInstances,
+	migrations map[string]*instanceSetMigration,
 ) error {
 
 	// want defines the number of replicas we want for each instance set
@@ -985,7 +1785,13 @@ func (r *Reconciler) scaleDownInstances(
 
 	// grab all pods for the cluster using the observed instances
 	pods := []corev1.Pod{}
+	stale := sets.NewString()
 	for instanceIndex := range observedInstances.forCluster {
+		if instance := observedInstances.forCluster[instanceIndex]; instance.Spec != nil &&
+			migrations[instance.Spec.Name].isStale(instance.Name) {
+			stale.Insert(instance.Name)
+			continue
+		}
 		for podIndex := range observedInstances.forCluster[instanceIndex].Pods {
 			pods = append(pods, *observedInstances.forCluster[instanceIndex].Pods[podIndex])
 		}
@@ -999,7 +1805,8 @@ func (r *Reconciler) scaleDownInstances(
 
 	for _, instance := range observedInstances.forCluster {
 		for _, pod := range instance.Pods {
-			if !namesToKeep.Has(pod.Labels[naming.LabelInstance]) {
+			if !namesToKeep.Has(pod.Labels[naming.LabelInstance]) &&
+				!stale.Has(pod.Labels[naming.LabelInstance]) {
 				err := r.deleteInstance(ctx, cluster, pod.Labels[naming.LabelInstance])
 				if err != nil {
 					return err
@@ -1054,15 +1861,16 @@ func podsToKeep(instances []corev1.Pod, want map[string]int) []corev1.Pod {
 // +kubebuilder:rbac:groups="apps",resources="statefulsets",verbs={list}
 
 // scaleUpInstances updates the cluster until the number of instances matches
-// the cluster spec
+// the cluster spec plus surge
 func (r *Reconciler) scaleUpInstances(
 	ctx context.Context,
 	cluster *v1beta1.PostgresCluster,
 	observed *observedInstances,
 	set *v1beta1.PostgresInstanceSetSpec,
+	surge int,
 	clusterConfigMap *corev1.ConfigMap,
 	clusterReplicationSecret *corev1.Secret,
-	rootCA *pki.RootCertificateAuthority,
+	rootCA pki.Issuer,
 	clusterPodService *corev1.Service,
 	instanceServiceAccount *corev1.ServiceAccount,
 	patroniLeaderService *corev1.Service,
@@ -1071,7 +1879,7 @@ func (r *Reconciler) scaleUpInstances(
 	numInstancePods int,
 	clusterVolumes []corev1.PersistentVolumeClaim,
 	exporterQueriesConfig, exporterWebConfig *corev1.ConfigMap,
-	backupsSpecFound bool,
+	backupsSpecFound bool, pgParameters postgres.Parameters,
 ) ([]*appsv1.StatefulSet, error) {
 	log := logging.FromContext(ctx)
 
@@ -1087,7 +1895,7 @@ func (r *Reconciler) scaleUpInstances(
 	}
 	// While there are fewer instances than specified, generate another empty one
 	// and append it.
-	for len(instances) < int(*set.Replicas) {
+	for len(instances) < int(*set.Replicas)+surge {
 		var span trace.Span
 		ctx, span = r.Tracer.Start(ctx, "generateInstanceName")
 		next := naming.GenerateInstance(cluster, set)
@@ -1116,7 +1924,7 @@ func (r *Reconciler) scaleUpInstances(
 			rootCA, clusterPodService, instanceServiceAccount,
 			patroniLeaderService, primaryCertificate, instances[i],
 			numInstancePods, clusterVolumes, exporterQueriesConfig, exporterWebConfig,
-			backupsSpecFound,
+			backupsSpecFound, pgParameters,
 		)
 	}
 	if err == nil {
@@ -1137,7 +1945,7 @@ func (r *Reconciler) reconcileInstance(
 	spec *v1beta1.PostgresInstanceSetSpec,
 	clusterConfigMap *corev1.ConfigMap,
 	clusterReplicationSecret *corev1.Secret,
-	rootCA *pki.RootCertificateAuthority,
+	rootCA pki.Issuer,
 	clusterPodService *corev1.Service,
 	instanceServiceAccount *corev1.ServiceAccount,
 	patroniLeaderService *corev1.Service,
@@ -1146,7 +1954,7 @@ func (r *Reconciler) reconcileInstance(
 	numInstancePods int,
 	clusterVolumes []corev1.PersistentVolumeClaim,
 	exporterQueriesConfig, exporterWebConfig *corev1.ConfigMap,
-	backupsSpecFound bool,
+	backupsSpecFound bool, pgParameters postgres.Parameters,
 ) error {
 	log := logging.FromContext(ctx).WithValues("instance", instance.Name)
 	ctx = logging.NewContext(ctx, log)
@@ -1171,7 +1979,7 @@ func (r *Reconciler) reconcileInstance(
 	)
 
 	if err == nil {
-		instanceConfigMap, err = r.reconcileInstanceConfigMap(ctx, cluster, spec, instance)
+		instanceConfigMap, err = r.reconcileInstanceConfigMap(ctx, cluster, spec, instance, pgParameters)
 	}
 	if err == nil {
 		instanceCertificates, err = r.reconcileInstanceCertificates(
@@ -1381,7 +2189,7 @@ func addPGBackRestToInstancePodSpec(
 // files (etc) that apply to instance of cluster.
 func (r *Reconciler) reconcileInstanceConfigMap(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, spec *v1beta1.PostgresInstanceSetSpec,
-	instance *appsv1.StatefulSet,
+	instance *appsv1.StatefulSet, pgParameters postgres.Parameters,
 ) (*corev1.ConfigMap, error) {
 	instanceConfigMap := &corev1.ConfigMap{ObjectMeta: naming.InstanceConfigMap(instance)}
 	instanceConfigMap.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
@@ -1402,7 +2210,7 @@ func (r *Reconciler) reconcileInstanceConfigMap(
 		})
 
 	if err == nil {
-		err = patroni.InstanceConfigMap(ctx, cluster, spec, instanceConfigMap)
+		err = patroni.InstanceConfigMap(ctx, cluster, spec, pgParameters, instanceConfigMap)
 	}
 	if err == nil {
 		err = errors.WithStack(r.apply(ctx, instanceConfigMap))
@@ -1419,7 +2227,7 @@ func (r *Reconciler) reconcileInstanceConfigMap(
 func (r *Reconciler) reconcileInstanceCertificates(
 	ctx context.Context, cluster *v1beta1.PostgresCluster,
 	spec *v1beta1.PostgresInstanceSetSpec, instance *appsv1.StatefulSet,
-	root *pki.RootCertificateAuthority,
+	root pki.Issuer,
 ) (*corev1.Secret, error) {
 	existing := &corev1.Secret{ObjectMeta: naming.InstanceCertificates(instance)}
 	err := errors.WithStack(client.IgnoreNotFound(
@@ -1459,12 +2267,12 @@ func (r *Reconciler) reconcileInstanceCertificates(
 	}
 	if err == nil {
 		err = patroni.InstanceCertificates(ctx,
-			root.Certificate, leafCert.Certificate,
+			root.Bundle(), leafCert.Certificate,
 			leafCert.PrivateKey, instanceCerts)
 	}
 	if err == nil {
 		err = pgbackrest.InstanceCertificates(ctx, cluster,
-			root.Certificate, leafCert.Certificate, leafCert.PrivateKey,
+			root.Bundle(), leafCert.Certificate, leafCert.PrivateKey,
 			instanceCerts)
 	}
 	if err == nil {
@@ -6,9 +6,11 @@ package postgrescluster
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -18,7 +20,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -641,3 +645,261 @@ func TestReconcilerRolloutInstances(t *testing.T) {
 			}))
 	})
 }
+
+func TestReconcilerRolloutInstancesStrategy(t *testing.T) {
+	ctx := context.Background()
+	reconciler := &Reconciler{}
+	reconciler.Tracer = otel.Tracer(t.Name())
+
+	accumulate := func(on *[]*Instance) func(context.Context, *Instance) error {
+		return func(_ context.Context, i *Instance) error { *on = append(*on, i); return nil }
+	}
+
+	// instance returns a ready instance of set. Its Pod matches its template
+	// when revision is "gamma".
+	instance := func(set *v1beta1.PostgresInstanceSetSpec, name, revision, role string) *Instance {
+		return &Instance{
+			Name: name,
+			Spec: set,
+			Pods: []*corev1.Pod{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name + "-0",
+					Labels: map[string]string{
+						"controller-revision-hash":               revision,
+						"postgres-operator.crunchydata.com/role": role,
+					},
+					Annotations: map[string]string{
+						"status": `{"role":"` + role + `"}`,
+					},
+				},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{{
+						Type:   corev1.PodReady,
+						Status: corev1.ConditionTrue,
+					}},
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name: "database",
+						State: corev1.ContainerState{
+							Running: new(corev1.ContainerStateRunning),
+						},
+					}},
+				},
+			}},
+			Runner: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 1},
+				Status: appsv1.StatefulSetStatus{
+					ObservedGeneration: 1,
+					UpdateRevision:     "gamma",
+				},
+			},
+		}
+	}
+
+	setup := func(replicas int32, strategy v1beta1.PostgresInstanceSetRolloutStrategy) *v1beta1.PostgresCluster {
+		cluster := new(v1beta1.PostgresCluster)
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{{
+			Name: "00", Replicas: &replicas, RolloutStrategy: &strategy,
+		}}
+		cluster.Status.InstanceSets = []v1beta1.PostgresInstanceSetStatus{{Name: "00"}}
+		return cluster
+	}
+
+	t.Run("MaxUnavailable", func(t *testing.T) {
+		cluster := setup(5, v1beta1.PostgresInstanceSetRolloutStrategy{
+			MaxUnavailable: initialize.Pointer(intstr.FromString("50%")),
+		})
+		set := &cluster.Spec.InstanceSets[0]
+		observed := &observedInstances{forCluster: []*Instance{
+			instance(set, "a", "beta", "replica"),
+			instance(set, "b", "beta", "replica"),
+			instance(set, "c", "beta", "replica"),
+			instance(set, "d", "beta", "replica"),
+			instance(set, "e", "gamma", "master"),
+		}}
+
+		var redeploys []*Instance
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Equal(t, len(redeploys), 2)
+		assert.Equal(t, redeploys[0].Name, "a")
+		assert.Equal(t, redeploys[1].Name, "b")
+
+		status := cluster.Status.InstanceSets[0].Rollout
+		assert.Assert(t, status != nil)
+		assert.Equal(t, status.Pending, int32(2))
+		assert.Equal(t, status.Waiting, "MaxUnavailable")
+		assert.Equal(t, rolloutRequeue(cluster), time.Duration(0))
+	})
+
+	t.Run("MaxUnavailableAcrossSets", func(t *testing.T) {
+		cluster := setup(3, v1beta1.PostgresInstanceSetRolloutStrategy{
+			MaxUnavailable: initialize.Pointer(intstr.FromInt(2)),
+		})
+		cluster.Spec.InstanceSets = append(cluster.Spec.InstanceSets, cluster.Spec.InstanceSets[0])
+		cluster.Spec.InstanceSets[1].Name = "01"
+		cluster.Status.InstanceSets = append(cluster.Status.InstanceSets,
+			v1beta1.PostgresInstanceSetStatus{Name: "01"})
+
+		zero, one := &cluster.Spec.InstanceSets[0], &cluster.Spec.InstanceSets[1]
+		observed := &observedInstances{forCluster: []*Instance{
+			instance(zero, "a", "beta", "replica"),
+			instance(zero, "b", "beta", "replica"),
+			instance(zero, "c", "gamma", "master"),
+			instance(one, "d", "beta", "replica"),
+			instance(one, "e", "beta", "replica"),
+			instance(one, "f", "gamma", "replica"),
+		}}
+
+		// An unavailable instance in one set holds back the others.
+		observed.forCluster[5].Pods[0].Status.Conditions[0].Status = corev1.ConditionFalse
+
+		var redeploys []*Instance
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Equal(t, len(redeploys), 1)
+		assert.Equal(t, redeploys[0].Spec.Name, "01")
+
+		status := cluster.Status.InstanceSets[0].Rollout
+		assert.Assert(t, status != nil)
+		assert.Equal(t, status.Pending, int32(2))
+		assert.Equal(t, status.Waiting, "MaxUnavailable")
+		assert.Assert(t, cmp.Contains(status.Message, "other sets"))
+
+		// Only one set at a time replaces instances.
+		observed.forCluster[5].Pods[0].Status.Conditions[0].Status = corev1.ConditionTrue
+
+		redeploys = nil
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Equal(t, len(redeploys), 2)
+		assert.Equal(t, redeploys[0].Spec.Name, redeploys[1].Spec.Name)
+	})
+
+	t.Run("Pause", func(t *testing.T) {
+		cluster := setup(3, v1beta1.PostgresInstanceSetRolloutStrategy{
+			MaxUnavailable: initialize.Pointer(intstr.FromInt(2)),
+			PauseSeconds:   initialize.Int32(60),
+		})
+		set := &cluster.Spec.InstanceSets[0]
+
+		recent := instance(set, "a", "gamma", "replica")
+		recent.Pods[0].Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-5 * time.Second))
+
+		observed := &observedInstances{forCluster: []*Instance{
+			recent,
+			instance(set, "b", "beta", "replica"),
+			instance(set, "c", "gamma", "master"),
+		}}
+
+		var redeploys []*Instance
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Equal(t, len(redeploys), 0)
+		assert.Equal(t, cluster.Status.InstanceSets[0].Rollout.Waiting, "Pause")
+		assert.Assert(t, rolloutRequeue(cluster) > 0)
+
+		// Replace one Pod at a time after the pause.
+		recent.Pods[0].Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-time.Hour))
+		observed.forCluster = append(observed.forCluster, instance(set, "d", "beta", "replica"))
+
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Equal(t, len(redeploys), 1)
+		assert.Equal(t, cluster.Status.InstanceSets[0].Rollout.Waiting, "Pause")
+	})
+
+	t.Run("ReplicationLag", func(t *testing.T) {
+		cluster := setup(3, v1beta1.PostgresInstanceSetRolloutStrategy{
+			MaxUnavailable:    initialize.Pointer(intstr.FromInt(2)),
+			MaxReplicationLag: initialize.Pointer(resource.MustParse("1Mi")),
+		})
+		set := &cluster.Spec.InstanceSets[0]
+		observed := &observedInstances{forCluster: []*Instance{
+			instance(set, "a", "gamma", "replica"),
+			instance(set, "b", "beta", "replica"),
+			instance(set, "c", "gamma", "master"),
+		}}
+
+		lag := `{"a-0": 5000000}`
+		reconciler := &Reconciler{Tracer: reconciler.Tracer}
+		reconciler.PodExec = func(
+			_ context.Context, _, pod, _ string, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			assert.Equal(t, pod, "c-0", "expected to exec on the primary")
+			_, _ = stdout.Write([]byte(lag))
+			return nil
+		}
+
+		var redeploys []*Instance
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Equal(t, len(redeploys), 0)
+		assert.Equal(t, cluster.Status.InstanceSets[0].Rollout.Waiting, "ReplicationLag")
+		assert.Assert(t, cmp.Contains(cluster.Status.InstanceSets[0].Rollout.Message, "within 1Mi"))
+
+		lag = `{"a-0": 1024}`
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Equal(t, len(redeploys), 1)
+		assert.Equal(t, redeploys[0].Name, "b")
+		assert.Assert(t, cluster.Status.InstanceSets[0].Rollout == nil)
+	})
+
+	t.Run("PrimaryLast", func(t *testing.T) {
+		cluster := setup(3, v1beta1.PostgresInstanceSetRolloutStrategy{
+			MaxUnavailable: initialize.Pointer(intstr.FromInt(3)),
+			Primary:        v1beta1.RolloutPrimaryLast,
+		})
+		set := &cluster.Spec.InstanceSets[0]
+		observed := &observedInstances{forCluster: []*Instance{
+			instance(set, "a", "beta", "master"),
+			instance(set, "b", "beta", "replica"),
+			instance(set, "c", "gamma", "replica"),
+		}}
+
+		var redeploys []*Instance
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Equal(t, len(redeploys), 1)
+		assert.Equal(t, redeploys[0].Name, "b")
+		assert.Equal(t, cluster.Status.InstanceSets[0].Rollout.Waiting, "PrimaryLast")
+	})
+
+	t.Run("SwitchoverFirst", func(t *testing.T) {
+		cluster := setup(3, v1beta1.PostgresInstanceSetRolloutStrategy{
+			MaxUnavailable: initialize.Pointer(intstr.FromInt(3)),
+			Primary:        v1beta1.RolloutSwitchoverFirst,
+		})
+		set := &cluster.Spec.InstanceSets[0]
+		observed := &observedInstances{forCluster: []*Instance{
+			instance(set, "a", "beta", "master"),
+			instance(set, "b", "beta", "replica"),
+			instance(set, "c", "beta", "replica"),
+		}}
+
+		var redeploys []*Instance
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Equal(t, len(redeploys), 1, "expected to stop after the primary")
+		assert.Equal(t, redeploys[0].Name, "a")
+		assert.Equal(t, cluster.Status.InstanceSets[0].Rollout.Pending, int32(2))
+	})
+
+	t.Run("MaintenanceWindow", func(t *testing.T) {
+		cluster := setup(3, v1beta1.PostgresInstanceSetRolloutStrategy{
+			MaxUnavailable: initialize.Pointer(intstr.FromInt(3)),
+		})
+		cluster.Spec.MaintenanceWindows = []v1beta1.MaintenanceWindow{{
+			Schedule: fmt.Sprintf("0 %d * * *", (time.Now().UTC().Hour()+12)%24),
+			Duration: metav1.Duration{Duration: time.Hour},
+		}}
+		set := &cluster.Spec.InstanceSets[0]
+		observed := &observedInstances{forCluster: []*Instance{
+			instance(set, "a", "beta", "replica"),
+			instance(set, "b", "beta", "replica"),
+			instance(set, "c", "gamma", "master"),
+		}}
+		observed.forCluster[0].Pods[0].Status.Conditions[0].Status = corev1.ConditionFalse
+
+		var redeploys []*Instance
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Equal(t, len(redeploys), 1, "expected unavailable instances to be replaced")
+		assert.Equal(t, redeploys[0].Name, "a")
+
+		status := cluster.Status.InstanceSets[0].Rollout
+		assert.Assert(t, status != nil)
+		assert.Equal(t, status.Pending, int32(1))
+		assert.Equal(t, status.Waiting, "MaintenanceWindow")
+	})
+}
@@ -7,6 +7,7 @@ package postgrescluster
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -32,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
@@ -375,6 +377,96 @@ func TestStoreDesiredRequest(t *testing.T) {
 	})
 }
 
+func TestStoreDesiredVolumes(t *testing.T) {
+	ctx := context.Background()
+
+	limited := corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+		Resources: corev1.VolumeResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("10Gi"),
+			}}}
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Name = "rhino"
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{{
+		Name:               "red",
+		WALVolumeClaimSpec: &limited,
+		TablespaceVolumes: []v1beta1.TablespaceVolume{
+			{Name: "castle", DataVolumeClaimSpec: limited},
+			{Name: "trial"},
+		},
+	}}
+
+	instance := func(name string, annotations map[string]string) *Instance {
+		pod := &corev1.Pod{}
+		pod.Annotations = annotations
+		return &Instance{Name: name, Pods: []*corev1.Pod{pod}}
+	}
+
+	t.Run("Suggested", func(t *testing.T) {
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+
+		var status v1beta1.PostgresInstanceSetStatus
+		reconciler.storeDesiredVolumes(ctx, cluster, "red", []*Instance{
+			instance("red-abc", map[string]string{
+				"suggested-pgwal-pvc-size":             "3Gi",
+				"suggested-tablespace-castle-pvc-size": "4Gi",
+				"suggested-tablespace-trial-pvc-size":  "5Gi",
+			}),
+			instance("red-def", nil),
+		}, v1beta1.PostgresInstanceSetStatus{}, &status)
+
+		assert.DeepEqual(t, status.DesiredPGWALVolume, map[string]string{"red-abc": "3Gi"})
+		assert.DeepEqual(t, status.DesiredTablespaceVolumes, map[string]map[string]string{
+			"castle": {"red-abc": "4Gi"},
+			"trial":  {"red-abc": "5Gi"},
+		})
+
+		// Only volumes with a limit grow.
+		assert.Equal(t, len(recorder.Events), 2)
+		assert.Equal(t, recorder.Events[0].Reason, "VolumeAutoGrow")
+		assert.Equal(t, recorder.Events[0].Note, "pgWAL volume expansion to 3Gi requested for rhino/red.")
+		assert.Equal(t, recorder.Events[1].Reason, "VolumeAutoGrow")
+		assert.Equal(t, recorder.Events[1].Note, "castle tablespace volume expansion to 4Gi requested for rhino/red.")
+	})
+
+	t.Run("Previous", func(t *testing.T) {
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+
+		previous := v1beta1.PostgresInstanceSetStatus{
+			DesiredPGWALVolume: map[string]string{"red-abc": "3Gi"},
+			DesiredTablespaceVolumes: map[string]map[string]string{
+				"castle": {"red-abc": "4Gi"},
+			},
+		}
+
+		var status v1beta1.PostgresInstanceSetStatus
+		reconciler.storeDesiredVolumes(ctx, cluster, "red", []*Instance{
+			instance("red-abc", nil),
+		}, previous, &status)
+
+		assert.DeepEqual(t, status.DesiredPGWALVolume, previous.DesiredPGWALVolume)
+		assert.DeepEqual(t, status.DesiredTablespaceVolumes, previous.DesiredTablespaceVolumes)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("NotInSpec", func(t *testing.T) {
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+
+		var status v1beta1.PostgresInstanceSetStatus
+		reconciler.storeDesiredVolumes(ctx, cluster, "blue", []*Instance{
+			instance("blue-abc", map[string]string{"suggested-pgwal-pvc-size": "3Gi"}),
+		}, v1beta1.PostgresInstanceSetStatus{}, &status)
+
+		assert.Assert(t, status.DesiredPGWALVolume == nil)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+}
+
 func TestWritablePod(t *testing.T) {
 	container := "container"
 
@@ -1919,6 +2011,26 @@ func TestFindAvailableInstanceNames(t *testing.T) {
 				naming.LabelInstanceSet: "instance1",
 				naming.LabelInstance:    "instance1-def"}}}},
 		expectedInstanceNames: []string{},
+	}, {
+		set: v1beta1.PostgresInstanceSetSpec{Name: "instance1",
+			DataVolumeClaimSpec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: initialize.String("fast")}},
+		fakeObservedInstances: newObservedInstances(
+			&v1beta1.PostgresCluster{Spec: v1beta1.PostgresClusterSpec{
+				InstanceSets: []v1beta1.PostgresInstanceSetSpec{{Name: "instance1"}},
+			}},
+			[]appsv1.StatefulSet{},
+			[]corev1.Pod{},
+		),
+		fakeClusterVolumes: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{
+			Name: "instance1-def-ghi",
+			Labels: map[string]string{
+				naming.LabelRole:        naming.RolePostgresData,
+				naming.LabelInstanceSet: "instance1",
+				naming.LabelInstance:    "instance1-def"}},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: initialize.String("slow")}}},
+		expectedInstanceNames: []string{},
 	}}
 
 	for _, tc := range testCases {
@@ -2150,3 +2262,510 @@ func TestCleanupDisruptionBudgets(t *testing.T) {
 		})
 	})
 }
+
+func TestObserveApplyLag(t *testing.T) {
+	ctx := context.Background()
+
+	running := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: name},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: naming.ContainerDatabase,
+					State: corev1.ContainerState{
+						Running: new(corev1.ContainerStateRunning),
+					},
+				}},
+			},
+		}
+	}
+
+	observed := &observedInstances{bySet: map[string][]*Instance{
+		"slow": {
+			{Name: "slow-a", Pods: []*corev1.Pod{running("slow-a-0")}},
+			{Name: "slow-b", Pods: []*corev1.Pod{running("slow-b-0")}},
+			{Name: "slow-c", Pods: []*corev1.Pod{{}}},
+		},
+	}}
+
+	lags := map[string]string{"slow-a-0": "30\n", "slow-b-0": "90\n"}
+	reconciler := &Reconciler{}
+	reconciler.PodExec = func(
+		ctx context.Context, namespace, pod, container string, stdin io.Reader, stdout, _ io.Writer, command ...string,
+	) error {
+		assert.Equal(t, namespace, "ns1")
+		assert.Equal(t, container, naming.ContainerDatabase)
+		_, _ = stdout.Write([]byte(lags[pod]))
+		return nil
+	}
+
+	t.Run("NotDelayed", func(t *testing.T) {
+		set := &v1beta1.PostgresInstanceSetSpec{Name: "slow"}
+		assert.Assert(t, reconciler.observeApplyLag(ctx, set, observed) == nil)
+	})
+
+	t.Run("Delayed", func(t *testing.T) {
+		set := &v1beta1.PostgresInstanceSetSpec{
+			Name: "slow", RecoveryMinApplyDelay: initialize.String("1min"),
+		}
+
+		lag := reconciler.observeApplyLag(ctx, set, observed)
+		assert.Assert(t, lag != nil)
+		assert.Equal(t, *lag, int64(90))
+	})
+}
+
+func TestReconcileLoadBalanceLabels(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Name: "fast"},
+		{Name: "slow", RecoveryMinApplyDelay: initialize.String("1h")},
+	}
+
+	pod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1", Name: name, Labels: labels,
+		}}
+	}
+	labeled := map[string]string{naming.LabelLoadBalance: "true"}
+
+	setup := func(t *testing.T, pods ...*corev1.Pod) *Reconciler {
+		objects := make([]client.Object, len(pods))
+		for i := range pods {
+			objects[i] = pods[i].DeepCopy()
+		}
+		reconciler := &Reconciler{}
+		reconciler.Client = fake.NewClientBuilder().
+			WithScheme(runtime.Scheme).WithObjects(objects...).Build()
+		return reconciler
+	}
+
+	t.Run("Delayed", func(t *testing.T) {
+		fast, slow := pod("fast-0", nil), pod("slow-0", labeled)
+		reconciler := setup(t, fast, slow)
+		observed := &observedInstances{forCluster: []*Instance{
+			{Spec: &cluster.Spec.InstanceSets[0], Pods: []*corev1.Pod{fast}},
+			{Spec: &cluster.Spec.InstanceSets[1], Pods: []*corev1.Pod{slow}},
+		}}
+
+		assert.NilError(t, reconciler.reconcileLoadBalanceLabels(ctx, cluster, observed))
+		assert.Assert(t, hasLoadBalanceLabels(observed))
+
+		stored := &corev1.Pod{}
+		assert.NilError(t, reconciler.Client.Get(ctx, client.ObjectKeyFromObject(fast), stored))
+		assert.Equal(t, stored.Labels[naming.LabelLoadBalance], "true")
+
+		assert.NilError(t, reconciler.Client.Get(ctx, client.ObjectKeyFromObject(slow), stored))
+		_, ok := stored.Labels[naming.LabelLoadBalance]
+		assert.Assert(t, !ok)
+	})
+
+	t.Run("NotDelayed", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.InstanceSets = cluster.Spec.InstanceSets[:1]
+
+		fast := pod("fast-0", labeled)
+		reconciler := setup(t, fast)
+		observed := &observedInstances{forCluster: []*Instance{
+			{Spec: &cluster.Spec.InstanceSets[0], Pods: []*corev1.Pod{fast}},
+		}}
+
+		assert.NilError(t, reconciler.reconcileLoadBalanceLabels(ctx, cluster, observed))
+
+		stored := &corev1.Pod{}
+		assert.NilError(t, reconciler.Client.Get(ctx, client.ObjectKeyFromObject(fast), stored))
+		_, ok := stored.Labels[naming.LabelLoadBalance]
+		assert.Assert(t, !ok)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		fast := pod("fast-0", nil)
+		reconciler := setup(t)
+		observed := &observedInstances{forCluster: []*Instance{
+			{Spec: &cluster.Spec.InstanceSets[0], Pods: []*corev1.Pod{fast}},
+		}}
+
+		// Pods that no longer exist are not labeled, and the replica Service
+		// continues to select all replicas.
+		assert.NilError(t, reconciler.reconcileLoadBalanceLabels(ctx, cluster, observed))
+		assert.Assert(t, !hasLoadBalanceLabels(observed))
+	})
+}
+
+func TestObserveStorageMigrations(t *testing.T) {
+	volume := func(instance, class string) corev1.PersistentVolumeClaim {
+		pvc := corev1.PersistentVolumeClaim{}
+		pvc.Labels = map[string]string{
+			naming.LabelInstance: instance,
+			naming.LabelRole:     naming.RolePostgresData,
+		}
+		if class != "" {
+			pvc.Spec.StorageClassName = initialize.String(class)
+		}
+		return pvc
+	}
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Name: "default"},
+		{Name: "fast", DataVolumeClaimSpec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: initialize.String("fast"),
+		}},
+	}
+
+	observed := &observedInstances{bySet: map[string][]*Instance{
+		"default": {
+			{Name: "default-a", Runner: &appsv1.StatefulSet{}},
+		},
+		"fast": {
+			{Name: "fast-a", Runner: &appsv1.StatefulSet{}},
+			{Name: "fast-b", Runner: &appsv1.StatefulSet{}},
+			{Name: "fast-c", Runner: &appsv1.StatefulSet{}},
+			{Name: "fast-d"},
+		},
+	}}
+
+	t.Run("Unchanged", func(t *testing.T) {
+		migrations := observeStorageMigrations(cluster, observed,
+			[]corev1.PersistentVolumeClaim{
+				volume("default-a", "slow"),
+				volume("fast-a", "fast"),
+				volume("fast-b", ""),
+			}, nil)
+
+		assert.Equal(t, len(migrations), 0)
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		migrations := observeStorageMigrations(cluster, observed,
+			[]corev1.PersistentVolumeClaim{
+				volume("default-a", "slow"),
+				volume("fast-a", "slow"),
+				volume("fast-b", "fast"),
+				volume("fast-d", "slow"),
+			}, nil)
+
+		assert.Equal(t, len(migrations), 1)
+		migration := migrations["fast"]
+		assert.Assert(t, migration != nil)
+		assert.Equal(t, migration.surge(), 1)
+		assert.Assert(t, migration.isStale("fast-a"))
+
+		// Instances without a data volume are new; those without a
+		// StatefulSet are already being deleted.
+		var current []string
+		for _, instance := range migration.current {
+			current = append(current, instance.Name)
+		}
+		assert.DeepEqual(t, current, []string{"fast-b", "fast-c"})
+	})
+
+	t.Run("Shrink", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.InstanceSets[0].DataVolumeClaimSpec.Resources.Requests =
+			corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}
+
+		large := volume("default-a", "")
+		large.Spec.Resources.Requests =
+			corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("2Gi")}
+
+		migrations := observeStorageMigrations(cluster, observed,
+			[]corev1.PersistentVolumeClaim{large}, map[string]bool{"default": false})
+		assert.Equal(t, len(migrations), 0)
+
+		migrations = observeStorageMigrations(cluster, observed,
+			[]corev1.PersistentVolumeClaim{large}, map[string]bool{"default": true})
+		assert.Equal(t, len(migrations), 1)
+
+		migration := migrations["default"]
+		assert.Assert(t, migration != nil)
+		assert.Assert(t, migration.isStale("default-a"))
+		assert.Equal(t, migration.reasons["default-a"], "volume size 2Gi")
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		var migration *instanceSetMigration
+		assert.Equal(t, migration.surge(), 0)
+		assert.Assert(t, !migration.isStale("any"))
+	})
+}
+
+func TestReconcilerMigrateInstanceStorage(t *testing.T) {
+	ctx := context.Background()
+
+	pod := func(name string, primary, ready bool) *corev1.Pod {
+		pod := &corev1.Pod{}
+		pod.Namespace, pod.Name = "ns1", name
+		pod.Status.Conditions = []corev1.PodCondition{{
+			Type: corev1.PodReady, Status: corev1.ConditionFalse,
+		}}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  naming.ContainerDatabase,
+			State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+		}}
+		if ready {
+			pod.Status.Conditions[0].Status = corev1.ConditionTrue
+		}
+		if primary {
+			pod.Labels = map[string]string{naming.LabelRole: naming.RolePatroniLeader}
+			pod.Annotations = map[string]string{"status": `{"role":"master"}`}
+		}
+		return pod
+	}
+
+	setup := func(t *testing.T, oldPrimary, newReady bool, lag string) (
+		*Reconciler, *v1beta1.PostgresCluster, *events.Recorder, *[]string,
+	) {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Namespace, cluster.Name = "ns1", "hippo"
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{{
+			Name: "00", Replicas: initialize.Int32(1),
+		}}
+		cluster.Status.InstanceSets = []v1beta1.PostgresInstanceSetStatus{{Name: "00"}}
+
+		old := &Instance{Name: "old", Runner: &appsv1.StatefulSet{},
+			Pods: []*corev1.Pod{pod("old-0", oldPrimary, true)}}
+		replacement := &Instance{Name: "new", Runner: &appsv1.StatefulSet{},
+			Pods: []*corev1.Pod{pod("new-0", !oldPrimary, newReady)}}
+		observed := &observedInstances{forCluster: []*Instance{old, replacement}}
+
+		migrations := map[string]*instanceSetMigration{"00": {
+			spec:    &cluster.Spec.InstanceSets[0],
+			stale:   []*Instance{old},
+			current: []*Instance{replacement},
+			classes: map[string]string{"old": "slow", "new": "fast"},
+			reasons: map[string]string{"old": `storage class "slow"`},
+		}}
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+		reconciler.Client = fake.NewClientBuilder().WithScheme(runtime.Scheme).Build()
+		reconciler.Tracer = otel.Tracer(t.Name())
+
+		var commands []string
+		reconciler.PodExec = func(
+			ctx context.Context, namespace, pod, container string, _ io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			commands = append(commands, pod+": "+strings.Join(command, " "))
+			if command[0] == "patronictl" {
+				_, _ = stdout.Write([]byte("switched over"))
+			} else {
+				_, _ = stdout.Write([]byte(lag))
+			}
+			return nil
+		}
+
+		assert.NilError(t, reconciler.migrateInstanceStorage(ctx, cluster, observed, migrations))
+		return reconciler, cluster, recorder, &commands
+	}
+
+	t.Run("Syncing", func(t *testing.T) {
+		_, cluster, recorder, _ := setup(t, true, false, `{}`)
+
+		assert.DeepEqual(t, cluster.Status.InstanceSets[0].StorageMigration,
+			[]v1beta1.PostgresInstanceStorageMigrationStatus{
+				{
+					Name: "new", StorageClassName: "fast", Phase: "Syncing",
+					Message: "waiting to be ready and streaming from the primary",
+				},
+				{
+					Name: "old", StorageClassName: "slow", Phase: "Replacing",
+					Message: "waiting for 1 of 1 replacements to sync",
+				},
+			})
+		assert.Equal(t, len(recorder.Events), 0)
+		assert.Equal(t, rolloutRequeue(cluster), 10*time.Second)
+	})
+
+	t.Run("NotStreaming", func(t *testing.T) {
+		_, cluster, _, commands := setup(t, true, true, `{}`)
+
+		assert.Equal(t, cluster.Status.InstanceSets[0].StorageMigration[0].Phase, "Syncing")
+		assert.Equal(t, len(*commands), 1)
+		assert.Assert(t, cmp.Contains((*commands)[0], "old-0: "))
+	})
+
+	t.Run("Switchover", func(t *testing.T) {
+		_, cluster, recorder, commands := setup(t, true, true, `{"new-0":0}`)
+
+		assert.DeepEqual(t, cluster.Status.InstanceSets[0].StorageMigration,
+			[]v1beta1.PostgresInstanceStorageMigrationStatus{
+				{Name: "new", StorageClassName: "fast", Phase: "Synced"},
+				{
+					Name: "old", StorageClassName: "slow", Phase: "SwitchingOver",
+					Message: "switching over to new",
+				},
+			})
+
+		assert.Equal(t, len(*commands), 2)
+		assert.Assert(t, cmp.Contains((*commands)[1], "old-0: patronictl switchover"))
+		assert.Assert(t, cmp.Contains((*commands)[1], "--candidate=new-0"))
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "StorageMigrationSwitchover")
+		assert.Equal(t, recorder.Events[0].Note,
+			`Switched over from old to new to move off of storage class "slow"`)
+	})
+
+	t.Run("Retire", func(t *testing.T) {
+		_, cluster, recorder, commands := setup(t, false, true, `{}`)
+
+		assert.DeepEqual(t, cluster.Status.InstanceSets[0].StorageMigration,
+			[]v1beta1.PostgresInstanceStorageMigrationStatus{
+				{Name: "new", StorageClassName: "fast", Phase: "Synced"},
+				{Name: "old", StorageClassName: "slow", Phase: "Retiring"},
+			})
+
+		// The primary is not measured.
+		assert.Equal(t, len(*commands), 0)
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "StorageMigrationRetired")
+		assert.Equal(t, recorder.Events[0].Note,
+			`Retired old and its volumes on storage class "slow"`)
+	})
+}
+
+func TestReconcilerObserveVolumeShrink(t *testing.T) {
+	ctx := context.Background()
+
+	volume := func(instance, request string) corev1.PersistentVolumeClaim {
+		pvc := corev1.PersistentVolumeClaim{}
+		pvc.Labels = map[string]string{
+			naming.LabelInstance:    instance,
+			naming.LabelInstanceSet: "00",
+			naming.LabelRole:        naming.RolePostgresData,
+		}
+		pvc.Spec.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceStorage: resource.MustParse(request),
+		}
+		return pvc
+	}
+
+	primary := &corev1.Pod{}
+	primary.Namespace, primary.Name = "ns1", "old-0"
+	primary.Annotations = map[string]string{"status": `{"role":"master"}`}
+	primary.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  naming.ContainerDatabase,
+		State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+	}}
+	observed := &observedInstances{forCluster: []*Instance{
+		{Name: "old", Pods: []*corev1.Pod{primary}},
+	}}
+
+	setup := func(t *testing.T, mode, used string) (
+		*Reconciler, *v1beta1.PostgresCluster, *events.Recorder,
+	) {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Namespace, cluster.Name = "ns1", "hippo"
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{{
+			Name: "00", DataVolumeShrink: mode,
+			DataVolumeClaimSpec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("10Gi"),
+					},
+				},
+			},
+		}}
+		cluster.Status.InstanceSets = []v1beta1.PostgresInstanceSetStatus{{Name: "00"}}
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+		reconciler.PodExec = func(
+			ctx context.Context, namespace, pod, container string, _ io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			assert.Equal(t, pod, "old-0")
+			assert.Equal(t, command[0], "df")
+			_, _ = stdout.Write([]byte("Used\n" + used + "\n"))
+			return nil
+		}
+		return reconciler, cluster, recorder
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		reconciler, cluster, _ := setup(t, "", "0")
+
+		shrink := reconciler.observeVolumeShrink(ctx, cluster, observed,
+			[]corev1.PersistentVolumeClaim{volume("old", "20Gi")})
+
+		assert.Equal(t, len(shrink), 0)
+		assert.Assert(t, cluster.Status.InstanceSets[0].DataVolumeShrink == nil)
+	})
+
+	t.Run("NotOversized", func(t *testing.T) {
+		reconciler, cluster, _ := setup(t, "Enabled", "0")
+
+		shrink := reconciler.observeVolumeShrink(ctx, cluster, observed,
+			[]corev1.PersistentVolumeClaim{volume("old", "10Gi")})
+
+		assert.Equal(t, len(shrink), 0)
+		assert.Assert(t, cluster.Status.InstanceSets[0].DataVolumeShrink == nil)
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		reconciler, cluster, recorder := setup(t, "DryRun", "3221225472")
+
+		shrink := reconciler.observeVolumeShrink(ctx, cluster, observed,
+			[]corev1.PersistentVolumeClaim{volume("old", "20Gi"), volume("new", "10Gi")})
+
+		assert.Equal(t, len(shrink), 0)
+		assert.Equal(t, len(recorder.Events), 0)
+		assert.Assert(t, cmp.MarshalMatches(cluster.Status.InstanceSets[0].DataVolumeShrink, `
+fits: true
+message: data would use 30% of 10Gi; at most 75% is allowed
+oversized: 1
+projectedUsagePercent: 30
+request: 10Gi
+used: 3Gi
+`))
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		reconciler, cluster, recorder := setup(t, "Enabled", "3221225472")
+
+		shrink := reconciler.observeVolumeShrink(ctx, cluster, observed,
+			[]corev1.PersistentVolumeClaim{volume("old", "20Gi")})
+
+		assert.DeepEqual(t, shrink, map[string]bool{"00": true})
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("TooFull", func(t *testing.T) {
+		reconciler, cluster, recorder := setup(t, "Enabled", "9663676416")
+
+		shrink := reconciler.observeVolumeShrink(ctx, cluster, observed,
+			[]corev1.PersistentVolumeClaim{volume("old", "20Gi")})
+
+		assert.DeepEqual(t, shrink, map[string]bool{"00": false})
+		assert.Equal(t, cluster.Status.InstanceSets[0].DataVolumeShrink.Fits, false)
+		assert.Equal(t, *cluster.Status.InstanceSets[0].DataVolumeShrink.ProjectedUsagePercent, int32(90))
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "VolumeShrinkBlocked")
+		assert.Equal(t, recorder.Events[0].Note, "Data volumes of hippo/00 cannot shrink: "+
+			"data would use 90% of 10Gi; at most 75% is allowed")
+
+		// The event is not repeated while the shrink stays blocked.
+		_ = reconciler.observeVolumeShrink(ctx, cluster, observed,
+			[]corev1.PersistentVolumeClaim{volume("old", "20Gi")})
+		assert.Equal(t, len(recorder.Events), 1)
+	})
+
+	t.Run("NoPrimary", func(t *testing.T) {
+		reconciler, cluster, _ := setup(t, "DryRun", "0")
+
+		shrink := reconciler.observeVolumeShrink(ctx, cluster, &observedInstances{},
+			[]corev1.PersistentVolumeClaim{volume("old", "20Gi")})
+
+		assert.Equal(t, len(shrink), 0)
+		assert.Assert(t, cmp.MarshalMatches(cluster.Status.InstanceSets[0].DataVolumeShrink, `
+fits: false
+message: 'unable to measure data volume: no running primary'
+oversized: 1
+request: 10Gi
+`))
+	})
+}
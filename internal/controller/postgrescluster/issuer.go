@@ -0,0 +1,406 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/pki"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// certificateIssuerRetry is how long to wait before checking again for a
+// certificate authority or certificates that are not ready.
+const certificateIssuerRetry = 10 * time.Second
+
+// certManagerCertificateLabel is the value of the cluster certificate label on
+// cert-manager Certificates of a cluster and their Secrets.
+const certManagerCertificateLabel = "cert-manager"
+
+// errCertificatePending is returned when cert-manager has not yet issued a
+// certificate. Reconcile waits for it without backing off.
+var errCertificatePending = errors.New("waiting for cert-manager to issue a certificate")
+
+// certificateIssuerSecret returns the name of the Secret that holds the
+// certificate authority of cluster.Spec.CertificateIssuer. It is empty when
+// cert-manager issues each certificate.
+func certificateIssuerSecret(cluster *v1beta1.PostgresCluster) string {
+	if issuer := cluster.Spec.CertificateIssuer; issuer != nil && issuer.CASecret != nil {
+		return issuer.CASecret.Name
+	}
+	return ""
+}
+
+// +kubebuilder:rbac:groups="",resources="secrets",verbs={get,list}
+
+// reconcileCertificateIssuer returns the issuer of leaf certificates described
+// by cluster.Spec.CertificateIssuer. It returns nil, and reports why in the
+// "CertificateIssuerReady" condition, when the Secret of an intermediate
+// certificate authority does not exist.
+func (r *Reconciler) reconcileCertificateIssuer(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) (
+	pki.Issuer, error,
+) {
+	if cluster.Spec.CertificateIssuer.CertManager != nil {
+		return r.newCertManagerIssuer(ctx, cluster)
+	}
+
+	secret := &corev1.Secret{}
+	secret.Namespace, secret.Name = cluster.Namespace, certificateIssuerSecret(cluster)
+	err := errors.WithStack(r.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret))
+
+	if apierrors.IsNotFound(err) {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    v1beta1.CertificateIssuerReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  "SecretNotFound",
+			Message: fmt.Sprintf("Waiting for the certificate authority in Secret %q.", secret.Name),
+
+			ObservedGeneration: cluster.GetGeneration(),
+		})
+		return nil, nil
+	}
+
+	var authority *pki.RootCertificateAuthority
+	if err == nil {
+		authority, err = pki.NewCertificateAuthority(
+			secret.Data[clusterCertFile], secret.Data[clusterKeyFile], secret.Data[rootCertFile])
+
+		if err != nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidCertificateIssuer",
+				"Unable to use the certificate authority in Secret %q: %v", secret.Name, err)
+			meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+				Type:    v1beta1.CertificateIssuerReady,
+				Status:  metav1.ConditionFalse,
+				Reason:  "InvalidAuthority",
+				Message: fmt.Sprintf("Unable to use the certificate authority in Secret %q.", secret.Name),
+
+				ObservedGeneration: cluster.GetGeneration(),
+			})
+			err = errors.WithStack(err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    v1beta1.CertificateIssuerReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Ready",
+		Message: fmt.Sprintf("Using the certificate authority in Secret %q.", secret.Name),
+
+		ObservedGeneration: cluster.GetGeneration(),
+	})
+
+	authority.Policy = r.certificatePolicy(cluster)
+	return authority, nil
+}
+
+// setCertManagerCondition reports in cluster whether or not cert-manager has
+// issued every certificate requested while reconciling, given the error, if
+// any, of that reconcile.
+func setCertManagerCondition(cluster *v1beta1.PostgresCluster, err error) {
+	if cluster.Spec.CertificateIssuer == nil || cluster.Spec.CertificateIssuer.CertManager == nil {
+		return
+	}
+
+	switch {
+	case errors.Is(err, errCertificatePending):
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    v1beta1.CertificateIssuerReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  "CertificatesPending",
+			Message: err.Error(),
+
+			ObservedGeneration: cluster.GetGeneration(),
+		})
+	case err == nil:
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    v1beta1.CertificateIssuerReady,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Ready",
+			Message: "cert-manager issued every certificate.",
+
+			ObservedGeneration: cluster.GetGeneration(),
+		})
+	}
+}
+
+// certManagerIssuer issues leaf certificates by asking cert-manager for them.
+// Each leaf is a cert-manager Certificate that stores its key pair in a Secret
+// of the same name; cert-manager renews it before it expires. It lives for one
+// reconcile of one cluster.
+// - https://cert-manager.io/docs/usage/certificate/
+type certManagerIssuer struct {
+	ctx        context.Context
+	cluster    *v1beta1.PostgresCluster
+	reconciler *Reconciler
+	policy     pki.Policy
+
+	// bundle holds the authorities that cert-manager reports in the Secrets of
+	// leaf certificates of cluster.
+	bundle pki.CertificateBundle
+
+	// requested holds the names of Certificates asked for during this reconcile.
+	requested []string
+}
+
+var _ pki.Issuer = (*certManagerIssuer)(nil)
+
+// newCertManagerIssuer returns an issuer that asks cert-manager for the leaf
+// certificates of cluster. It trusts the authorities of any certificates that
+// cert-manager has already issued for cluster.
+func (r *Reconciler) newCertManagerIssuer(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) (*certManagerIssuer, error) {
+	issuer := &certManagerIssuer{
+		ctx: ctx, cluster: cluster, reconciler: r,
+		policy: r.certificatePolicy(cluster),
+	}
+
+	secrets := &corev1.SecretList{}
+	err := errors.WithStack(r.Client.List(ctx, secrets,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{
+			naming.LabelCluster:            cluster.Name,
+			naming.LabelClusterCertificate: certManagerCertificateLabel,
+		}))
+
+	if err == nil {
+		sort.Slice(secrets.Items, func(i, j int) bool {
+			return secrets.Items[i].Name < secrets.Items[j].Name
+		})
+		for i := range secrets.Items {
+			issuer.trust(secrets.Items[i].Data[rootCertFile])
+		}
+	}
+	return issuer, err
+}
+
+// trust adds the authorities in PEM encoded chain to the bundle of issuer.
+func (issuer *certManagerIssuer) trust(chain []byte) {
+	var bundle pki.CertificateBundle
+	if bundle.UnmarshalText(chain) == nil {
+		for _, authority := range bundle {
+			if !slices.ContainsFunc(issuer.bundle, authority.Equal) {
+				issuer.bundle = append(issuer.bundle, authority)
+			}
+		}
+	}
+}
+
+// Bundle returns the authorities that cert-manager reports in the Secrets of
+// certificates it issued for the cluster.
+func (issuer *certManagerIssuer) Bundle() pki.CertificateBundle { return issuer.bundle }
+
+// +kubebuilder:rbac:groups="cert-manager.io",resources="certificates",verbs={create,patch}
+
+// RegenerateLeafWhenNecessary asks cert-manager for a certificate with
+// commonName and dnsNames and returns it once cert-manager has issued it. The
+// leaf that was issued before is ignored; cert-manager decides when to renew.
+// It returns errCertificatePending until the certificate is issued.
+func (issuer *certManagerIssuer) RegenerateLeafWhenNecessary(
+	_ *pki.LeafCertificate, commonName string, dnsNames []string,
+) (*pki.LeafCertificate, error) {
+	ctx, cluster := issuer.ctx, issuer.cluster
+
+	suffix, err := safeHash32(func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, commonName, strings.Join(dnsNames, " "))
+		return err
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	certificate := issuer.certificate(naming.ClusterLeafCertificate(cluster, suffix), commonName, dnsNames)
+	issuer.requested = append(issuer.requested, certificate.GetName())
+
+	err = errors.WithStack(issuer.reconciler.setControllerReference(cluster, certificate))
+	if err == nil {
+		err = errors.WithStack(issuer.reconciler.patch(ctx, certificate, client.Apply, client.ForceOwnership))
+	}
+
+	secret := &corev1.Secret{}
+	secret.Namespace, secret.Name = certificate.GetNamespace(), certificate.GetName()
+	if err == nil {
+		err = errors.WithStack(issuer.reconciler.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret))
+	}
+	if apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(errCertificatePending, "Certificate %q", secret.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The Secret holds the previous certificate while cert-manager issues one
+	// with a different subject. Some issuers add the common name to the DNS
+	// names.
+	leaf := &pki.LeafCertificate{}
+	if leaf.Certificate.UnmarshalText(secret.Data[clusterCertFile]) != nil ||
+		leaf.PrivateKey.UnmarshalText(secret.Data[clusterKeyFile]) != nil ||
+		leaf.Certificate.CommonName() != commonName ||
+		!sets.New(leaf.Certificate.DNSNames()...).HasAll(dnsNames...) {
+		return nil, errors.Wrapf(errCertificatePending, "Certificate %q", secret.Name)
+	}
+
+	issuer.trust(secret.Data[rootCertFile])
+	return leaf, nil
+}
+
+// certificate returns the cert-manager Certificate called meta for a leaf
+// with commonName and dnsNames. Its key and lifetime follow the policy of
+// issuer.
+func (issuer *certManagerIssuer) certificate(
+	meta metav1.ObjectMeta, commonName string, dnsNames []string,
+) *unstructured.Unstructured {
+	cluster := issuer.cluster
+	spec := cluster.Spec.CertificateIssuer.CertManager
+
+	kind, group := spec.Kind, spec.Group
+	if kind == "" {
+		kind = "Issuer"
+	}
+	if group == "" {
+		group = "cert-manager.io"
+	}
+
+	// A new key with every renewal, like those the operator issues.
+	privateKey := map[string]any{"rotationPolicy": "Always"}
+	switch issuer.policy.KeyAlgorithm {
+	case pki.KeyAlgorithmECDSAP384:
+		privateKey["algorithm"], privateKey["size"] = "ECDSA", int64(384)
+	case pki.KeyAlgorithmRSA2048:
+		privateKey["algorithm"], privateKey["size"] = "RSA", int64(2048)
+	case pki.KeyAlgorithmRSA4096:
+		privateKey["algorithm"], privateKey["size"] = "RSA", int64(4096)
+	case pki.KeyAlgorithmEd25519:
+		privateKey["algorithm"] = "Ed25519"
+	default:
+		privateKey["algorithm"], privateKey["size"] = "ECDSA", int64(256)
+	}
+
+	labels := naming.Merge(
+		cluster.Spec.Metadata.GetLabelsOrNil(),
+		map[string]string{
+			naming.LabelCluster:            cluster.Name,
+			naming.LabelClusterCertificate: certManagerCertificateLabel,
+		})
+
+	object := map[string]any{
+		"secretName": meta.Name,
+		"privateKey": privateKey,
+
+		// Leaf certificates identify servers and authenticate clients, such
+		// as replicas and pgBackRest.
+		"usages": []any{"digital signature", "key encipherment", "server auth", "client auth"},
+		"issuerRef": map[string]any{
+			"name": spec.Name, "kind": kind, "group": group,
+		},
+
+		// Label the Secret so that changes to it are noticed.
+		"secretTemplate": map[string]any{"labels": toAnyMap(labels)},
+	}
+	if commonName != "" {
+		object["commonName"] = commonName
+	}
+	if len(dnsNames) > 0 {
+		names := make([]any, len(dnsNames))
+		for i := range dnsNames {
+			names[i] = dnsNames[i]
+		}
+		object["dnsNames"] = names
+	}
+
+	duration := issuer.policy.LeafLifetime
+	if spec.Duration != nil {
+		duration = spec.Duration.Duration
+	}
+	if duration > 0 {
+		object["duration"] = duration.String()
+	}
+	if renew := issuer.policy.RenewBefore; renew > 0 && (duration == 0 || renew < duration) {
+		object["renewBefore"] = renew.String()
+	}
+
+	intent := &unstructured.Unstructured{Object: map[string]any{"spec": object}}
+	intent.SetAPIVersion("cert-manager.io/v1")
+	intent.SetKind("Certificate")
+	intent.SetNamespace(meta.Namespace)
+	intent.SetName(meta.Name)
+	intent.SetAnnotations(naming.Merge(cluster.Spec.Metadata.GetAnnotationsOrNil()))
+	intent.SetLabels(labels)
+
+	return intent
+}
+
+// toAnyMap returns the values of m as the interface type used by unstructured
+// objects.
+func toAnyMap(m map[string]string) map[string]any {
+	result := make(map[string]any, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+// +kubebuilder:rbac:groups="cert-manager.io",resources="certificates",verbs={list,delete}
+// +kubebuilder:rbac:groups="",resources="secrets",verbs={delete}
+
+// pruneCertManagerCertificates deletes the cert-manager Certificates of
+// cluster, and their Secrets, that issuer did not ask for. Call it only after
+// everything that needs a certificate has asked for one.
+func (r *Reconciler) pruneCertManagerCertificates(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, issuer *certManagerIssuer,
+) error {
+	certificates := &unstructured.UnstructuredList{}
+	certificates.SetAPIVersion("cert-manager.io/v1")
+	certificates.SetKind("CertificateList")
+
+	err := errors.WithStack(r.Client.List(ctx, certificates,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{
+			naming.LabelCluster:            cluster.Name,
+			naming.LabelClusterCertificate: certManagerCertificateLabel,
+		}))
+
+	for i := range certificates.Items {
+		certificate := &certificates.Items[i]
+		if err != nil || slices.Contains(issuer.requested, certificate.GetName()) {
+			continue
+		}
+		if !metav1.IsControlledBy(certificate, cluster) {
+			continue
+		}
+
+		err = client.IgnoreNotFound(r.deleteControlled(ctx, cluster, certificate))
+
+		// cert-manager leaves the Secret behind.
+		if err == nil {
+			secret := &corev1.Secret{}
+			secret.Namespace, secret.Name = certificate.GetNamespace(), certificate.GetName()
+			err = client.IgnoreNotFound(r.Client.Delete(ctx, secret))
+		}
+		err = errors.WithStack(err)
+	}
+	return err
+}
@@ -0,0 +1,224 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/internal/pki"
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/internal/testing/events"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestReconcileCertificateIssuer(t *testing.T) {
+	ctx := context.Background()
+
+	authority, err := pki.NewRootCertificateAuthority()
+	assert.NilError(t, err)
+	certificate, _ := authority.Certificate.MarshalText()
+	key, _ := authority.PrivateKey.MarshalText()
+
+	valid := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "corporate-ca"},
+		Data:       map[string][]byte{"tls.crt": certificate, "tls.key": key},
+	}
+	invalid := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "broken-ca"},
+		Data:       map[string][]byte{"tls.crt": certificate},
+	}
+
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &Reconciler{
+		Client: fake.NewClientBuilder().WithScheme(runtime.Scheme).
+			WithObjects(valid, invalid).Build(),
+		Recorder: recorder,
+	}
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+
+	issue := func(name string) (*v1beta1.PostgresCluster, pki.Issuer, error) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.CertificateIssuer = &v1beta1.CertificateIssuerSpec{
+			CASecret: &corev1.LocalObjectReference{Name: name},
+		}
+		issuer, err := reconciler.reconcileCertificateIssuer(ctx, cluster)
+		return cluster, issuer, err
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		cluster, issuer, err := issue("corporate-ca")
+		assert.NilError(t, err)
+		assert.Equal(t, len(recorder.Events), 0)
+
+		root, ok := issuer.(*pki.RootCertificateAuthority)
+		assert.Assert(t, ok)
+		assert.Assert(t, root.Certificate.Equal(authority.Certificate))
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.CertificateIssuerReady)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionTrue)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		recorder.Events = nil
+		cluster, issuer, err := issue("missing")
+		assert.NilError(t, err)
+		assert.Assert(t, issuer == nil, "expected no issuer, got %v", issuer)
+		assert.Equal(t, len(recorder.Events), 0)
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.CertificateIssuerReady)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionFalse)
+		assert.Equal(t, condition.Reason, "SecretNotFound")
+		assert.Assert(t, cmp.Contains(condition.Message, `"missing"`))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		recorder.Events = nil
+		cluster, _, err := issue("broken-ca")
+		assert.ErrorContains(t, err, "private key")
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "InvalidCertificateIssuer")
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, `"broken-ca"`))
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.CertificateIssuerReady)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Reason, "InvalidAuthority")
+	})
+}
+
+func TestCertManagerIssuer(t *testing.T) {
+	ctx := context.Background()
+
+	var applied *unstructured.Unstructured
+	cc := fake.NewClientBuilder().WithScheme(runtime.Scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(
+				ctx context.Context, c client.WithWatch, obj client.Object,
+				patch client.Patch, opts ...client.PatchOption,
+			) error {
+				applied = obj.(*unstructured.Unstructured)
+				return nil
+			},
+		}).Build()
+
+	reconciler := &Reconciler{Client: cc, Owner: client.FieldOwner(t.Name())}
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	cluster.Spec.CertificateIssuer = &v1beta1.CertificateIssuerSpec{
+		CertManager: &v1beta1.CertManagerIssuerSpec{Name: "vault"},
+	}
+
+	issuer, err := reconciler.newCertManagerIssuer(ctx, cluster)
+	assert.NilError(t, err)
+	assert.Equal(t, len(issuer.Bundle()), 0)
+
+	dnsNames := []string{"hippo-primary.ns1.svc", "hippo-primary"}
+
+	t.Run("Pending", func(t *testing.T) {
+		_, err := issuer.RegenerateLeafWhenNecessary(nil, dnsNames[0], dnsNames)
+		assert.Assert(t, errors.Is(err, errCertificatePending), "got %v", err)
+
+		assert.Assert(t, applied != nil)
+		assert.Equal(t, applied.GetKind(), "Certificate")
+		assert.Assert(t, cmp.Contains(applied.GetName(), "hippo-cert-"))
+		assert.DeepEqual(t, issuer.requested, []string{applied.GetName()})
+
+		// Each leaf is requested from cert-manager, not an authority.
+		spec := applied.Object["spec"].(map[string]any)
+		assert.Equal(t, spec["isCA"], nil)
+		assert.Equal(t, spec["secretName"], applied.GetName())
+		assert.Equal(t, spec["commonName"], dnsNames[0])
+		assert.DeepEqual(t, spec["dnsNames"], []any{dnsNames[0], dnsNames[1]})
+		assert.DeepEqual(t, spec["usages"],
+			[]any{"digital signature", "key encipherment", "server auth", "client auth"})
+		assert.DeepEqual(t, spec["issuerRef"], map[string]any{
+			"name": "vault", "kind": "Issuer", "group": "cert-manager.io",
+		})
+	})
+
+	t.Run("Issued", func(t *testing.T) {
+		authority, err := pki.NewRootCertificateAuthority()
+		assert.NilError(t, err)
+		leaf, err := authority.GenerateLeafCertificate(dnsNames[0], dnsNames)
+		assert.NilError(t, err)
+
+		secret := &corev1.Secret{}
+		secret.Namespace, secret.Name = "ns1", applied.GetName()
+		secret.Data = map[string][]byte{}
+		secret.Data["tls.crt"], _ = leaf.Certificate.MarshalText()
+		secret.Data["tls.key"], _ = leaf.PrivateKey.MarshalText()
+		secret.Data["ca.crt"], _ = authority.Certificate.MarshalText()
+		assert.NilError(t, cc.Create(ctx, secret))
+
+		issued, err := issuer.RegenerateLeafWhenNecessary(nil, dnsNames[0], dnsNames)
+		assert.NilError(t, err)
+		assert.Assert(t, issued.Certificate.Equal(leaf.Certificate))
+		assert.Assert(t, issued.PrivateKey.Equal(leaf.PrivateKey))
+
+		assert.Equal(t, len(issuer.Bundle()), 1)
+		assert.Assert(t, issuer.Bundle()[0].Equal(authority.Certificate))
+
+		// A different subject waits for cert-manager to issue another.
+		_, err = issuer.RegenerateLeafWhenNecessary(nil, "other", nil)
+		assert.Assert(t, errors.Is(err, errCertificatePending), "got %v", err)
+	})
+}
+
+func TestSetCertManagerCondition(t *testing.T) {
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Spec.CertificateIssuer = &v1beta1.CertificateIssuerSpec{
+		CertManager: &v1beta1.CertManagerIssuerSpec{Name: "vault"},
+	}
+
+	setCertManagerCondition(cluster, fmt.Errorf("some: %w", errCertificatePending))
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.CertificateIssuerReady)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Status, metav1.ConditionFalse)
+	assert.Equal(t, condition.Reason, "CertificatesPending")
+
+	// Other errors say nothing about cert-manager.
+	setCertManagerCondition(cluster, fmt.Errorf("other"))
+	condition = meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.CertificateIssuerReady)
+	assert.Equal(t, condition.Reason, "CertificatesPending")
+
+	setCertManagerCondition(cluster, nil)
+	condition = meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.CertificateIssuerReady)
+	assert.Equal(t, condition.Status, metav1.ConditionTrue)
+}
+
+func TestCertificateIssuerSecret(t *testing.T) {
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Name = "hippo"
+
+	cluster.Spec.CertificateIssuer = &v1beta1.CertificateIssuerSpec{
+		CertManager: &v1beta1.CertManagerIssuerSpec{Name: "vault"},
+	}
+	assert.Equal(t, certificateIssuerSecret(cluster), "")
+	assert.Assert(t, !referencesSecret(cluster, ""))
+
+	cluster.Spec.CertificateIssuer = &v1beta1.CertificateIssuerSpec{
+		CASecret: &corev1.LocalObjectReference{Name: "corporate-ca"},
+	}
+	assert.Equal(t, certificateIssuerSecret(cluster), "corporate-ca")
+	assert.Assert(t, referencesSecret(cluster, "corporate-ca"))
+	assert.Assert(t, !referencesSecret(cluster, "hippo-cluster-cert"))
+}
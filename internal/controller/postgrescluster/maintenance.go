@@ -0,0 +1,114 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crunchydata/postgres-operator/internal/maintenance"
+	"github.com/crunchydata/postgres-operator/internal/patroni"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// rolloutMaintenanceWindow is the reason a rollout waits for a maintenance window.
+const rolloutMaintenanceWindow = "MaintenanceWindow"
+
+// maintenanceAllowed returns whether or not disruptive work, such as replacing
+// Pods or restarting PostgreSQL, can happen in cluster at now.
+func maintenanceAllowed(cluster *v1beta1.PostgresCluster, now time.Time) bool {
+	open, _, _ := maintenance.Status(cluster.Spec.MaintenanceWindows, now)
+	return open
+}
+
+// validateMaintenanceWindows emits a warning event when cluster has maintenance
+// windows that cannot be interpreted. Those windows are never open.
+func (r *Reconciler) validateMaintenanceWindows(cluster *v1beta1.PostgresCluster) {
+	path := field.NewPath("spec", "maintenanceWindows")
+
+	for i, window := range cluster.Spec.MaintenanceWindows {
+		if _, err := maintenance.NewWindow(window); err != nil {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidMaintenanceWindow",
+				field.Invalid(path.Index(i), window, err.Error()).Error())
+		}
+	}
+}
+
+// pendingMaintenance returns the disruptive work in cluster that is waiting
+// for a maintenance window.
+func pendingMaintenance(cluster *v1beta1.PostgresCluster, instances *observedInstances) []string {
+	var work []string
+
+	for _, status := range cluster.Status.InstanceSets {
+		if status.Rollout != nil && status.Rollout.Waiting == rolloutMaintenanceWindow {
+			work = append(work, "replace Pods")
+			break
+		}
+	}
+
+	if _, candidate := preferredPrimarySwitchover(cluster, instances); candidate != nil {
+		work = append(work, "switch over to a preferred primary")
+	}
+
+	if instances != nil {
+		for _, instance := range instances.forCluster {
+			if len(instance.Pods) > 0 && patroni.PodRequiresRestart(instance.Pods[0]) {
+				work = append(work, "restart PostgreSQL")
+				break
+			}
+		}
+	}
+
+	return work
+}
+
+// setMaintenanceCondition reports in cluster the work that is waiting for a
+// maintenance window and when the next window opens. The condition is removed
+// when there is no such work.
+func setMaintenanceCondition(cluster *v1beta1.PostgresCluster, now time.Time, work []string) {
+	open, next, _ := maintenance.Status(cluster.Spec.MaintenanceWindows, now)
+
+	if open || len(work) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, v1beta1.MaintenancePending)
+		return
+	}
+
+	message := "Waiting for a maintenance window to " + strings.Join(work, ", ")
+	if !next.IsZero() {
+		message = "Waiting until " + next.UTC().Format(time.RFC3339) +
+			" to " + strings.Join(work, ", ")
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    v1beta1.MaintenancePending,
+		Status:  metav1.ConditionTrue,
+		Reason:  "MaintenanceWindowClosed",
+		Message: message,
+
+		ObservedGeneration: cluster.GetGeneration(),
+	})
+}
+
+// maintenanceRequeue returns how long to wait before the next maintenance
+// window opens when cluster has work waiting for it. The opening of a window
+// does not cause an event that triggers another reconcile.
+func maintenanceRequeue(cluster *v1beta1.PostgresCluster, now time.Time) time.Duration {
+	if meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.MaintenancePending) == nil {
+		return 0
+	}
+
+	_, next, _ := maintenance.Status(cluster.Spec.MaintenanceWindows, now)
+	if next.IsZero() {
+		return 0
+	}
+
+	// Arrive a moment after the window opens.
+	return next.Sub(now) + time.Second
+}
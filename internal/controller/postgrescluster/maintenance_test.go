@@ -0,0 +1,112 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/internal/testing/events"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestMaintenanceAllowed(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 10, 20, 0, 0, time.UTC)
+	cluster := v1beta1.NewPostgresCluster()
+
+	assert.Assert(t, maintenanceAllowed(cluster, now), "expected always without windows")
+
+	cluster.Spec.MaintenanceWindows = []v1beta1.MaintenanceWindow{{
+		Schedule: "0 10 * * *", Duration: metav1.Duration{Duration: time.Hour},
+	}}
+	assert.Assert(t, maintenanceAllowed(cluster, now))
+	assert.Assert(t, !maintenanceAllowed(cluster, now.Add(time.Hour)))
+}
+
+func TestValidateMaintenanceWindows(t *testing.T) {
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Name = "pg1"
+	cluster.Spec.MaintenanceWindows = []v1beta1.MaintenanceWindow{
+		{Schedule: "0 10 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+		{Schedule: "0 25 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+	}
+
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &Reconciler{Recorder: recorder}
+
+	reconciler.validateMaintenanceWindows(cluster)
+	assert.Equal(t, len(recorder.Events), 1)
+	assert.Equal(t, recorder.Events[0].Regarding.Name, cluster.Name)
+	assert.Equal(t, recorder.Events[0].Reason, "InvalidMaintenanceWindow")
+	assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "spec.maintenanceWindows[1]"))
+	assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "invalid hour"))
+}
+
+func TestPendingMaintenance(t *testing.T) {
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Status.InstanceSets = []v1beta1.PostgresInstanceSetStatus{
+		{Name: "00", Rollout: &v1beta1.PostgresInstanceSetRolloutStatus{
+			Pending: 1, Waiting: "MaxUnavailable",
+		}},
+	}
+
+	instances := &observedInstances{forCluster: []*Instance{
+		{Name: "one", Pods: []*corev1.Pod{{}}},
+	}}
+
+	assert.Assert(t, pendingMaintenance(cluster, nil) == nil)
+	assert.Assert(t, pendingMaintenance(cluster, instances) == nil)
+
+	cluster.Status.InstanceSets[0].Rollout.Waiting = "MaintenanceWindow"
+	instances.forCluster[0].Pods[0].Annotations = map[string]string{
+		"status": `{"role":"replica","pending_restart":true}`,
+	}
+	assert.DeepEqual(t, pendingMaintenance(cluster, instances),
+		[]string{"replace Pods", "restart PostgreSQL"})
+}
+
+func TestSetMaintenanceCondition(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 10, 20, 0, 0, time.UTC)
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Spec.MaintenanceWindows = []v1beta1.MaintenanceWindow{{
+		Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: time.Hour},
+	}}
+
+	t.Run("NoWork", func(t *testing.T) {
+		setMaintenanceCondition(cluster, now, nil)
+		assert.Assert(t, meta.FindStatusCondition(
+			cluster.Status.Conditions, v1beta1.MaintenancePending) == nil)
+		assert.Equal(t, maintenanceRequeue(cluster, now), time.Duration(0))
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		setMaintenanceCondition(cluster, now, []string{"replace Pods", "restart PostgreSQL"})
+
+		condition := meta.FindStatusCondition(
+			cluster.Status.Conditions, v1beta1.MaintenancePending)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionTrue)
+		assert.Equal(t, condition.Reason, "MaintenanceWindowClosed")
+		assert.Equal(t, condition.Message,
+			"Waiting until 2024-03-15T22:00:00Z to replace Pods, restart PostgreSQL")
+
+		assert.Equal(t, maintenanceRequeue(cluster, now), 11*time.Hour+40*time.Minute+time.Second)
+	})
+
+	t.Run("Open", func(t *testing.T) {
+		later := now.Add(12 * time.Hour)
+		setMaintenanceCondition(cluster, later, []string{"replace Pods"})
+		assert.Assert(t, meta.FindStatusCondition(
+			cluster.Status.Conditions, v1beta1.MaintenancePending) == nil)
+	})
+}
@@ -0,0 +1,259 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// alternatePostgresUser returns the name of the role that takes turns with
+// user to log in with the current password when passwords rotate.
+func alternatePostgresUser(user string) string { return user + "-alt" }
+
+// passwordRotation returns the password rotation of spec, if any. Passwords of
+// the "postgres" user and passwords from other Secrets do not rotate.
+func passwordRotation(spec *v1beta1.PostgresUserSpec) *v1beta1.PostgresPasswordRotationSpec {
+	if spec.Name == "postgres" || spec.PasswordSecretRef != nil ||
+		len(alternatePostgresUser(string(spec.Name))) > 63 ||
+		spec.PasswordRotation == nil || spec.PasswordRotation.Period.Duration <= 0 {
+		return nil
+	}
+	return spec.PasswordRotation
+}
+
+// passwordGracePeriod returns how long a previous password remains valid.
+func passwordGracePeriod(rotation *v1beta1.PostgresPasswordRotationSpec) time.Duration {
+	if rotation.GracePeriod != nil && rotation.GracePeriod.Duration >= 0 {
+		return rotation.GracePeriod.Duration
+	}
+	return 24 * time.Hour
+}
+
+// passwordRotated returns when the password in secret was last rotated. A
+// password that never rotated is as old as its Secret.
+func passwordRotated(secret *corev1.Secret) time.Time {
+	if t, err := time.Parse(time.RFC3339, secret.Annotations[naming.PasswordRotated]); err == nil {
+		return t
+	}
+	return secret.CreationTimestamp.Time
+}
+
+// rotatePostgresUserPassword returns a copy of existing without a password
+// when the password of spec is due to rotate at now. The copy hands the login
+// to the other role of spec and remembers the previous verifier. Otherwise,
+// it returns existing.
+func rotatePostgresUserPassword(
+	spec *v1beta1.PostgresUserSpec, existing *corev1.Secret, now time.Time,
+) (*corev1.Secret, bool) {
+	rotation := passwordRotation(spec)
+	if existing == nil || rotation == nil ||
+		now.Before(passwordRotated(existing).Add(rotation.Period.Duration)) {
+		return existing, false
+	}
+
+	login := alternatePostgresUser(string(spec.Name))
+	if string(existing.Data["user"]) == login {
+		login = string(spec.Name)
+	}
+
+	rotated := existing.DeepCopy()
+	rotated.Annotations = naming.Merge(rotated.Annotations, map[string]string{
+		naming.PasswordRotated: now.UTC().Format(time.RFC3339),
+	})
+	rotated.Data["user"] = []byte(login)
+	rotated.Data["previous-verifier"] = existing.Data["verifier"]
+	delete(rotated.Data, "password")
+	delete(rotated.Data, "verifier")
+
+	return rotated, true
+}
+
+// +kubebuilder:rbac:groups="",resources="secrets",verbs={get}
+
+// usePostgresUserPassword returns a copy of existing that holds the password
+// from the Secret referenced by spec. The verifier is removed when the password
+// changes so that a new one is built. When that Secret or its key is missing,
+// it emits a warning event and returns existing, which is nil for a user that
+// has no Secret yet.
+func (r *Reconciler) usePostgresUserPassword(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	spec *v1beta1.PostgresUserSpec, existing *corev1.Secret,
+) (*corev1.Secret, error) {
+	ref := spec.PasswordSecretRef
+	source := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      ref.Name,
+	}}
+
+	err := errors.WithStack(r.Client.Get(ctx, client.ObjectKeyFromObject(source), source))
+	if apierrors.IsNotFound(err) || (err == nil && len(source.Data[ref.Key]) == 0) {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidPasswordSecret",
+			"Unable to find the password of user %q in key %q of Secret %q",
+			spec.Name, ref.Key, ref.Name)
+		return existing, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	password := source.Data[ref.Key]
+	if existing != nil && bytes.Equal(existing.Data["password"], password) {
+		return existing, nil
+	}
+
+	intent := &corev1.Secret{}
+	if existing != nil {
+		intent = existing.DeepCopy()
+	}
+	initialize.Map(&intent.Data)
+	intent.Data["password"] = password
+	delete(intent.Data, "verifier")
+
+	return intent, nil
+}
+
+// withValidUntil returns options that expire the password of a role at until.
+// - https://www.postgresql.org/docs/current/sql-alterrole.html
+func withValidUntil(options, until string) string {
+	return strings.TrimSpace(options + " VALID UNTIL '" + until + "'")
+}
+
+// postgresUserRoles returns the roles of users to write in PostgreSQL, the
+// verifier of each role, and the user of each alternate role. Users that
+// rotate passwords have an alternate role after their first rotation. The
+// role with the current password never expires while the other expires after
+// the grace period.
+func postgresUserRoles(
+	users []v1beta1.PostgresUserSpec, secrets map[string]*corev1.Secret,
+) (
+	roles []v1beta1.PostgresUserSpec, verifiers, alternates map[string]string,
+) {
+	verifiers = make(map[string]string, len(secrets))
+	alternates = make(map[string]string)
+
+	for i := range users {
+		name := string(users[i].Name)
+		secret := secrets[name]
+		rotation := passwordRotation(&users[i])
+		alternate := alternatePostgresUser(name)
+
+		if secret == nil {
+			roles = append(roles, users[i])
+			continue
+		}
+
+		// Users that do not rotate passwords log in with their own role.
+		login := string(secret.Data["user"])
+		if rotation == nil && login != alternate {
+			roles = append(roles, users[i])
+			verifiers[name] = string(secret.Data["verifier"])
+			continue
+		}
+
+		primary := users[i]
+		other := v1beta1.PostgresUserSpec{
+			Name:    v1beta1.PostgresIdentifier(alternate),
+			Options: users[i].Options,
+		}
+
+		current, previous := &primary, &other
+		if login == alternate {
+			current, previous = previous, current
+		}
+
+		current.Options = withValidUntil(users[i].Options, "infinity")
+		verifiers[string(current.Name)] = string(secret.Data["verifier"])
+
+		rotated := secret.Annotations[naming.PasswordRotated] != ""
+		if rotation != nil && rotated {
+			expires := passwordRotated(secret).Add(passwordGracePeriod(rotation))
+			previous.Options = withValidUntil(users[i].Options, expires.UTC().Format(time.RFC3339))
+			verifiers[string(previous.Name)] = string(secret.Data["previous-verifier"])
+		}
+
+		roles = append(roles, primary)
+		if login == alternate || (rotation != nil && rotated) {
+			roles = append(roles, other)
+			alternates[alternate] = name
+		}
+	}
+
+	return roles, verifiers, alternates
+}
+
+// setPasswordRotationStatus records in cluster when the passwords of users
+// rotated and which role logs in with each current password.
+func setPasswordRotationStatus(
+	cluster *v1beta1.PostgresCluster,
+	users []v1beta1.PostgresUserSpec, secrets map[string]*corev1.Secret,
+) {
+	for i := range users {
+		rotation := passwordRotation(&users[i])
+		secret := secrets[string(users[i].Name)]
+		if rotation == nil || secret == nil {
+			continue
+		}
+
+		for j := range cluster.Status.Users {
+			status := &cluster.Status.Users[j]
+			if status.Name != string(users[i].Name) {
+				continue
+			}
+
+			rotated := passwordRotated(secret)
+			status.LoginRole = string(secret.Data["user"])
+			status.PasswordRotated = &metav1.Time{Time: rotated}
+			if secret.Annotations[naming.PasswordRotated] != "" {
+				status.PreviousPasswordExpires = &metav1.Time{
+					Time: rotated.Add(passwordGracePeriod(rotation)),
+				}
+			}
+		}
+	}
+}
+
+// passwordRotationRequeue returns how long to wait before the next password
+// in cluster is due to rotate. Time passing does not cause an event that
+// triggers another reconcile.
+func passwordRotationRequeue(cluster *v1beta1.PostgresCluster, now time.Time) time.Duration {
+	var requeue time.Duration
+
+	for i := range cluster.Spec.Users {
+		rotation := passwordRotation(&cluster.Spec.Users[i])
+		if rotation == nil {
+			continue
+		}
+
+		for _, status := range cluster.Status.Users {
+			if status.Name != string(cluster.Spec.Users[i].Name) || status.PasswordRotated == nil {
+				continue
+			}
+
+			// Arrive a moment after the password is due.
+			next := status.PasswordRotated.Add(rotation.Period.Duration).Sub(now) + time.Second
+			if next < time.Second {
+				next = time.Second
+			}
+			if requeue == 0 || next < requeue {
+				requeue = next
+			}
+		}
+	}
+
+	return requeue
+}
@@ -0,0 +1,298 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/internal/testing/events"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestRotatePostgresUserPassword(t *testing.T) {
+	created := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Time{Time: created}},
+		Data: map[string][]byte{
+			"user": []byte("alice"), "password": []byte("one"), "verifier": []byte("v1"),
+		},
+	}
+
+	spec := &v1beta1.PostgresUserSpec{Name: "alice"}
+	now := created.Add(100 * 24 * time.Hour)
+
+	t.Run("Disabled", func(t *testing.T) {
+		secret, rotated := rotatePostgresUserPassword(spec, existing, now)
+		assert.Assert(t, !rotated)
+		assert.Equal(t, secret, existing)
+	})
+
+	spec.PasswordRotation = &v1beta1.PostgresPasswordRotationSpec{
+		Period: metav1.Duration{Duration: 90 * 24 * time.Hour},
+	}
+
+	t.Run("NotDue", func(t *testing.T) {
+		secret, rotated := rotatePostgresUserPassword(spec, existing, created.Add(time.Hour))
+		assert.Assert(t, !rotated)
+		assert.Equal(t, secret, existing)
+
+		secret, rotated = rotatePostgresUserPassword(spec, nil, now)
+		assert.Assert(t, !rotated)
+		assert.Assert(t, secret == nil)
+	})
+
+	t.Run("Postgres", func(t *testing.T) {
+		_, rotated := rotatePostgresUserPassword(&v1beta1.PostgresUserSpec{
+			Name: "postgres", PasswordRotation: spec.PasswordRotation,
+		}, existing, now)
+		assert.Assert(t, !rotated)
+	})
+
+	t.Run("Due", func(t *testing.T) {
+		first, rotated := rotatePostgresUserPassword(spec, existing, now)
+		assert.Assert(t, rotated)
+		assert.Equal(t, string(existing.Data["password"]), "one", "expected a copy")
+
+		assert.Equal(t, first.Annotations[naming.PasswordRotated], "2024-04-10T00:00:00Z")
+		assert.Equal(t, string(first.Data["user"]), "alice-alt")
+		assert.Equal(t, string(first.Data["previous-verifier"]), "v1")
+		assert.Assert(t, first.Data["password"] == nil)
+		assert.Assert(t, first.Data["verifier"] == nil)
+
+		// The next rotation is one period after the last one.
+		first.Data["verifier"] = []byte("v2")
+		_, rotated = rotatePostgresUserPassword(spec, first, now.Add(89*24*time.Hour))
+		assert.Assert(t, !rotated)
+
+		second, rotated := rotatePostgresUserPassword(spec, first, now.Add(90*24*time.Hour))
+		assert.Assert(t, rotated)
+		assert.Equal(t, string(second.Data["user"]), "alice")
+		assert.Equal(t, string(second.Data["previous-verifier"]), "v2")
+	})
+}
+
+func TestGeneratePostgresUserSecretRotation(t *testing.T) {
+	reconciler := &Reconciler{
+		Client: fake.NewClientBuilder().WithScheme(runtime.Scheme).Build(),
+	}
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Namespace = "ns1"
+	cluster.Name = "hippo"
+	cluster.Spec.Port = initialize.Int32(5432)
+	spec := &v1beta1.PostgresUserSpec{
+		Name:      "alice",
+		Databases: []v1beta1.PostgresIdentifier{"db1"},
+		PasswordRotation: &v1beta1.PostgresPasswordRotationSpec{
+			Period: metav1.Duration{Duration: time.Hour},
+		},
+	}
+
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			naming.PasswordRotated: "2024-04-10T00:00:00Z",
+		}},
+		Data: map[string][]byte{
+			"user": []byte("alice-alt"), "previous-verifier": []byte("v1"),
+		},
+	}
+
+	secret, err := reconciler.generatePostgresUserSecret(cluster, spec, existing)
+	assert.NilError(t, err)
+
+	assert.Equal(t, secret.Name, "hippo-pguser-alice")
+	assert.Equal(t, secret.Labels[naming.LabelPostgresUser], "alice")
+	assert.Equal(t, secret.Annotations[naming.PasswordRotated], "2024-04-10T00:00:00Z")
+
+	assert.Equal(t, string(secret.Data["user"]), "alice-alt")
+	assert.Equal(t, string(secret.Data["previous-verifier"]), "v1")
+	assert.Assert(t, len(secret.Data["password"]) > 0)
+	assert.Assert(t, len(secret.Data["verifier"]) > 0)
+	assert.Assert(t, cmp.Contains(string(secret.Data["uri"]), "postgresql://alice-alt:"))
+}
+
+func TestUsePostgresUserPassword(t *testing.T) {
+	ctx := context.Background()
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "vault"},
+		Data:       map[string][]byte{"password": []byte("from-vault")},
+	}
+
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &Reconciler{
+		Client:   fake.NewClientBuilder().WithScheme(runtime.Scheme).WithObjects(source).Build(),
+		Recorder: recorder,
+	}
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+
+	spec := &v1beta1.PostgresUserSpec{
+		Name: "alice",
+		PasswordSecretRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "vault"},
+			Key:                  "password",
+		},
+	}
+
+	t.Run("New", func(t *testing.T) {
+		secret, err := reconciler.usePostgresUserPassword(ctx, cluster, spec, nil)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, secret.Data, map[string][]byte{"password": []byte("from-vault")})
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		existing := &corev1.Secret{Data: map[string][]byte{
+			"password": []byte("from-vault"), "verifier": []byte("v"),
+		}}
+		secret, err := reconciler.usePostgresUserPassword(ctx, cluster, spec, existing)
+		assert.NilError(t, err)
+		assert.Equal(t, secret, existing)
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		existing := &corev1.Secret{Data: map[string][]byte{
+			"password": []byte("before"), "verifier": []byte("v"), "user": []byte("alice"),
+		}}
+		secret, err := reconciler.usePostgresUserPassword(ctx, cluster, spec, existing)
+		assert.NilError(t, err)
+		assert.Equal(t, string(existing.Data["password"]), "before", "expected a copy")
+		assert.DeepEqual(t, secret.Data, map[string][]byte{
+			"password": []byte("from-vault"), "user": []byte("alice"),
+		})
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		existing := &corev1.Secret{Data: map[string][]byte{"password": []byte("before")}}
+
+		for _, ref := range []corev1.SecretKeySelector{
+			{LocalObjectReference: corev1.LocalObjectReference{Name: "nope"}, Key: "password"},
+			{LocalObjectReference: corev1.LocalObjectReference{Name: "vault"}, Key: "nope"},
+		} {
+			spec := &v1beta1.PostgresUserSpec{Name: "alice", PasswordSecretRef: &ref}
+			secret, err := reconciler.usePostgresUserPassword(ctx, cluster, spec, existing)
+			assert.NilError(t, err)
+			assert.Equal(t, secret, existing)
+
+			// No password is generated for a new user.
+			secret, err = reconciler.usePostgresUserPassword(ctx, cluster, spec, nil)
+			assert.NilError(t, err)
+			assert.Assert(t, secret == nil)
+		}
+
+		assert.Equal(t, len(recorder.Events), 4)
+		for _, event := range recorder.Events {
+			assert.Equal(t, event.Reason, "InvalidPasswordSecret")
+			assert.Equal(t, event.Regarding.Name, cluster.Name)
+		}
+	})
+
+	t.Run("NoRotation", func(t *testing.T) {
+		rotating := spec.DeepCopy()
+		rotating.PasswordRotation = &v1beta1.PostgresPasswordRotationSpec{
+			Period: metav1.Duration{Duration: time.Hour},
+		}
+		assert.Assert(t, passwordRotation(rotating) == nil)
+	})
+}
+
+func TestPostgresUserRoles(t *testing.T) {
+	rotation := &v1beta1.PostgresPasswordRotationSpec{
+		Period:      metav1.Duration{Duration: time.Hour},
+		GracePeriod: &metav1.Duration{Duration: 30 * time.Minute},
+	}
+	users := []v1beta1.PostgresUserSpec{
+		{Name: "plain", Options: "CREATEDB"},
+		{Name: "fresh", PasswordRotation: rotation},
+		{Name: "alice", Databases: []v1beta1.PostgresIdentifier{"db1"}, Options: "LOGIN", PasswordRotation: rotation},
+		{Name: "stopped"},
+	}
+	secrets := map[string]*corev1.Secret{
+		"plain": {Data: map[string][]byte{"user": []byte("plain"), "verifier": []byte("p")}},
+		"fresh": {Data: map[string][]byte{"user": []byte("fresh"), "verifier": []byte("f")}},
+		"alice": {
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				naming.PasswordRotated: "2024-04-10T00:00:00Z",
+			}},
+			Data: map[string][]byte{
+				"user": []byte("alice-alt"), "verifier": []byte("a2"), "previous-verifier": []byte("a1"),
+			},
+		},
+		"stopped": {Data: map[string][]byte{"user": []byte("stopped-alt"), "verifier": []byte("s")}},
+	}
+
+	roles, verifiers, alternates := postgresUserRoles(users, secrets)
+
+	assert.DeepEqual(t, roles, []v1beta1.PostgresUserSpec{
+		{Name: "plain", Options: "CREATEDB"},
+		{Name: "fresh", Options: "VALID UNTIL 'infinity'", PasswordRotation: rotation},
+		{
+			Name: "alice", Databases: []v1beta1.PostgresIdentifier{"db1"},
+			Options: "LOGIN VALID UNTIL '2024-04-10T00:30:00Z'", PasswordRotation: rotation,
+		},
+		{Name: "alice-alt", Options: "LOGIN VALID UNTIL 'infinity'"},
+		{Name: "stopped"},
+		{Name: "stopped-alt", Options: "VALID UNTIL 'infinity'"},
+	})
+	assert.DeepEqual(t, verifiers, map[string]string{
+		"plain": "p", "fresh": "f", "alice": "a1", "alice-alt": "a2", "stopped-alt": "s",
+	})
+	assert.DeepEqual(t, alternates, map[string]string{
+		"alice-alt": "alice", "stopped-alt": "stopped",
+	})
+}
+
+func TestPasswordRotationStatus(t *testing.T) {
+	now := time.Date(2024, time.April, 10, 0, 0, 0, 0, time.UTC)
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Spec.Users = []v1beta1.PostgresUserSpec{
+		{Name: "plain"},
+		{Name: "alice", PasswordRotation: &v1beta1.PostgresPasswordRotationSpec{
+			Period: metav1.Duration{Duration: 2 * time.Hour},
+		}},
+	}
+	cluster.Status.Users = []v1beta1.PostgresUserStatus{
+		{Name: "alice"}, {Name: "alice-alt"}, {Name: "plain"},
+	}
+	assert.Equal(t, passwordRotationRequeue(cluster, now), time.Duration(0))
+
+	setPasswordRotationStatus(cluster, cluster.Spec.Users, map[string]*corev1.Secret{
+		"plain": {Data: map[string][]byte{"user": []byte("plain")}},
+		"alice": {
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				naming.PasswordRotated: "2024-04-10T00:00:00Z",
+			}},
+			Data: map[string][]byte{"user": []byte("alice-alt")},
+		},
+	})
+
+	assert.DeepEqual(t, cluster.Status.Users, []v1beta1.PostgresUserStatus{
+		{
+			Name: "alice", LoginRole: "alice-alt",
+			PasswordRotated:         &metav1.Time{Time: now},
+			PreviousPasswordExpires: &metav1.Time{Time: now.Add(24 * time.Hour)},
+		},
+		{Name: "alice-alt"}, {Name: "plain"},
+	})
+
+	assert.Equal(t, passwordRotationRequeue(cluster, now.Add(time.Hour)), time.Hour+time.Second)
+	assert.Equal(t, passwordRotationRequeue(cluster, now.Add(3*time.Hour)), time.Second)
+
+	cluster.Spec.Users[1].PasswordRotation.GracePeriod = initialize.Pointer(metav1.Duration{})
+	assert.Equal(t, passwordGracePeriod(cluster.Spec.Users[1].PasswordRotation), time.Duration(0))
+}
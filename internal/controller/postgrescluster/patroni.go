@@ -6,14 +6,20 @@ package postgrescluster
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crunchydata/postgres-operator/internal/initialize"
@@ -52,6 +58,11 @@ func (r *Reconciler) handlePatroniRestarts(
 	const container = naming.ContainerDatabase
 	var primaryNeedsRestart, replicaNeedsRestart *Instance
 
+	// Restarts interrupt connections; wait for a maintenance window.
+	if !maintenanceAllowed(cluster, time.Now()) {
+		return nil
+	}
+
 	// Look for one primary and one replica that need to restart. Ignore
 	// containers that are terminating or not running; Kubernetes will start
 	// them again, and calls to their Patroni API will likely be interrupted anyway.
@@ -210,8 +221,109 @@ func (r *Reconciler) reconcilePatroniDynamicConfiguration(
 	}
 	configuration = patroni.DynamicConfiguration(cluster, configuration, pgHBAs, pgParameters)
 
-	return errors.WithStack(
-		patroni.Executor(exec).ReplaceConfiguration(ctx, configuration))
+	// Patroni stores its current dynamic configuration in DCS. Compare it to
+	// the new configuration to find parameters that need PostgreSQL to restart.
+	dcs := &corev1.Endpoints{ObjectMeta: naming.PatroniDistributedConfiguration(cluster)}
+	err := errors.WithStack(client.IgnoreNotFound(
+		r.Client.Get(ctx, client.ObjectKeyFromObject(dcs), dcs)))
+
+	if err == nil && dcs.Annotations["config"] != "" {
+		var current map[string]any
+		if json.Unmarshal([]byte(dcs.Annotations["config"]), &current) == nil {
+			addPendingRestart(cluster, patroni.PendingRestartParameters(
+				cluster.Spec.PostgresVersion, current, configuration))
+		}
+	}
+	if err == nil {
+		err = errors.WithStack(
+			patroni.Executor(exec).ReplaceConfiguration(ctx, configuration))
+	}
+	return err
+}
+
+// addPendingRestart records in cluster that parameters changed and take
+// effect only after PostgreSQL restarts.
+func addPendingRestart(cluster *v1beta1.PostgresCluster, parameters []string) {
+	if len(parameters) > 0 {
+		cluster.Status.Patroni.PendingRestart = sets.List(sets.New(
+			cluster.Status.Patroni.PendingRestart...).Insert(parameters...))
+		cluster.Status.Patroni.PendingRestartSince = initialize.Pointer(metav1.Now())
+	}
+}
+
+// setPendingRestartCondition reports PostgreSQL parameters and instances that
+// are waiting for PostgreSQL to restart. Patroni notices changed parameters on
+// its next loop, so parameters are forgotten only after that has had time to
+// happen and no instance requires a restart. It returns true when the condition
+// reports something that was not reported before.
+func setPendingRestartCondition(
+	cluster *v1beta1.PostgresCluster, instances *observedInstances, now time.Time,
+) bool {
+	var waiting []string
+	for _, instance := range instances.forCluster {
+		if len(instance.Pods) > 0 && patroni.PodRequiresRestart(instance.Pods[0]) {
+			waiting = append(waiting, instance.Name)
+		}
+	}
+	sort.Strings(waiting)
+
+	parameters := func() string {
+		return "PostgreSQL must restart to apply parameters: " +
+			strings.Join(cluster.Status.Patroni.PendingRestart, ", ")
+	}
+
+	// Parameters expire together once the last of them has been pending for
+	// long enough. One that was added recently keeps them all.
+	existing := meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.PendingRestart)
+	if len(cluster.Status.Patroni.PendingRestart) > 0 &&
+		cluster.Status.Patroni.PendingRestartSince == nil {
+		cluster.Status.Patroni.PendingRestartSince = initialize.Pointer(metav1.NewTime(now))
+	}
+	if len(waiting) == 0 && len(cluster.Status.Patroni.PendingRestart) > 0 {
+		loop := 10 * time.Second
+		if cluster.Spec.Patroni != nil && cluster.Spec.Patroni.SyncPeriodSeconds != nil {
+			loop = time.Duration(*cluster.Spec.Patroni.SyncPeriodSeconds) * time.Second
+		}
+		if now.Sub(cluster.Status.Patroni.PendingRestartSince.Time) > 2*loop {
+			cluster.Status.Patroni.PendingRestart = nil
+		}
+	}
+	if len(cluster.Status.Patroni.PendingRestart) == 0 {
+		cluster.Status.Patroni.PendingRestartSince = nil
+	}
+
+	if len(waiting) == 0 && len(cluster.Status.Patroni.PendingRestart) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, v1beta1.PendingRestart)
+		return false
+	}
+
+	condition := metav1.Condition{
+		Type:               v1beta1.PendingRestart,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ParametersChanged",
+		ObservedGeneration: cluster.GetGeneration(),
+	}
+	var message []string
+	if len(cluster.Status.Patroni.PendingRestart) > 0 {
+		message = append(message, parameters())
+	}
+	if len(waiting) > 0 {
+		message = append(message, "Instances pending restart: "+strings.Join(waiting, ", "))
+	}
+	condition.Message = strings.Join(message, ". ")
+
+	// Keep the transition time while nothing changes so parameters can expire.
+	// A parameter or instance that is new starts the clock again.
+	// NOTE: [meta.SetStatusCondition] keeps the transition time of a condition
+	// when its status does not change, so remove it first.
+	changed := existing == nil || existing.Status != metav1.ConditionTrue ||
+		existing.Message != condition.Message
+	if changed {
+		condition.LastTransitionTime = metav1.NewTime(now)
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, v1beta1.PendingRestart)
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	return changed
 }
 
 // generatePatroniLeaderLeaseService returns a v1.Service that exposes the
@@ -340,9 +452,99 @@ func (r *Reconciler) reconcilePatroniStatus(
 		}
 	}
 
+	// Patroni writes the names of synchronous standbys to DCS.
+	// - https://github.com/zalando/patroni/blob/v3.3.0/patroni/dcs/kubernetes.py
+	sync := &corev1.Endpoints{ObjectMeta: naming.PatroniSync(cluster)}
+	if err == nil {
+		err = errors.WithStack(client.IgnoreNotFound(
+			r.Client.Get(ctx, client.ObjectKeyFromObject(sync), sync)))
+	}
+	if err == nil {
+		cluster.Status.Patroni.SynchronousStandbys = nil
+		for _, name := range strings.Split(sync.Annotations["sync_standby"], ",") {
+			if name = strings.TrimSpace(name); name != "" && name != "*" {
+				cluster.Status.Patroni.SynchronousStandbys = append(
+					cluster.Status.Patroni.SynchronousStandbys, name)
+			}
+		}
+		sort.Strings(cluster.Status.Patroni.SynchronousStandbys)
+
+		setSynchronousReplicationCondition(cluster)
+	}
+
 	return requeue, err
 }
 
+// setSynchronousReplicationCondition reports whether or not cluster has the
+// synchronous standbys it asks for. The condition is removed when synchronous
+// replication is not enabled in the spec.
+func setSynchronousReplicationCondition(cluster *v1beta1.PostgresCluster) {
+	var sync *v1beta1.PatroniSynchronous
+	if cluster.Spec.Patroni != nil {
+		sync = cluster.Spec.Patroni.Synchronous
+	}
+	if sync == nil || !sync.Enabled || !patroni.ClusterBootstrapped(cluster) {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, v1beta1.SynchronousReplication)
+		return
+	}
+
+	want := max(1, int(initialize.FromPointer(sync.NodeCount)))
+	have := len(cluster.Status.Patroni.SynchronousStandbys)
+
+	condition := metav1.Condition{
+		Type:               v1beta1.SynchronousReplication,
+		ObservedGeneration: cluster.GetGeneration(),
+	}
+	switch {
+	case have >= want:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Synchronous"
+		condition.Message = fmt.Sprintf("Replicating synchronously to %d standby(s).", have)
+	case have > 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Degraded"
+		condition.Message = fmt.Sprintf(
+			"Replicating synchronously to %d of %d standby(s).", have, want)
+	case sync.Strict:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NoStandbys"
+		condition.Message = "No synchronous standbys are available; writes are blocked."
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "AsynchronousFallback"
+		condition.Message = "No synchronous standbys are available; replicating asynchronously."
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// validateSynchronousReplication emits warnings when the synchronous
+// replication settings of cluster cannot be satisfied by its instance sets.
+func (r *Reconciler) validateSynchronousReplication(cluster *v1beta1.PostgresCluster) {
+	if cluster.Spec.Patroni == nil || cluster.Spec.Patroni.Synchronous == nil ||
+		!cluster.Spec.Patroni.Synchronous.Enabled {
+		return
+	}
+
+	// One instance is the primary. Delayed instances are never synchronous.
+	var candidates int32 = -1
+	for i := range cluster.Spec.InstanceSets {
+		if cluster.Spec.InstanceSets[i].RecoveryMinApplyDelay == nil {
+			candidates += initialize.FromPointer(cluster.Spec.InstanceSets[i].Replicas)
+		}
+	}
+
+	sync := cluster.Spec.Patroni.Synchronous
+	if count := max(1, initialize.FromPointer(sync.NodeCount)); count > candidates {
+		message := "must be less than the number of instances that are not delayed"
+		if sync.Strict {
+			message += "; writes are blocked until there are enough replicas"
+		}
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidSynchronousReplication",
+			field.Invalid(field.NewPath("spec", "patroni", "synchronous", "nodeCount"),
+				count, message).Error())
+	}
+}
+
 // reconcileReplicationSecret creates a secret containing the TLS
 // certificate, key and CA certificate for use with the replication and
 // pg_rewind accounts in Postgres.
@@ -350,7 +552,7 @@ func (r *Reconciler) reconcilePatroniStatus(
 // account and enable cert authentication for that user
 func (r *Reconciler) reconcileReplicationSecret(
 	ctx context.Context, cluster *v1beta1.PostgresCluster,
-	root *pki.RootCertificateAuthority,
+	root pki.Issuer,
 ) (*corev1.Secret, error) {
 
 	// if a custom postgrescluster secret is provided, just return it
@@ -409,7 +611,7 @@ func (r *Reconciler) reconcileReplicationSecret(
 		err = errors.WithStack(err)
 	}
 	if err == nil {
-		intent.Data[naming.ReplicationCACert], err = root.Certificate.MarshalText()
+		intent.Data[naming.ReplicationCACert], err = root.Bundle().MarshalText()
 		err = errors.WithStack(err)
 	}
 	if err == nil {
@@ -442,6 +644,9 @@ func replicationCertSecretProjection(certificate *corev1.Secret) *corev1.SecretP
 	}
 }
 
+// reconcilePatroniSwitchover performs the switchover or failover requested
+// by the trigger annotation of cluster. When no such request is in progress,
+// it moves the primary to a preferred instance set.
 func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 	cluster *v1beta1.PostgresCluster, instances *observedInstances) error {
 	log := logging.FromContext(ctx)
@@ -456,7 +661,7 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 		!cluster.Spec.Patroni.Switchover.Enabled {
 		cluster.Status.Patroni.Switchover = nil
 		cluster.Status.Patroni.SwitchoverTimeline = nil
-		return nil
+		return r.reconcilePreferredPrimary(ctx, cluster, instances)
 	}
 
 	annotation := cluster.GetAnnotations()[naming.PatroniSwitchover]
@@ -467,7 +672,7 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 	// switchover has been successful, and the `SwitchoverTimeline` field can be cleared
 	if annotation == "" || (status != nil && *status == annotation) {
 		cluster.Status.Patroni.SwitchoverTimeline = nil
-		return nil
+		return r.reconcilePreferredPrimary(ctx, cluster, instances)
 	}
 
 	// If we've reached this point, we assume a switchover request or in progress
@@ -602,3 +807,93 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 
 	return err
 }
+
+// preferredPrimarySwitchover returns the primary instance of cluster when it
+// is outside the instance sets that prefer the primary, along with the replica
+// in those sets to switch over to. The candidate is nil when no replica in a
+// preferred set is ready and able to be promoted. Both are nil when the
+// primary is already preferred or no instance set has a preference.
+func preferredPrimarySwitchover(
+	cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) (primary, candidate *Instance) {
+	preferred := sets.New[string]()
+	for _, set := range cluster.Spec.InstanceSets {
+		if set.PreferredPrimary {
+			preferred.Insert(set.Name)
+		}
+	}
+	if preferred.Len() == 0 || instances == nil {
+		return nil, nil
+	}
+
+	for _, instance := range instances.forCluster {
+		if is, known := instance.IsPrimary(); is && known {
+			primary = instance
+		}
+	}
+	if primary == nil || primary.Spec == nil || preferred.Has(primary.Spec.Name) {
+		return nil, nil
+	}
+
+	// Patroni does not promote instances that are tagged "nofailover". Among
+	// the others, prefer the highest "failover_priority" then the lowest name.
+	for _, instance := range instances.forCluster {
+		if instance.Spec == nil || !preferred.Has(instance.Spec.Name) ||
+			instance.Spec.RecoveryMinApplyDelay != nil || failoverPriority(instance) == 0 {
+			continue
+		}
+		if terminating, known := instance.IsTerminating(); terminating || !known {
+			continue
+		}
+		if ready, known := instance.IsReady(); !ready || !known {
+			continue
+		}
+		if running, known := instance.IsRunning(naming.ContainerDatabase); !running || !known {
+			continue
+		}
+		if candidate == nil {
+			candidate = instance
+		} else if a, b := failoverPriority(instance), failoverPriority(candidate); a > b ||
+			(a == b && instance.Name < candidate.Name) {
+			candidate = instance
+		}
+	}
+
+	return primary, candidate
+}
+
+// failoverPriority returns the Patroni "failover_priority" of instance.
+func failoverPriority(instance *Instance) int32 {
+	if instance.Spec != nil && instance.Spec.FailoverPriority != nil {
+		return *instance.Spec.FailoverPriority
+	}
+	return 1 // The Patroni default
+}
+
+// reconcilePreferredPrimary switches over to a replica in a preferred instance
+// set when the primary is elsewhere, such as after a failover. Switchovers
+// interrupt connections, so this waits for a maintenance window. Clusters
+// without maintenance windows are always open, so their primary is not moved
+// back at all; that would be a second outage right after the first.
+func (r *Reconciler) reconcilePreferredPrimary(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) error {
+	if len(cluster.Spec.MaintenanceWindows) == 0 {
+		return nil
+	}
+
+	primary, candidate := preferredPrimarySwitchover(cluster, instances)
+	if candidate == nil || len(primary.Pods) != 1 ||
+		!maintenanceAllowed(cluster, time.Now()) {
+		return nil
+	}
+
+	err := r.switchoverInstance(ctx, primary, candidate)
+	if err == nil {
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "PreferredPrimarySwitchover",
+			"Switched over from %v to %v in preferred instance set %v",
+			primary.Name, candidate.Name, candidate.Spec.Name)
+	}
+
+	return err
+}
@@ -15,18 +15,22 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
 	"gotest.tools/v3/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/internal/testing/events"
 	"github.com/crunchydata/postgres-operator/internal/testing/require"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
@@ -1003,3 +1007,352 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 		assert.Assert(t, cluster.Status.Patroni.SwitchoverTimeline == nil)
 	})
 }
+
+func TestReconcilePreferredPrimary(t *testing.T) {
+	ctx := context.Background()
+
+	pod := func(name string, primary, ready bool) *corev1.Pod {
+		pod := &corev1.Pod{}
+		pod.Namespace, pod.Name = "ns1", name
+		pod.Status.Conditions = []corev1.PodCondition{{
+			Type: corev1.PodReady, Status: corev1.ConditionFalse,
+		}}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  naming.ContainerDatabase,
+			State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+		}}
+		if ready {
+			pod.Status.Conditions[0].Status = corev1.ConditionTrue
+		}
+		if primary {
+			pod.Labels = map[string]string{naming.LabelRole: naming.RolePatroniLeader}
+			pod.Annotations = map[string]string{"status": `{"role":"master"}`}
+		}
+		return pod
+	}
+
+	setup := func() (*v1beta1.PostgresCluster, *observedInstances) {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Namespace, cluster.Name = "ns1", "hippo"
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+			{Name: "zone-a", PreferredPrimary: true},
+			{Name: "zone-b"},
+		}
+		cluster.Spec.MaintenanceWindows = []v1beta1.MaintenanceWindow{{
+			Schedule: "* * * * *", Duration: metav1.Duration{Duration: time.Hour},
+		}}
+
+		a, b := &cluster.Spec.InstanceSets[0], &cluster.Spec.InstanceSets[1]
+		return cluster, &observedInstances{forCluster: []*Instance{
+			{Name: "a1", Spec: a, Pods: []*corev1.Pod{pod("a1-0", false, true)}},
+			{Name: "a2", Spec: a, Pods: []*corev1.Pod{pod("a2-0", false, true)}},
+			{Name: "b1", Spec: b, Pods: []*corev1.Pod{pod("b1-0", true, true)}},
+		}}
+	}
+
+	reconcile := func(t *testing.T, cluster *v1beta1.PostgresCluster, observed *observedInstances) (
+		*events.Recorder, []string,
+	) {
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder, Tracer: otel.Tracer(t.Name())}
+
+		var commands []string
+		reconciler.PodExec = func(
+			ctx context.Context, namespace, pod, container string, _ io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			commands = append(commands, pod+": "+strings.Join(command, " "))
+			_, _ = stdout.Write([]byte("switched over"))
+			return nil
+		}
+
+		assert.NilError(t, reconciler.reconcilePatroniSwitchover(ctx, cluster, observed))
+		return recorder, commands
+	}
+
+	t.Run("NoPreference", func(t *testing.T) {
+		cluster, observed := setup()
+		cluster.Spec.InstanceSets[0].PreferredPrimary = false
+
+		primary, candidate := preferredPrimarySwitchover(cluster, observed)
+		assert.Assert(t, primary == nil && candidate == nil)
+
+		recorder, commands := reconcile(t, cluster, observed)
+		assert.Equal(t, len(commands), 0)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("AlreadyPreferred", func(t *testing.T) {
+		cluster, observed := setup()
+		cluster.Spec.InstanceSets[1].PreferredPrimary = true
+
+		primary, candidate := preferredPrimarySwitchover(cluster, observed)
+		assert.Assert(t, primary == nil && candidate == nil)
+	})
+
+	t.Run("SwitchBack", func(t *testing.T) {
+		cluster, observed := setup()
+
+		primary, candidate := preferredPrimarySwitchover(cluster, observed)
+		assert.Equal(t, primary.Name, "b1")
+		assert.Equal(t, candidate.Name, "a1")
+		assert.DeepEqual(t, pendingMaintenance(cluster, observed),
+			[]string{"switch over to a preferred primary"})
+
+		recorder, commands := reconcile(t, cluster, observed)
+		assert.Equal(t, len(commands), 1)
+		assert.Assert(t, cmp.Contains(commands[0], "b1-0: patronictl switchover"))
+		assert.Assert(t, cmp.Contains(commands[0], "--master=b1-0 --candidate=a1-0"))
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "PreferredPrimarySwitchover")
+		assert.Equal(t, recorder.Events[0].Note,
+			"Switched over from b1 to a1 in preferred instance set zone-a")
+	})
+
+	t.Run("FailoverPriority", func(t *testing.T) {
+		cluster, observed := setup()
+		cluster.Spec.InstanceSets[0].FailoverPriority = initialize.Int32(2)
+		cluster.Spec.InstanceSets = append(cluster.Spec.InstanceSets, v1beta1.PostgresInstanceSetSpec{
+			Name: "zone-c", PreferredPrimary: true, FailoverPriority: initialize.Int32(3),
+		})
+		observed.forCluster[0].Spec = &cluster.Spec.InstanceSets[0]
+		observed.forCluster[1].Spec = &cluster.Spec.InstanceSets[0]
+		observed.forCluster = append(observed.forCluster, &Instance{
+			Name: "c1", Spec: &cluster.Spec.InstanceSets[2],
+			Pods: []*corev1.Pod{pod("c1-0", false, true)},
+		})
+
+		_, candidate := preferredPrimarySwitchover(cluster, observed)
+		assert.Equal(t, candidate.Name, "c1")
+
+		// Instances that cannot be promoted are never candidates.
+		cluster.Spec.InstanceSets[2].FailoverPriority = initialize.Int32(0)
+
+		_, candidate = preferredPrimarySwitchover(cluster, observed)
+		assert.Equal(t, candidate.Name, "a1")
+	})
+
+	t.Run("NotReady", func(t *testing.T) {
+		cluster, observed := setup()
+		observed.forCluster[0].Pods[0] = pod("a1-0", false, false)
+		observed.forCluster[1].Spec = &v1beta1.PostgresInstanceSetSpec{
+			Name: "zone-a", RecoveryMinApplyDelay: initialize.String("1h"),
+		}
+
+		primary, candidate := preferredPrimarySwitchover(cluster, observed)
+		assert.Equal(t, primary.Name, "b1")
+		assert.Assert(t, candidate == nil)
+		assert.Assert(t, pendingMaintenance(cluster, observed) == nil)
+
+		recorder, commands := reconcile(t, cluster, observed)
+		assert.Equal(t, len(commands), 0)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("NoMaintenanceWindows", func(t *testing.T) {
+		cluster, observed := setup()
+		cluster.Spec.MaintenanceWindows = nil
+
+		// Without windows, the primary stays where a failover put it.
+		recorder, commands := reconcile(t, cluster, observed)
+		assert.Equal(t, len(commands), 0)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("MaintenanceWindowClosed", func(t *testing.T) {
+		cluster, observed := setup()
+		cluster.Spec.MaintenanceWindows = []v1beta1.MaintenanceWindow{{
+			Schedule: fmt.Sprintf("0 %d * * *", (time.Now().UTC().Hour()+12)%24),
+			Duration: metav1.Duration{Duration: time.Hour},
+		}}
+
+		recorder, commands := reconcile(t, cluster, observed)
+		assert.Equal(t, len(commands), 0)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("SwitchoverRequested", func(t *testing.T) {
+		cluster, observed := setup()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			Switchover: &v1beta1.PatroniSwitchover{Enabled: true},
+		}
+		cluster.Annotations = map[string]string{naming.PatroniSwitchover: "trigger"}
+		cluster.Status.Patroni.Switchover = initialize.String("trigger")
+
+		// The requested switchover is complete, so the primary moves back.
+		_, commands := reconcile(t, cluster, observed)
+		assert.Equal(t, len(commands), 1)
+		assert.Assert(t, cmp.Contains(commands[0], "--candidate=a1-0"))
+	})
+}
+
+func TestSetPendingRestartCondition(t *testing.T) {
+	now := time.Now()
+	restarting := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{"status": `{"pending_restart":true}`},
+	}}
+
+	t.Run("Nothing", func(t *testing.T) {
+		cluster := new(v1beta1.PostgresCluster)
+		assert.Assert(t, !setPendingRestartCondition(cluster, &observedInstances{}, now))
+
+		assert.Assert(t, meta.FindStatusCondition(
+			cluster.Status.Conditions, v1beta1.PendingRestart) == nil)
+	})
+
+	t.Run("Parameters", func(t *testing.T) {
+		cluster := new(v1beta1.PostgresCluster)
+		cluster.Status.Patroni.PendingRestart = []string{"max_connections", "shared_buffers"}
+		instances := &observedInstances{forCluster: []*Instance{
+			{Name: "one", Pods: []*corev1.Pod{restarting}},
+			{Name: "two", Pods: []*corev1.Pod{{}}},
+		}}
+
+		assert.Assert(t, setPendingRestartCondition(cluster, instances, now),
+			"expected a new condition")
+		assert.Assert(t, !setPendingRestartCondition(cluster, instances, now),
+			"expected no change")
+
+		condition := meta.FindStatusCondition(
+			cluster.Status.Conditions, v1beta1.PendingRestart)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionTrue)
+		assert.Equal(t, condition.Reason, "ParametersChanged")
+		assert.Assert(t, cmp.Contains(condition.Message, "max_connections, shared_buffers"))
+		assert.Assert(t, cmp.Contains(condition.Message, "pending restart: one"))
+
+		t.Run("Restarted", func(t *testing.T) {
+			instances := &observedInstances{forCluster: []*Instance{
+				{Name: "one", Pods: []*corev1.Pod{{}}},
+				{Name: "two", Pods: []*corev1.Pod{{}}},
+			}}
+
+			// Patroni may not have noticed the change yet.
+			setPendingRestartCondition(cluster, instances, now.Add(time.Second))
+			assert.Equal(t, len(cluster.Status.Patroni.PendingRestart), 2)
+			assert.Assert(t, meta.IsStatusConditionTrue(
+				cluster.Status.Conditions, v1beta1.PendingRestart))
+
+			setPendingRestartCondition(cluster, instances, now.Add(time.Minute))
+			assert.Equal(t, len(cluster.Status.Patroni.PendingRestart), 0)
+			assert.Assert(t, meta.FindStatusCondition(
+				cluster.Status.Conditions, v1beta1.PendingRestart) == nil)
+		})
+	})
+
+	t.Run("ParameterAdded", func(t *testing.T) {
+		cluster := new(v1beta1.PostgresCluster)
+		cluster.Status.Patroni.PendingRestart = []string{"max_connections"}
+		instances := &observedInstances{forCluster: []*Instance{
+			{Name: "one", Pods: []*corev1.Pod{{}}},
+		}}
+
+		assert.Assert(t, setPendingRestartCondition(cluster, instances, now))
+
+		// Another parameter changes long after the first.
+		cluster.Status.Patroni.PendingRestart = []string{"max_connections", "shared_buffers"}
+		cluster.Status.Patroni.PendingRestartSince = initialize.Pointer(metav1.NewTime(now.Add(time.Hour)))
+		assert.Assert(t, setPendingRestartCondition(cluster, instances, now.Add(time.Hour)))
+
+		// Patroni has not had time to notice the new parameter, so neither expires.
+		setPendingRestartCondition(cluster, instances, now.Add(time.Hour+time.Second))
+		assert.Equal(t, len(cluster.Status.Patroni.PendingRestart), 2)
+
+		setPendingRestartCondition(cluster, instances, now.Add(time.Hour+time.Minute))
+		assert.Equal(t, len(cluster.Status.Patroni.PendingRestart), 0)
+		assert.Assert(t, cluster.Status.Patroni.PendingRestartSince == nil)
+	})
+}
+
+func TestSetSynchronousReplicationCondition(t *testing.T) {
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Status.Patroni.SystemIdentifier = "some-identifier"
+
+	t.Run("Disabled", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Status.Conditions = []metav1.Condition{{
+			Type: v1beta1.SynchronousReplication, Status: metav1.ConditionTrue,
+		}}
+
+		setSynchronousReplicationCondition(cluster)
+		assert.Assert(t, meta.FindStatusCondition(
+			cluster.Status.Conditions, v1beta1.SynchronousReplication) == nil)
+	})
+
+	cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+		Synchronous: &v1beta1.PatroniSynchronous{
+			Enabled: true, NodeCount: initialize.Int32(2),
+		},
+	}
+
+	for _, tt := range []struct {
+		standbys []string
+		strict   bool
+		status   metav1.ConditionStatus
+		reason   string
+	}{
+		{standbys: []string{"a", "b"}, status: metav1.ConditionTrue, reason: "Synchronous"},
+		{standbys: []string{"a"}, status: metav1.ConditionTrue, reason: "Degraded"},
+		{standbys: nil, status: metav1.ConditionFalse, reason: "AsynchronousFallback"},
+		{standbys: nil, strict: true, status: metav1.ConditionFalse, reason: "NoStandbys"},
+	} {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Patroni.Synchronous.Strict = tt.strict
+		cluster.Status.Patroni.SynchronousStandbys = tt.standbys
+
+		setSynchronousReplicationCondition(cluster)
+		condition := meta.FindStatusCondition(
+			cluster.Status.Conditions, v1beta1.SynchronousReplication)
+		assert.Assert(t, condition != nil, "%+v", tt)
+		assert.Equal(t, condition.Status, tt.status, "%+v", tt)
+		assert.Equal(t, condition.Reason, tt.reason, "%+v", tt)
+	}
+}
+
+func TestValidateSynchronousReplication(t *testing.T) {
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Name = "pg3"
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Name: "one", Replicas: initialize.Int32(2)},
+		{Name: "two", Replicas: initialize.Int32(2), RecoveryMinApplyDelay: initialize.String("1h")},
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		reconciler := &Reconciler{}
+		assert.Assert(t, reconciler.Recorder == nil,
+			"expected the following to not use a Recorder at all")
+
+		reconciler.validateSynchronousReplication(cluster)
+	})
+
+	t.Run("Enough", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			Synchronous: &v1beta1.PatroniSynchronous{
+				Enabled: true, NodeCount: initialize.Int32(1),
+			},
+		}
+
+		reconciler := &Reconciler{}
+		reconciler.validateSynchronousReplication(cluster)
+	})
+
+	t.Run("TooMany", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			Synchronous: &v1beta1.PatroniSynchronous{
+				Enabled: true, NodeCount: initialize.Int32(2), Strict: true,
+			},
+		}
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+
+		reconciler.validateSynchronousReplication(cluster)
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Regarding.Name, cluster.Name)
+		assert.Equal(t, recorder.Events[0].Reason, "InvalidSynchronousReplication")
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "spec.patroni.synchronous.nodeCount"))
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "writes are blocked"))
+	})
+}
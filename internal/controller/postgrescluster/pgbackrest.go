@@ -5,12 +5,14 @@
 package postgrescluster
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -177,6 +179,21 @@ func (r *Reconciler) applyRepoVolumeIntent(ctx context.Context,
 		return nil, errors.WithStack(err)
 	}
 
+	var desired map[string]string
+	if postgresCluster.Status.PGBackRest != nil {
+		for _, status := range postgresCluster.Status.PGBackRest.Repos {
+			if status.Name == repoName {
+				desired = map[string]string{repoName: status.DesiredRepoVolume}
+			}
+		}
+	}
+	r.setVolumeRequest(ctx, postgresCluster, repo, "pgBackRest repo",
+		postgresCluster.Name+"/"+repoName, desired)
+
+	// Clear any set limit before applying PVC. This is needed to allow the limit
+	// value to change later.
+	repo.Spec.Resources.Limits = nil
+
 	if err := r.apply(ctx, repo); err != nil {
 		return nil, r.handlePersistentVolumeClaimError(postgresCluster,
 			errors.WithStack(err))
@@ -1346,7 +1363,7 @@ func (r *Reconciler) generateRestoreJobIntent(cluster *v1beta1.PostgresCluster,
 func (r *Reconciler) reconcilePGBackRest(ctx context.Context,
 	postgresCluster *v1beta1.PostgresCluster,
 	instances *observedInstances,
-	rootCA *pki.RootCertificateAuthority,
+	rootCA pki.Issuer,
 	backupsSpecFound bool,
 ) (reconcile.Result, error) {
 
@@ -1486,6 +1503,13 @@ func (r *Reconciler) reconcilePGBackRest(ctx context.Context,
 		result.Requeue = true
 	}
 
+	// Nothing notifies the operator when repository volumes fill up, so
+	// measure them again in a minute.
+	if repoVolumesCanGrow(ctx, postgresCluster) &&
+		(result.RequeueAfter == 0 || result.RequeueAfter > time.Minute) {
+		result.RequeueAfter = time.Minute
+	}
+
 	return result, nil
 }
 
@@ -1498,7 +1522,7 @@ func (r *Reconciler) reconcilePGBackRest(ctx context.Context,
 func (r *Reconciler) reconcilePostgresClusterDataSource(ctx context.Context,
 	cluster *v1beta1.PostgresCluster, dataSource *v1beta1.PostgresClusterDataSource,
 	configHash string, clusterVolumes []corev1.PersistentVolumeClaim,
-	rootCA *pki.RootCertificateAuthority,
+	rootCA pki.Issuer,
 	backupsSpecFound bool,
 ) error {
 
@@ -2010,7 +2034,7 @@ func (r *Reconciler) reconcilePGBackRestConfig(ctx context.Context,
 // reconcilePGBackRestSecret reconciles the pgBackRest Secret.
 func (r *Reconciler) reconcilePGBackRestSecret(ctx context.Context,
 	cluster *v1beta1.PostgresCluster, repoHost *appsv1.StatefulSet,
-	rootCA *pki.RootCertificateAuthority) error {
+	rootCA pki.Issuer) error {
 
 	intent := &corev1.Secret{ObjectMeta: naming.PGBackRestSecret(cluster)}
 	intent.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
@@ -2553,6 +2577,104 @@ func (r *Reconciler) reconcileReplicaCreateBackup(ctx context.Context,
 	return nil
 }
 
+// repoVolumesCanGrow returns whether or not any repository volume of cluster
+// has a storage limit up to which it grows automatically.
+func repoVolumesCanGrow(ctx context.Context, cluster *v1beta1.PostgresCluster) bool {
+	if !feature.Enabled(ctx, feature.AutoGrowVolumes) {
+		return false
+	}
+	for _, repo := range cluster.Spec.Backups.PGBackRest.Repos {
+		if repo.Volume != nil && !repo.Volume.VolumeClaimSpec.Resources.Limits.Storage().IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:rbac:groups="",resources="pods",verbs={list}
+// +kubebuilder:rbac:groups="",resources="pods/exec",verbs={create}
+
+// storeDesiredRepoVolumes measures the repository volumes of cluster that have
+// a limit and saves a larger size for those that are nearly full to the
+// PostgresCluster status. If a value has grown, create an Event. The volumes
+// are measured through the dedicated repository host so that it needs no
+// credentials for the Kubernetes API.
+func (r *Reconciler) storeDesiredRepoVolumes(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) error {
+	if !repoVolumesCanGrow(ctx, cluster) || cluster.Status.PGBackRest == nil {
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := errors.WithStack(r.Client.List(ctx, pods,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabelsSelector{Selector: naming.PGBackRestDedicatedSelector(cluster.Name)},
+	)); err != nil {
+		return err
+	}
+
+	var pod *corev1.Pod
+	for i := range pods.Items {
+		if pods.Items[i].DeletionTimestamp == nil &&
+			pods.Items[i].Status.Phase == corev1.PodRunning {
+			pod = &pods.Items[i]
+		}
+	}
+
+	// Repository volumes are mounted by name; see [pgbackrest.AddRepoVolumesToPod].
+	limited := make(map[string]bool)
+	paths := []string{}
+	for _, repo := range cluster.Spec.Backups.PGBackRest.Repos {
+		if repo.Volume != nil && !repo.Volume.VolumeClaimSpec.Resources.Limits.Storage().IsZero() {
+			limited[repo.Name] = true
+			paths = append(paths, "/pgbackrest/"+repo.Name)
+		}
+	}
+
+	var suggested map[string]string
+	if pod != nil && len(paths) > 0 {
+		var stdout, stderr bytes.Buffer
+		command := append([]string{"df", "--block-size=M", "--output=target,size,pcent"}, paths...)
+		if err := r.PodExec(ctx, pod.Namespace, pod.Name, naming.PGBackRestRepoContainerName,
+			nil, &stdout, &stderr, command...); err != nil {
+			return errors.WithStack(errors.Wrap(err, stderr.String()))
+		}
+		suggested = suggestedRepoVolumeSizes(stdout.String())
+	}
+
+	for i := range cluster.Status.PGBackRest.Repos {
+		status := &cluster.Status.PGBackRest.Repos[i]
+
+		status.DesiredRepoVolume = r.storeDesiredVolumeRequest(ctx, cluster,
+			"pgBackRest repo", cluster.Name+"/"+status.Name, limited[status.Name],
+			suggested[status.Name], status.DesiredRepoVolume)
+	}
+
+	return nil
+}
+
+// suggestedRepoVolumeSizes reads the output of `df --output=target,size,pcent`
+// and returns a larger size for each repository volume that is more than 75%
+// full, keyed by repository name. This matches the policy of the data volume.
+func suggestedRepoVolumeSizes(df string) map[string]string {
+	suggested := make(map[string]string)
+	for _, line := range strings.Split(df, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || !strings.HasPrefix(fields[0], "/pgbackrest/") {
+			continue
+		}
+
+		size, err1 := strconv.ParseInt(strings.TrimSuffix(fields[1], "M"), 10, 64)
+		use, err2 := strconv.ParseInt(strings.TrimSuffix(fields[2], "%"), 10, 64)
+		if err1 == nil && err2 == nil && use > 75 {
+			suggested[strings.TrimPrefix(fields[0], "/pgbackrest/")] =
+				strconv.FormatInt(size/2+size, 10) + "Mi"
+		}
+	}
+	return suggested
+}
+
 // reconcileRepos is responsible for reconciling any pgBackRest repositories configured
 // for the cluster
 func (r *Reconciler) reconcileRepos(ctx context.Context,
@@ -2564,6 +2686,12 @@ func (r *Reconciler) reconcileRepos(ctx context.Context,
 	errors := []error{}
 	errMsg := "reconciling repository volume"
 	repoVols := []*corev1.PersistentVolumeClaim{}
+
+	if err := r.storeDesiredRepoVolumes(ctx, postgresCluster); err != nil {
+		log.Error(err, errMsg)
+		errors = append(errors, err)
+	}
+
 	var replicaCreateRepo v1beta1.PGBackRestRepo
 	for i, repo := range postgresCluster.Spec.Backups.PGBackRest.Repos {
 		// the repo at index 0 is the replica creation repo
@@ -33,15 +33,19 @@ import (
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	pgoRuntime "github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/internal/feature"
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/internal/pgbackrest"
 	"github.com/crunchydata/postgres-operator/internal/pki"
 	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/internal/testing/events"
 	"github.com/crunchydata/postgres-operator/internal/testing/require"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
@@ -3900,3 +3904,97 @@ func TestBackupsEnabled(t *testing.T) {
 		assert.Assert(t, backupsReconciliationAllowed)
 	})
 }
+
+func TestStoreDesiredRepoVolumes(t *testing.T) {
+	gate := feature.NewGate()
+	assert.NilError(t, gate.SetFromMap(map[string]bool{
+		feature.AutoGrowVolumes: true,
+	}))
+	ctx := feature.NewContext(context.Background(), gate)
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{{
+		Name: "repo1",
+		Volume: &v1beta1.RepoPVC{VolumeClaimSpec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.VolumeResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		}},
+	}, {
+		Name:   "repo2",
+		Volume: &v1beta1.RepoPVC{},
+	}}
+	cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+		Repos: []v1beta1.RepoStatus{
+			{Name: "repo1", DesiredRepoVolume: "2Gi"},
+			{Name: "repo2"},
+		},
+	}
+
+	assert.Assert(t, repoVolumesCanGrow(ctx, cluster))
+	assert.Assert(t, !repoVolumesCanGrow(context.Background(), cluster))
+
+	pod := &corev1.Pod{}
+	pod.Namespace, pod.Name = "ns1", "hippo-repo-host-0"
+	pod.Labels = naming.PGBackRestDedicatedLabels("hippo")
+	pod.Status.Phase = corev1.PodRunning
+
+	recorder := events.NewRecorder(t, pgoRuntime.Scheme)
+	r := &Reconciler{
+		Client:   fake.NewClientBuilder().WithScheme(pgoRuntime.Scheme).WithObjects(pod).Build(),
+		Recorder: recorder,
+	}
+
+	var calls []string
+	r.PodExec = func(
+		ctx context.Context, namespace, pod, container string,
+		stdin io.Reader, stdout, stderr io.Writer, command ...string,
+	) error {
+		calls = append(calls, pod+"/"+container+": "+strings.Join(command, " "))
+		_, _ = stdout.Write([]byte("" +
+			"Mounted on      1M-blocks Use%\n" +
+			"/pgbackrest/repo1   2000M  80%\n"))
+		return nil
+	}
+
+	assert.NilError(t, r.storeDesiredRepoVolumes(ctx, cluster))
+	assert.DeepEqual(t, calls, []string{
+		"hippo-repo-host-0/pgbackrest: df --block-size=M --output=target,size,pcent /pgbackrest/repo1",
+	})
+	assert.Equal(t, cluster.Status.PGBackRest.Repos[0].DesiredRepoVolume, "3000Mi")
+	assert.Equal(t, cluster.Status.PGBackRest.Repos[1].DesiredRepoVolume, "")
+
+	// Only the volume with a limit grows.
+	assert.Equal(t, len(recorder.Events), 1)
+	assert.Equal(t, recorder.Events[0].Reason, "VolumeAutoGrow")
+	assert.Equal(t, recorder.Events[0].Note, "pgBackRest repo volume expansion to 3000Mi requested for hippo/repo1.")
+
+	t.Run("Unchanged", func(t *testing.T) {
+		r.PodExec = func(
+			ctx context.Context, namespace, pod, container string,
+			stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			_, _ = stdout.Write([]byte("" +
+				"Mounted on      1M-blocks Use%\n" +
+				"/pgbackrest/repo1   3000M  50%\n"))
+			return nil
+		}
+
+		// The previous value is kept when the volume has room.
+		assert.NilError(t, r.storeDesiredRepoVolumes(ctx, cluster))
+		assert.Equal(t, cluster.Status.PGBackRest.Repos[0].DesiredRepoVolume, "3000Mi")
+	})
+
+	t.Run("RepoHost", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Backups.PGBackRest.RepoHost = &v1beta1.PGBackRestRepoHost{}
+
+		sts, err := r.generateRepoHostIntent(ctx, cluster, "", &RepoResources{}, &observedInstances{})
+		assert.NilError(t, err)
+
+		// The repository host has no credentials for the Kubernetes API.
+		assert.Equal(t, sts.Spec.Template.Spec.ServiceAccountName, "")
+		assert.Equal(t, *sts.Spec.Template.Spec.AutomountServiceAccountToken, false)
+	})
+}
@@ -31,7 +31,7 @@ import (
 func (r *Reconciler) reconcilePGBouncer(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
 	primaryCertificate *corev1.SecretProjection,
-	root *pki.RootCertificateAuthority,
+	root pki.Issuer,
 ) error {
 	var (
 		configmap *corev1.ConfigMap
@@ -187,7 +187,7 @@ func (r *Reconciler) reconcilePGBouncerInPostgreSQL(
 // reconcilePGBouncerSecret writes the Secret for a PgBouncer Pod.
 func (r *Reconciler) reconcilePGBouncerSecret(
 	ctx context.Context, cluster *v1beta1.PostgresCluster,
-	root *pki.RootCertificateAuthority, service *corev1.Service,
+	root pki.Issuer, service *corev1.Service,
 ) (*corev1.Secret, error) {
 	existing := &corev1.Secret{ObjectMeta: naming.ClusterPGBouncer(cluster)}
 	err := errors.WithStack(
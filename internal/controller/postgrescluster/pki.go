@@ -6,10 +6,17 @@ package postgrescluster
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crunchydata/postgres-operator/internal/naming"
@@ -31,20 +38,28 @@ const (
 // in the relevant secret, has been created and is not 'bad' due
 // to being expired, formatted incorrectly, etc.
 // If it is bad for some reason, a new root certificate is
-// generated for use.
+// generated for use. When the cluster has a certificate issuer, that is
+// returned instead; it is nil until the issuer is ready.
 func (r *Reconciler) reconcileRootCertificate(
 	ctx context.Context, cluster *v1beta1.PostgresCluster,
 ) (
-	*pki.RootCertificateAuthority, error,
+	pki.Issuer, error,
 ) {
 	const keyCertificate, keyPrivateKey = "root.crt", "root.key"
 
+	// Use the certificate issuer, if any.
+	if cluster.Spec.CertificateIssuer != nil {
+		return r.reconcileCertificateIssuer(ctx, cluster)
+	}
+	meta.RemoveStatusCondition(&cluster.Status.Conditions, v1beta1.CertificateIssuerReady)
+
 	existing := &corev1.Secret{}
 	existing.Namespace, existing.Name = cluster.Namespace, naming.RootCertSecret
 	err := errors.WithStack(client.IgnoreNotFound(
 		r.Client.Get(ctx, client.ObjectKeyFromObject(existing), existing)))
 
 	root := &pki.RootCertificateAuthority{}
+	rotation := rootRotation{}
 
 	if err == nil {
 		// Unmarshal and validate the stored root. These first errors can
@@ -52,13 +67,34 @@ func (r *Reconciler) reconcileRootCertificate(
 		// correctly regenerated.
 		_ = root.Certificate.UnmarshalText(existing.Data[keyCertificate])
 		_ = root.PrivateKey.UnmarshalText(existing.Data[keyPrivateKey])
+		rotation = parseRootRotation(existing)
 
 		if !pki.RootIsValid(root) {
-			root, err = pki.NewRootCertificateAuthority()
+			root, err = r.CertificatePolicy.NewRootCertificateAuthority()
 			err = errors.WithStack(err)
+			rotation = rootRotation{}
 		}
 	}
 
+	// The root is shared by clusters in the namespace, so any new one follows
+	// the policy of the operator rather than that of cluster.
+	root.Policy = r.CertificatePolicy
+
+	// Each phase of a rotation lasts until PostgreSQL in every instance of
+	// the namespace loads the authorities of that phase.
+	var observed bool
+	if err == nil && rotation.Phase != "" {
+		rotation.trust(root)
+		observed, err = r.rootTrustObserved(ctx, cluster.Namespace, root.Bundle())
+	}
+
+	// Advance any rotation of the root. Its state is stored in the root Secret
+	// so that every cluster in the namespace sees the same phase.
+	if err == nil {
+		root, rotation, err = advanceRootRotation(cluster, root, rotation, time.Now(), observed)
+		err = errors.WithStack(err)
+	}
+
 	intent := &corev1.Secret{}
 	intent.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
 	intent.Namespace, intent.Name = cluster.Namespace, naming.RootCertSecret
@@ -85,13 +121,224 @@ func (r *Reconciler) reconcileRootCertificate(
 		intent.Data[keyPrivateKey], err = root.PrivateKey.MarshalText()
 		err = errors.WithStack(err)
 	}
+	if err == nil {
+		err = errors.WithStack(rotation.store(intent))
+	}
 	if err == nil {
 		err = errors.WithStack(r.apply(ctx, intent))
 	}
 
+	root.Policy = r.certificatePolicy(cluster)
 	return root, err
 }
 
+// certificatePolicy returns the policy of certificates that the operator
+// issues for cluster: the policy of the operator with any fields of
+// cluster.Spec.CertificatePolicy.
+func (r *Reconciler) certificatePolicy(cluster *v1beta1.PostgresCluster) pki.Policy {
+	policy := r.CertificatePolicy
+
+	if spec := cluster.Spec.CertificatePolicy; spec != nil {
+		override := pki.Policy{KeyAlgorithm: pki.KeyAlgorithm(spec.KeyAlgorithm)}
+		if spec.LeafLifetime != nil {
+			override.LeafLifetime = spec.LeafLifetime.Duration
+		}
+		if spec.RenewBefore != nil {
+			override.RenewBefore = spec.RenewBefore.Duration
+		}
+		policy = policy.Merge(override)
+	}
+
+	return policy
+}
+
+// rootRotationCheck is how often to check whether every instance loaded the
+// certificate authorities of a root rotation phase. Instances in other
+// clusters do not cause an event that triggers another reconcile.
+const rootRotationCheck = 30 * time.Second
+
+// rootRotation is the state of a rotation of the root certificate authority.
+// The Next authority is trusted while "Distributing"; the Previous one is
+// trusted while "Reissuing".
+type rootRotation struct {
+	Phase          string
+	Since          time.Time
+	Next, Previous *pki.RootCertificateAuthority
+}
+
+const (
+	rootNextCertificate     = "root-next.crt"
+	rootNextPrivateKey      = "root-next.key"
+	rootPreviousCertificate = "root-previous.crt"
+)
+
+// parseRootRotation returns the rotation stored in the root Secret, if any.
+func parseRootRotation(secret *corev1.Secret) rootRotation {
+	phase, since, _ := strings.Cut(secret.Annotations[naming.RootCertificateRotation], " ")
+	started, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return rootRotation{}
+	}
+
+	// These errors can be ignored because they result in an invalid root
+	// and no rotation.
+	other := &pki.RootCertificateAuthority{}
+	switch phase {
+	case v1beta1.RootCertificateRotationDistributing:
+		_ = other.Certificate.UnmarshalText(secret.Data[rootNextCertificate])
+		_ = other.PrivateKey.UnmarshalText(secret.Data[rootNextPrivateKey])
+		if pki.RootIsValid(other) {
+			return rootRotation{Phase: phase, Since: started, Next: other}
+		}
+	case v1beta1.RootCertificateRotationReissuing:
+		if other.Certificate.UnmarshalText(secret.Data[rootPreviousCertificate]) == nil {
+			return rootRotation{Phase: phase, Since: started, Previous: other}
+		}
+	}
+	return rootRotation{}
+}
+
+// store writes rotation into the root Secret.
+func (rotation rootRotation) store(secret *corev1.Secret) error {
+	var err error
+	switch rotation.Phase {
+	case v1beta1.RootCertificateRotationDistributing:
+		secret.Data[rootNextCertificate], err = rotation.Next.Certificate.MarshalText()
+		if err == nil {
+			secret.Data[rootNextPrivateKey], err = rotation.Next.PrivateKey.MarshalText()
+		}
+	case v1beta1.RootCertificateRotationReissuing:
+		secret.Data[rootPreviousCertificate], err = rotation.Previous.Certificate.MarshalText()
+	default:
+		return nil
+	}
+
+	secret.Annotations = naming.Merge(secret.Annotations, map[string]string{
+		naming.RootCertificateRotation: rotation.Phase + " " + rotation.Since.UTC().Format(time.RFC3339),
+	})
+	return err
+}
+
+// trust sets the authorities that root should trust, other than itself, in
+// the current phase of rotation.
+func (rotation rootRotation) trust(root *pki.RootCertificateAuthority) {
+	root.Trusted = nil
+	if rotation.Next != nil {
+		root.Trusted = append(root.Trusted, rotation.Next.Certificate)
+	}
+	if rotation.Previous != nil {
+		root.Trusted = append(root.Trusted, rotation.Previous.Certificate)
+	}
+}
+
+// advanceRootRotation moves rotation to its next phase when every instance
+// has observed the current one, or starts a rotation when cluster is annotated
+// to do so. It returns the authority that signs certificates with any other
+// authority it should trust, and records the phase in the status of cluster.
+func advanceRootRotation(
+	cluster *v1beta1.PostgresCluster, root *pki.RootCertificateAuthority,
+	rotation rootRotation, now time.Time, observed bool,
+) (*pki.RootCertificateAuthority, rootRotation, error) {
+	status := cluster.Status.RootCertificateRotation
+	trigger := cluster.Annotations[naming.RotateRootCertificate]
+
+	switch {
+	case rotation.Phase == "" && trigger != "" && (status == nil || status.Trigger != trigger):
+		next, err := root.Policy.NewRootCertificateAuthority()
+		if err != nil {
+			return root, rotation, err
+		}
+		rotation = rootRotation{
+			Phase: v1beta1.RootCertificateRotationDistributing, Since: now, Next: next,
+		}
+		status = &v1beta1.RootCertificateRotationStatus{Trigger: trigger}
+
+	case rotation.Phase == v1beta1.RootCertificateRotationDistributing && observed:
+		root, rotation = rotation.Next, rootRotation{
+			Phase: v1beta1.RootCertificateRotationReissuing, Since: now, Previous: root,
+		}
+
+	case rotation.Phase == v1beta1.RootCertificateRotationReissuing && observed:
+		rotation = rootRotation{}
+	}
+
+	rotation.trust(root)
+
+	// Report the phase of the rotation. The phase after the last one is
+	// "Complete".
+	if status == nil && rotation.Phase != "" {
+		status = &v1beta1.RootCertificateRotationStatus{}
+	}
+	if status != nil {
+		phase, since := rotation.Phase, rotation.Since
+		if phase == "" {
+			phase, since = v1beta1.RootCertificateRotationComplete, now
+		}
+		if status.Phase != phase {
+			status.Phase = phase
+			status.LastTransitionTime = metav1.NewTime(since)
+		}
+	}
+	cluster.Status.RootCertificateRotation = status
+
+	return root, rotation, nil
+}
+
+// rootRotationRequeue returns how long to wait before checking again whether
+// every instance observed the current phase of a root rotation.
+func rootRotationRequeue(cluster *v1beta1.PostgresCluster) time.Duration {
+	status := cluster.Status.RootCertificateRotation
+	if status == nil || status.Phase == v1beta1.RootCertificateRotationComplete {
+		return 0
+	}
+	return rootRotationCheck
+}
+
+// +kubebuilder:rbac:groups="",resources="pods",verbs={list}
+// +kubebuilder:rbac:groups="postgres-operator.crunchydata.com",resources="postgresclusters",verbs={list}
+
+// rootTrustObserved returns whether or not PostgreSQL in every running
+// instance of namespace has loaded bundle. Instances annotate their Pods with
+// the SHA-256 of the authorities they loaded. Clusters that do not use the
+// root certificate authority of namespace are ignored.
+func (r *Reconciler) rootTrustObserved(
+	ctx context.Context, namespace string, bundle pki.CertificateBundle,
+) (bool, error) {
+	text, err := bundle.MarshalText()
+	sum := sha256.Sum256(text)
+	expected := hex.EncodeToString(sum[:])
+
+	clusters := &v1beta1.PostgresClusterList{}
+	pods := &corev1.PodList{}
+	if err == nil {
+		err = errors.WithStack(r.Client.List(ctx, clusters, client.InNamespace(namespace)))
+	}
+	if err == nil {
+		err = errors.WithStack(r.Client.List(ctx, pods, client.InNamespace(namespace),
+			client.HasLabels{naming.LabelCluster, naming.LabelInstance}))
+	}
+
+	using := sets.New[string]()
+	for i := range clusters.Items {
+		if spec := clusters.Items[i].Spec; spec.CertificateIssuer == nil && spec.CustomTLSSecret == nil {
+			using.Insert(clusters.Items[i].Name)
+		}
+	}
+
+	// Pods that are not running load the authorities when they start.
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !using.Has(pod.Labels[naming.LabelCluster]) ||
+			pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if pod.Annotations[naming.TrustedCertificateAuthorities] != expected {
+			return false, err
+		}
+	}
+	return err == nil, err
+}
+
 // +kubebuilder:rbac:groups="",resources="secrets",verbs={get}
 // +kubebuilder:rbac:groups="",resources="secrets",verbs={create,patch}
 
@@ -105,7 +352,7 @@ func (r *Reconciler) reconcileRootCertificate(
 // tls.crt, tls.key and ca.crt which are the TLS certificate, private key
 // and CA certificate, respectively.
 func (r *Reconciler) reconcileClusterCertificate(
-	ctx context.Context, root *pki.RootCertificateAuthority,
+	ctx context.Context, root pki.Issuer,
 	cluster *v1beta1.PostgresCluster, primaryService *corev1.Service,
 	replicaService *corev1.Service,
 ) (
@@ -163,7 +410,7 @@ func (r *Reconciler) reconcileClusterCertificate(
 		err = errors.WithStack(err)
 	}
 	if err == nil {
-		intent.Data[rootCA], err = root.Certificate.MarshalText()
+		intent.Data[rootCA], err = root.Bundle().MarshalText()
 		err = errors.WithStack(err)
 	}
 
@@ -191,7 +438,7 @@ func (r *Reconciler) reconcileClusterCertificate(
 // using the current root certificate
 func (*Reconciler) instanceCertificate(
 	ctx context.Context, instance *appsv1.StatefulSet,
-	existing, intent *corev1.Secret, root *pki.RootCertificateAuthority,
+	existing, intent *corev1.Secret, root pki.Issuer,
 ) (
 	*pki.LeafCertificate, error,
 ) {
@@ -228,6 +475,43 @@ func (*Reconciler) instanceCertificate(
 	return leaf, err
 }
 
+// postgresUserCertificate populates intent with a client certificate and key
+// for the user in spec, signed by root. It keeps those in existing while they
+// are valid. The certificate has the name of the user as its common name.
+// - https://www.postgresql.org/docs/current/auth-cert.html
+func postgresUserCertificate(
+	root pki.Issuer, spec *v1beta1.PostgresUserSpec,
+	existing, intent *corev1.Secret,
+) error {
+	leaf := &pki.LeafCertificate{}
+	commonName := string(spec.Name)
+
+	if existing != nil {
+		// Unmarshal and validate the stored leaf. These first errors can
+		// be ignored because they result in an invalid leaf which is then
+		// correctly regenerated.
+		_ = leaf.Certificate.UnmarshalText(existing.Data[clusterCertFile])
+		_ = leaf.PrivateKey.UnmarshalText(existing.Data[clusterKeyFile])
+	}
+
+	leaf, err := root.RegenerateLeafWhenNecessary(leaf, commonName, nil)
+	err = errors.WithStack(err)
+
+	if err == nil {
+		intent.Data[clusterCertFile], err = leaf.Certificate.MarshalText()
+		err = errors.WithStack(err)
+	}
+	if err == nil {
+		intent.Data[clusterKeyFile], err = leaf.PrivateKey.MarshalText()
+		err = errors.WithStack(err)
+	}
+	if err == nil {
+		intent.Data[rootCertFile], err = root.Bundle().MarshalText()
+		err = errors.WithStack(err)
+	}
+	return err
+}
+
 // clusterCertSecretProjection returns a secret projection of the postgrescluster's
 // CA, key, and certificate to include in the instance configuration volume.
 func clusterCertSecretProjection(certificate *corev1.Secret) *corev1.SecretProjection {
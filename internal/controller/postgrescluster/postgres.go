@@ -14,12 +14,15 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -29,6 +32,7 @@ import (
 	"github.com/crunchydata/postgres-operator/internal/logging"
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/internal/pgaudit"
+	"github.com/crunchydata/postgres-operator/internal/pki"
 	"github.com/crunchydata/postgres-operator/internal/postgis"
 	"github.com/crunchydata/postgres-operator/internal/postgres"
 	pgpassword "github.com/crunchydata/postgres-operator/internal/postgres/password"
@@ -39,11 +43,15 @@ import (
 // generatePostgresUserSecret returns a Secret containing a password and
 // connection details for the first database in spec. When existing is nil or
 // lacks a password or verifier, a new password and verifier are generated.
+// The user in existing is kept when it is the alternate role of spec.
 func (r *Reconciler) generatePostgresUserSecret(
 	cluster *v1beta1.PostgresCluster, spec *v1beta1.PostgresUserSpec, existing *corev1.Secret,
 ) (*corev1.Secret, error) {
 	username := string(spec.Name)
-	intent := &corev1.Secret{ObjectMeta: naming.PostgresUserSecret(cluster, username)}
+	if existing != nil && string(existing.Data["user"]) == alternatePostgresUser(username) {
+		username = alternatePostgresUser(username)
+	}
+	intent := &corev1.Secret{ObjectMeta: naming.PostgresUserSecret(cluster, string(spec.Name))}
 	intent.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
 	initialize.Map(&intent.Data)
 
@@ -62,6 +70,10 @@ func (r *Reconciler) generatePostgresUserSecret(
 	if existing != nil {
 		intent.Data["password"] = existing.Data["password"]
 		intent.Data["verifier"] = existing.Data["verifier"]
+
+		if len(existing.Data["previous-verifier"]) > 0 {
+			intent.Data["previous-verifier"] = existing.Data["previous-verifier"]
+		}
 	}
 
 	// When password is unset, generate a new one according to the specified policy.
@@ -160,12 +172,17 @@ func (r *Reconciler) generatePostgresUserSecret(
 	}
 
 	intent.Annotations = cluster.Spec.Metadata.GetAnnotationsOrNil()
+	if existing != nil && existing.Annotations[naming.PasswordRotated] != "" {
+		intent.Annotations = naming.Merge(intent.Annotations, map[string]string{
+			naming.PasswordRotated: existing.Annotations[naming.PasswordRotated],
+		})
+	}
 	intent.Labels = naming.Merge(
 		cluster.Spec.Metadata.GetLabelsOrNil(),
 		map[string]string{
 			naming.LabelCluster:      cluster.Name,
 			naming.LabelRole:         naming.RolePostgresUser,
-			naming.LabelPostgresUser: username,
+			naming.LabelPostgresUser: string(spec.Name),
 		})
 
 	err := errors.WithStack(r.setControllerReference(cluster, intent))
@@ -173,9 +190,104 @@ func (r *Reconciler) generatePostgresUserSecret(
 	return intent, err
 }
 
+// reconcilePostgresTablespaces creates the tablespaces of instance sets inside
+// of PostgreSQL and reports their size in cluster.Status. Tablespaces that are
+// removed from the spec are dropped after no database has objects in them.
+func (r *Reconciler) reconcilePostgresTablespaces(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) error {
+	const container = naming.ContainerDatabase
+
+	if !feature.Enabled(ctx, feature.TablespaceVolumes) {
+		return nil
+	}
+
+	// A tablespace can be created only when every instance has a volume for
+	// it. Replicas fail to replay the creation of a tablespace otherwise.
+	var specifications []v1beta1.TablespaceVolume
+	specified := sets.New[string]()
+	counts := make(map[string]int)
+	for _, set := range cluster.Spec.InstanceSets {
+		for _, tablespace := range set.TablespaceVolumes {
+			if counts[tablespace.Name]++; counts[tablespace.Name] == 1 {
+				specifications = append(specifications, tablespace)
+			}
+			specified.Insert(tablespace.Name)
+		}
+	}
+	var creatable []v1beta1.TablespaceVolume
+	for _, tablespace := range specifications {
+		if counts[tablespace.Name] == len(cluster.Spec.InstanceSets) {
+			creatable = append(creatable, tablespace)
+		} else {
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "TablespaceNotCreated",
+				"Tablespace %v is not in every instance set; it must be for PostgreSQL to create it",
+				tablespace.Name)
+		}
+	}
+
+	var removed []string
+	for _, status := range cluster.Status.Tablespaces {
+		if !specified.Has(status.Name) {
+			removed = append(removed, status.Name)
+		}
+	}
+
+	if len(creatable) == 0 && len(removed) == 0 {
+		cluster.Status.Tablespaces = nil
+		return nil
+	}
+
+	// Find the PostgreSQL instance that can execute SQL that writes system
+	// catalogs. When there is none, return early.
+	pod, _ := instances.writablePod(container)
+	if pod == nil {
+		return nil
+	}
+
+	ctx = logging.NewContext(ctx, logging.FromContext(ctx).WithValues("pod", pod.Name))
+	podExecutor := func(
+		ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+	) error {
+		return r.PodExec(ctx, pod.Namespace, pod.Name, container, stdin, stdout, stderr, command...)
+	}
+
+	existing, err := postgres.ManageTablespacesInPostgreSQL(ctx, podExecutor, creatable, removed)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	cluster.Status.Tablespaces = nil
+	for _, name := range sets.List(sets.KeySet(existing)) {
+		status := v1beta1.PostgresTablespaceStatus{
+			Name:      name,
+			Size:      resource.NewQuantity(existing[name].Size, resource.BinarySI),
+			Databases: existing[name].Databases,
+			Retained:  !specified.Has(name),
+		}
+		if status.Retained {
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "TablespaceInUse",
+				"Tablespace %v was removed from the spec but has objects in databases %v; keeping its volumes",
+				name, status.Databases)
+		}
+		cluster.Status.Tablespaces = append(cluster.Status.Tablespaces, status)
+	}
+
+	return nil
+}
+
+// databaseCheckInterval is how often databases are compared to their
+// specifications when nothing else changes.
+const databaseCheckInterval = time.Hour
+
+// databaseRetryInterval is how often databases that differ from their
+// specifications are checked again.
+const databaseRetryInterval = time.Minute
+
 // reconcilePostgresDatabases creates databases inside of PostgreSQL.
 func (r *Reconciler) reconcilePostgresDatabases(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+	now time.Time,
 ) error {
 	const container = naming.ContainerDatabase
 	var podExecutor postgres.Executor
@@ -221,6 +333,21 @@ func (r *Reconciler) reconcilePostgresDatabases(
 		}
 	}
 
+	// Create the databases of spec.databases first and in order, so one can be
+	// the template of another. Their settings take precedence over spec.users.
+	specifications := make([]v1beta1.PostgresDatabaseSpec, 0, len(databases)+len(cluster.Spec.Databases))
+	specifications = append(specifications, cluster.Spec.Databases...)
+	for _, spec := range cluster.Spec.Databases {
+		databases.Delete(string(spec.Name))
+	}
+	for _, name := range sets.List(databases) {
+		specifications = append(specifications, v1beta1.PostgresDatabaseSpec{
+			Name: v1beta1.PostgresIdentifier(name),
+		})
+	}
+
+	var drift map[string][]string
+	var extensions, extensionsFailed map[string]map[string]string
 	var pgAuditOK, postgisInstallOK bool
 	create := func(ctx context.Context, exec postgres.Executor) error {
 		if pgAuditOK = pgaudit.EnableInPostgreSQL(ctx, exec) == nil; !pgAuditOK {
@@ -244,11 +371,29 @@ func (r *Reconciler) reconcilePostgresDatabases(
 				"Unable to install PostGIS")
 		}
 
-		return postgres.CreateDatabasesInPostgreSQL(ctx, exec, sets.List(databases))
+		var err error
+		drift, err = postgres.CreateDatabasesInPostgreSQL(ctx, exec, specifications)
+
+		extensions = make(map[string]map[string]string)
+		extensionsFailed = make(map[string]map[string]string)
+		for _, spec := range cluster.Spec.Databases {
+			if err == nil && len(spec.Extensions) > 0 {
+				name := string(spec.Name)
+				extensions[name], extensionsFailed[name], err =
+					postgres.UpdateExtensionsInPostgreSQL(ctx, exec, name, spec.Extensions)
+			}
+		}
+		return err
 	}
 
 	// Calculate a hash of the SQL that should be executed in PostgreSQL.
+	// Include the current check interval so that databases are compared to
+	// their specifications again when it passes.
 	revision, err := safeHash32(func(hasher io.Writer) error {
+		if _, err := fmt.Fprint(hasher, now.Truncate(databaseCheckInterval).Unix()); err != nil {
+			return err
+		}
+
 		// Discard log messages about executing SQL.
 		return create(logging.NewContext(ctx, logging.Discard()), func(
 			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
@@ -276,21 +421,105 @@ func (r *Reconciler) reconcilePostgresDatabases(
 		log := logging.FromContext(ctx).WithValues("revision", revision)
 		err = errors.WithStack(create(logging.NewContext(ctx, log), podExecutor))
 	}
-	if err == nil && pgAuditOK && postgisInstallOK {
+	var differs []string
+	if err == nil {
+		previous := make(map[string]string)
+		for _, status := range cluster.Status.Databases {
+			for _, extension := range status.Extensions {
+				previous[status.Name+"/"+extension.Name] = extension.Error
+			}
+		}
+
+		cluster.Status.Databases = nil
+		for _, spec := range cluster.Spec.Databases {
+			status := v1beta1.PostgresDatabaseStatus{
+				Name:  string(spec.Name),
+				Drift: drift[string(spec.Name)],
+			}
+			differ := len(status.Drift) > 0
+			for _, extension := range spec.Extensions {
+				version, installed := extensions[status.Name][string(extension.Name)]
+				message, failed := extensionsFailed[status.Name][string(extension.Name)]
+
+				// Report each error once rather than on every attempt.
+				if failed && previous[status.Name+"/"+string(extension.Name)] != message {
+					r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "ExtensionFailed",
+						"Unable to create or update extension %q in database %q: %v",
+						extension.Name, status.Name, message)
+				}
+				if installed || failed {
+					status.Extensions = append(status.Extensions, v1beta1.PostgresExtensionStatus{
+						Name: string(extension.Name), Version: version, Error: message,
+					})
+				}
+				differ = differ || failed
+			}
+			if len(status.Drift) > 0 || len(status.Extensions) > 0 {
+				cluster.Status.Databases = append(cluster.Status.Databases, status)
+			}
+			if differ {
+				differs = append(differs, status.Name)
+			}
+		}
+		setDatabaseDriftCondition(cluster, differs)
+	}
+
+	// Databases that differ from their specification are checked again until
+	// they match. A database can be created before its owner, for example, and
+	// an extension might need a library that is loaded after a restart.
+	// See [databasesRequeue].
+	if err == nil && pgAuditOK && postgisInstallOK && len(differs) == 0 {
 		cluster.Status.DatabaseRevision = revision
 	}
 
 	return err
 }
 
+// setDatabaseDriftCondition reports in cluster the databases that differ from
+// their specification or have extensions that could not be created or updated.
+// The condition is removed when there are none.
+func setDatabaseDriftCondition(cluster *v1beta1.PostgresCluster, databases []string) {
+	if len(databases) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, v1beta1.DatabaseDrift)
+		return
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:   v1beta1.DatabaseDrift,
+		Status: metav1.ConditionTrue,
+		Reason: "DatabasesDiffer",
+		Message: fmt.Sprintf("Databases %q differ from their specification; "+
+			"see status.databases.", databases),
+
+		ObservedGeneration: cluster.GetGeneration(),
+	})
+}
+
+// databasesRequeue returns how long to wait before databases in cluster are
+// compared to their specifications again. Time passing does not cause an event
+// that triggers another reconcile.
+func databasesRequeue(cluster *v1beta1.PostgresCluster, now time.Time) time.Duration {
+	if meta.IsStatusConditionTrue(cluster.Status.Conditions, v1beta1.DatabaseDrift) {
+		return databaseRetryInterval
+	}
+	if cluster.Status.DatabaseRevision == "" {
+		return 0
+	}
+
+	// Arrive a moment after the next check interval begins.
+	return now.Truncate(databaseCheckInterval).Add(databaseCheckInterval).Sub(now) + time.Second
+}
+
 // reconcilePostgresUsers writes the objects necessary to manage users and their
 // passwords in PostgreSQL.
 func (r *Reconciler) reconcilePostgresUsers(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+	root pki.Issuer,
 ) error {
 	r.validatePostgresUsers(cluster)
+	r.validatePostgresRoles(cluster)
 
-	users, secrets, err := r.reconcilePostgresUserSecrets(ctx, cluster)
+	users, secrets, err := r.reconcilePostgresUserSecrets(ctx, cluster, root)
 	if err == nil {
 		err = r.reconcilePostgresUsersInPostgreSQL(ctx, cluster, instances, users, secrets)
 	}
@@ -315,6 +544,7 @@ func (r *Reconciler) validatePostgresUsers(cluster *v1beta1.PostgresCluster) {
 	path := field.NewPath("spec", "users")
 	reComments := regexp.MustCompile(`(?:--|/[*]|[*]/)`)
 	rePassword := regexp.MustCompile(`(?i:PASSWORD)`)
+	reValidUntil := regexp.MustCompile(`(?i:VALID\s+UNTIL)`)
 
 	for i := range cluster.Spec.Users {
 		errs := field.ErrorList{}
@@ -331,6 +561,26 @@ func (r *Reconciler) validatePostgresUsers(cluster *v1beta1.PostgresCluster) {
 					"cannot assign password"))
 		}
 
+		if spec.ClientCertificate && cluster.Spec.CustomTLSSecret != nil {
+			errs = append(errs,
+				field.Invalid(path.Index(i).Child("clientCertificate"), spec.ClientCertificate,
+					"cannot issue client certificates when customTLSSecret is set"))
+		}
+
+		// Rotating passwords requires an alternate role and sets VALID UNTIL.
+		if spec.PasswordRotation != nil && spec.Name != "postgres" {
+			if n := len(alternatePostgresUser(string(spec.Name))); n > 63 {
+				errs = append(errs,
+					field.Invalid(path.Index(i).Child("name"), spec.Name,
+						fmt.Sprintf("should be at most %d chars long to rotate passwords", 63-(n-len(spec.Name)))))
+			}
+			if reValidUntil.MatchString(spec.Options) {
+				errs = append(errs,
+					field.Invalid(path.Index(i).Child("options"), spec.Options,
+						"cannot set VALID UNTIL when passwords rotate"))
+			}
+		}
+
 		if len(errs) > 0 {
 			r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidUser",
 				errs.ToAggregate().Error())
@@ -338,6 +588,56 @@ func (r *Reconciler) validatePostgresUsers(cluster *v1beta1.PostgresCluster) {
 	}
 }
 
+// validatePostgresParameters emits warnings when cluster.Spec.Config.Parameters
+// or any instance set parameters are not valid for cluster.Spec.PostgresVersion.
+// Invalid parameters are not passed to PostgreSQL. Parameters that are not in
+// the catalog are passed along with a warning. It also warns when every
+// instance set delays applying WAL.
+func (r *Reconciler) validatePostgresParameters(cluster *v1beta1.PostgresCluster) {
+	validate := func(path *field.Path, parameters map[string]intstr.IntOrString) {
+		invalid, unrecognized := field.ErrorList{}, field.ErrorList{}
+		for _, name := range sets.List(sets.KeySet(parameters)) {
+			value := parameters[name]
+			err := postgres.ValidateParameter(cluster.Spec.PostgresVersion, name, value)
+
+			if errors.Is(err, postgres.ErrUnrecognizedParameter) {
+				unrecognized = append(unrecognized,
+					field.Invalid(path.Key(name), value.String(), err.Error()))
+			} else if err != nil {
+				invalid = append(invalid,
+					field.Invalid(path.Key(name), value.String(), err.Error()))
+			}
+		}
+		if len(invalid) > 0 {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidParameters",
+				invalid.ToAggregate().Error())
+		}
+		if len(unrecognized) > 0 {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "UnrecognizedParameters",
+				unrecognized.ToAggregate().Error())
+		}
+	}
+
+	validate(field.NewPath("spec", "config", "parameters"),
+		cluster.Spec.Config.Parameters)
+	delayed := 0
+	for i := range cluster.Spec.InstanceSets {
+		validate(field.NewPath("spec", "instances").Index(i).Child("parameters"),
+			cluster.Spec.InstanceSets[i].Parameters)
+
+		if cluster.Spec.InstanceSets[i].RecoveryMinApplyDelay != nil {
+			delayed++
+		}
+	}
+
+	// Patroni does not bootstrap nor promote instances tagged "nofailover".
+	if delayed > 0 && delayed == len(cluster.Spec.InstanceSets) {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidParameters",
+			field.Invalid(field.NewPath("spec", "instances"), delayed,
+				"at least one instance set must not have a recoveryMinApplyDelay").Error())
+	}
+}
+
 // +kubebuilder:rbac:groups="",resources="secrets",verbs={list}
 // +kubebuilder:rbac:groups="",resources="secrets",verbs={create,delete,patch}
 
@@ -346,7 +646,7 @@ func (r *Reconciler) validatePostgresUsers(cluster *v1beta1.PostgresCluster) {
 // It returns the user specifications it acted on (because defaults) and the
 // Secrets it wrote.
 func (r *Reconciler) reconcilePostgresUserSecrets(
-	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	ctx context.Context, cluster *v1beta1.PostgresCluster, root pki.Issuer,
 ) (
 	[]v1beta1.PostgresUserSpec, map[string]*corev1.Secret, error,
 ) {
@@ -460,6 +760,7 @@ func (r *Reconciler) reconcilePostgresUserSecrets(
 	}
 
 	// Reconcile each PostgreSQL user in the cluster spec.
+	now := time.Now()
 	for userName, user := range userSpecs {
 		secret := userSecrets[userName]
 
@@ -469,12 +770,32 @@ func (r *Reconciler) reconcilePostgresUserSecrets(
 			secret = defaultSecret
 		}
 
+		// Generate a new password when the current one is due to rotate.
+		secret, rotated := rotatePostgresUserPassword(user, secret, now)
+
+		// Take the password from another Secret when one is specified. Until
+		// that Secret exists, write neither the user Secret nor the role.
+		if err == nil && user.PasswordSecretRef != nil {
+			secret, err = r.usePostgresUserPassword(ctx, cluster, user, secret)
+			if err == nil && secret == nil {
+				continue
+			}
+		}
+
 		if err == nil {
 			userSecrets[userName], err = r.generatePostgresUserSecret(cluster, user, secret)
 		}
+		if err == nil && user.ClientCertificate && cluster.Spec.CustomTLSSecret == nil {
+			err = postgresUserCertificate(root, user, secret, userSecrets[userName])
+		}
 		if err == nil {
 			err = errors.WithStack(r.apply(ctx, userSecrets[userName]))
 		}
+		if err == nil && rotated {
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "PasswordRotated",
+				"Generated a new password for user %q; the previous password expires at %s",
+				userName, now.Add(passwordGracePeriod(user.PasswordRotation)).UTC().Format(time.RFC3339))
+		}
 	}
 
 	return specUsers, userSecrets, err
@@ -518,13 +839,64 @@ func (r *Reconciler) reconcilePostgresUsersInPostgreSQL(
 
 	// Calculate a hash of the SQL that should be executed in PostgreSQL.
 
-	verifiers := make(map[string]string, len(userSecrets))
-	for userName := range userSecrets {
-		verifiers[userName] = string(userSecrets[userName].Data["verifier"])
+	// Users that take their password from another Secret are not written
+	// until that Secret exists. They are still specified, so they are not pruned.
+	allUsers := specUsers
+	specified := sets.New[string]()
+	writable := make([]v1beta1.PostgresUserSpec, 0, len(specUsers))
+	for i := range specUsers {
+		specified.Insert(string(specUsers[i].Name))
+		if specUsers[i].PasswordSecretRef == nil || userSecrets[string(specUsers[i].Name)] != nil {
+			writable = append(writable, specUsers[i])
+		}
 	}
+	specUsers = writable
 
+	// Users that rotate passwords may log in with more than one role.
+	roles, verifiers, alternates := postgresUserRoles(specUsers, userSecrets)
+
+	// Find users that were removed from the spec since they were installed.
+	policy := v1beta1.PostgresUserPruningRetain
+	if cluster.Spec.UserPruning != nil && cluster.Spec.UserPruning.Users != "" {
+		policy = cluster.Spec.UserPruning.Users
+	}
+	for _, role := range roles {
+		specified.Insert(string(role.Name))
+	}
+	// Users that were retained or disabled are pruned again when the policy
+	// changes, but "Retain" leaves them unchanged.
+	var removed []string
+	for _, user := range cluster.Status.Users {
+		if !specified.Has(user.Name) && user.Removed != v1beta1.PostgresUserDropped &&
+			user.Removed != policyOutcome(policy) &&
+			(user.Removed == "" || policy != v1beta1.PostgresUserPruningRetain) {
+			removed = append(removed, user.Name)
+		}
+	}
+
+	// Find memberships and privileges that were removed from the spec since
+	// they were granted.
+	groups := postgresGroupRoles(cluster, allUsers)
+	granted := postgresRoleGrants(groups, specUsers)
+	memberships, grants := flattenRoleGrants(granted)
+	revokedMemberships, revokedGrants := revokedRoleGrants(cluster.Status.Grants, granted)
+
+	var missing []string
 	write := func(ctx context.Context, exec postgres.Executor) error {
-		return postgres.WriteUsersInPostgreSQL(ctx, cluster, exec, specUsers, verifiers)
+		err := postgres.WriteUsersInPostgreSQL(ctx, cluster, exec, roles, verifiers)
+		if err == nil {
+			err = postgres.WriteAlternateUsersInPostgreSQL(ctx, exec, alternates)
+		}
+		if err == nil {
+			err = postgres.WriteRolesInPostgreSQL(ctx, exec, groups, memberships, revokedMemberships)
+		}
+		if err == nil {
+			missing, err = postgres.WriteGrantsInPostgreSQL(ctx, exec, grants, revokedGrants)
+		}
+		if err == nil && policy != v1beta1.PostgresUserPruningRetain {
+			err = postgres.PruneUsersInPostgreSQL(ctx, exec, policy, removed)
+		}
+		return err
 	}
 
 	revision, err := safeHash32(func(hasher io.Writer) error {
@@ -542,6 +914,8 @@ func (r *Reconciler) reconcilePostgresUsersInPostgreSQL(
 
 	if err == nil && revision == cluster.Status.UsersRevision {
 		// The necessary SQL has already been applied; there's nothing more to do.
+		setUsersStatus(cluster, specified, removed, policy)
+		setPasswordRotationStatus(cluster, specUsers, userSecrets)
 
 		// TODO(cbandy): Give the user a way to trigger execution regardless.
 		// The value of an annotation could influence the hash, for example.
@@ -556,12 +930,88 @@ func (r *Reconciler) reconcilePostgresUsersInPostgreSQL(
 		err = errors.WithStack(write(logging.NewContext(ctx, log), podExecutor))
 	}
 	if err == nil {
+		setUsersStatus(cluster, specified, removed, policy)
+		setPasswordRotationStatus(cluster, specUsers, userSecrets)
+		cluster.Status.Grants = granted
+	}
+
+	// Keep granting privileges until their schemas exist.
+	if err == nil {
+		setPrivilegesPendingCondition(cluster, missing)
+	}
+	if err == nil && len(missing) == 0 {
 		cluster.Status.UsersRevision = revision
 	}
 
 	return err
 }
 
+// setPrivilegesPendingCondition reports in cluster the schemas that do not
+// exist yet, as "database.schema", for privileges in spec.roles. The condition
+// is removed when there are none.
+func setPrivilegesPendingCondition(cluster *v1beta1.PostgresCluster, schemas []string) {
+	if len(schemas) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, v1beta1.PrivilegesPending)
+		return
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    v1beta1.PrivilegesPending,
+		Status:  metav1.ConditionTrue,
+		Reason:  "SchemasMissing",
+		Message: fmt.Sprintf("Privileges are granted when schemas %q exist.", schemas),
+
+		ObservedGeneration: cluster.GetGeneration(),
+	})
+}
+
+// privilegesRequeue returns how long to wait before privileges in cluster are
+// granted again. Creating a schema does not cause an event that triggers
+// another reconcile.
+func privilegesRequeue(cluster *v1beta1.PostgresCluster) time.Duration {
+	if meta.IsStatusConditionTrue(cluster.Status.Conditions, v1beta1.PrivilegesPending) {
+		return time.Minute
+	}
+	return 0
+}
+
+// policyOutcome returns what happens to users that are pruned by policy.
+func policyOutcome(policy string) string {
+	switch policy {
+	case v1beta1.PostgresUserPruningDisable:
+		return v1beta1.PostgresUserDisabled
+	case v1beta1.PostgresUserPruningDrop:
+		return v1beta1.PostgresUserDropped
+	}
+	return v1beta1.PostgresUserRetained
+}
+
+// setUsersStatus records in cluster the users that are specified and what
+// happened to those that were removed. Removed users stay in status so that
+// a later policy can prune them.
+func setUsersStatus(
+	cluster *v1beta1.PostgresCluster, specified sets.Set[string], removed []string, policy string,
+) {
+	pruned := sets.New(removed...)
+	users := make([]v1beta1.PostgresUserStatus, 0, len(cluster.Status.Users)+specified.Len())
+
+	for _, user := range cluster.Status.Users {
+		switch {
+		case specified.Has(user.Name):
+		case pruned.Has(user.Name):
+			users = append(users, v1beta1.PostgresUserStatus{Name: user.Name, Removed: policyOutcome(policy)})
+		case user.Removed != "":
+			users = append(users, user)
+		}
+	}
+	for _, name := range sets.List(specified) {
+		users = append(users, v1beta1.PostgresUserStatus{Name: name})
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i].Name < users[j].Name })
+	cluster.Status.Users = users
+}
+
 // +kubebuilder:rbac:groups="",resources="persistentvolumeclaims",verbs={create,patch}
 
 // reconcilePostgresDataVolume writes the PersistentVolumeClaim for instance's
@@ -610,6 +1060,25 @@ func (r *Reconciler) reconcilePostgresDataVolume(
 
 	pvc.Spec = instanceSpec.DataVolumeClaimSpec
 
+	// The storage class of an existing PVC cannot change. Keep it so the rest
+	// of the spec applies; the instance is replaced by migrateInstanceStorage.
+	// Likewise, a volume cannot shrink. Keep the request of a larger volume
+	// while it waits to be replaced by observeVolumeShrink.
+	for i := range clusterVolumes {
+		if clusterVolumes[i].Name == existingPVCName &&
+			clusterVolumes[i].Spec.StorageClassName != nil {
+			pvc.Spec.StorageClassName = clusterVolumes[i].Spec.StorageClassName
+		}
+		if clusterVolumes[i].Name == existingPVCName &&
+			instanceSpec.DataVolumeShrink != "" &&
+			instanceSpec.DataVolumeShrink != v1beta1.VolumeShrinkDisabled &&
+			oversizedVolume(instanceSpec, &clusterVolumes[i]) {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{
+				corev1.ResourceStorage: *clusterVolumes[i].Spec.Resources.Requests.Storage(),
+			}
+		}
+	}
+
 	// If a source cluster was provided and VolumeSnapshots are turned on in the source cluster and
 	// there is a VolumeSnapshot available for the source cluster that is ReadyToUse, use it as the
 	// source for the PVC. If there is an error when retrieving VolumeSnapshots, or no ReadyToUse
@@ -654,12 +1123,44 @@ func (r *Reconciler) reconcilePostgresDataVolume(
 // and limit and sets the appropriate current value.
 func (r *Reconciler) setVolumeSize(ctx context.Context, cluster *v1beta1.PostgresCluster,
 	pvc *corev1.PersistentVolumeClaim, instanceSpecName string) {
+	desired := instanceSetStatus(cluster, instanceSpecName).DesiredPGDataVolume
+
+	// When shrinking, each volume grows only by its own suggested size so that
+	// replacement volumes start at the size requested in the spec.
+	for _, set := range cluster.Spec.InstanceSets {
+		if set.Name == instanceSpecName && set.DataVolumeShrink == v1beta1.VolumeShrinkEnabled {
+			instance := pvc.Labels[naming.LabelInstance]
+			desired = map[string]string{instance: desired[instance]}
+		}
+	}
+
+	r.setVolumeRequest(ctx, cluster, pvc, "pgData", cluster.Name+"/"+instanceSpecName, desired)
+}
+
+// instanceSetStatus returns the status of the instance set named name, or
+// an empty status when there is none.
+func instanceSetStatus(
+	cluster *v1beta1.PostgresCluster, name string,
+) v1beta1.PostgresInstanceSetStatus {
+	for _, status := range cluster.Status.InstanceSets {
+		if status.Name == name {
+			return status
+		}
+	}
+	return v1beta1.PostgresInstanceSetStatus{}
+}
+
+// setVolumeRequest compares the potential sizes from the spec of pvc, the
+// desired sizes in status, and the limit of pvc and sets the appropriate
+// current value. Events name the volume and its owner.
+func (r *Reconciler) setVolumeRequest(ctx context.Context, cluster *v1beta1.PostgresCluster,
+	pvc *corev1.PersistentVolumeClaim, volume, owner string, desired map[string]string) {
 	log := logging.FromContext(ctx)
 
-	// Store the limit for this instance set. This value will not change below.
+	// Store the limit for this volume. This value will not change below.
 	volumeLimitFromSpec := pvc.Spec.Resources.Limits.Storage()
 
-	// Capture the largest pgData volume size currently defined for a given instance set.
+	// Capture the largest volume size currently defined for this volume.
 	// This value will capture our desired update.
 	volumeRequestSize := pvc.Spec.Resources.Requests.Storage()
 
@@ -668,27 +1169,23 @@ func (r *Reconciler) setVolumeSize(ctx context.Context, cluster *v1beta1.Postgre
 	if !volumeLimitFromSpec.IsZero() &&
 		volumeRequestSize.Value() > volumeLimitFromSpec.Value() {
 		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "VolumeRequestOverLimit",
-			"pgData volume request (%v) for %s/%s is greater than set limit (%v). Limit value will be used.",
-			volumeRequestSize, cluster.Name, instanceSpecName, volumeLimitFromSpec)
+			"%s volume request (%v) for %s is greater than set limit (%v). Limit value will be used.",
+			volume, volumeRequestSize, owner, volumeLimitFromSpec)
 
 		pvc.Spec.Resources.Requests = corev1.ResourceList{
 			corev1.ResourceStorage: *resource.NewQuantity(volumeLimitFromSpec.Value(), resource.BinarySI),
 		}
 		// Otherwise, if the limit is not set or the feature gate is not enabled, do not autogrow.
 	} else if !volumeLimitFromSpec.IsZero() && feature.Enabled(ctx, feature.AutoGrowVolumes) {
-		for i := range cluster.Status.InstanceSets {
-			if instanceSpecName == cluster.Status.InstanceSets[i].Name {
-				for _, dpv := range cluster.Status.InstanceSets[i].DesiredPGDataVolume {
-					if dpv != "" {
-						desiredRequest, err := resource.ParseQuantity(dpv)
-						if err == nil {
-							if desiredRequest.Value() > volumeRequestSize.Value() {
-								volumeRequestSize = &desiredRequest
-							}
-						} else {
-							log.Error(err, "Unable to parse volume request: "+dpv)
-						}
+		for _, dpv := range desired {
+			if dpv != "" {
+				desiredRequest, err := resource.ParseQuantity(dpv)
+				if err == nil {
+					if desiredRequest.Value() > volumeRequestSize.Value() {
+						volumeRequestSize = &desiredRequest
 					}
+				} else {
+					log.Error(err, "Unable to parse volume request: "+dpv)
 				}
 			}
 		}
@@ -700,15 +1197,14 @@ func (r *Reconciler) setVolumeSize(ctx context.Context, cluster *v1beta1.Postgre
 		if volumeRequestSize.Value() >= volumeLimitFromSpec.Value() {
 
 			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "VolumeLimitReached",
-				"pgData volume(s) for %s/%s are at size limit (%v).", cluster.Name,
-				instanceSpecName, volumeLimitFromSpec)
+				"%s volume(s) for %s are at size limit (%v).", volume, owner, volumeLimitFromSpec)
 
 			// If the volume size request is greater than the limit, issue an
 			// additional event warning.
 			if volumeRequestSize.Value() > volumeLimitFromSpec.Value() {
 				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "DesiredVolumeAboveLimit",
-					"The desired size (%v) for the %s/%s pgData volume(s) is greater than the size limit (%v).",
-					volumeRequestSize, cluster.Name, instanceSpecName, volumeLimitFromSpec)
+					"The desired size (%v) for the %s %s volume(s) is greater than the size limit (%v).",
+					volumeRequestSize, owner, volume, volumeLimitFromSpec)
 			}
 
 			volumeRequestSize = volumeLimitFromSpec
@@ -733,10 +1229,6 @@ func (r *Reconciler) reconcileTablespaceVolumes(
 		return
 	}
 
-	if instanceSpec.TablespaceVolumes == nil {
-		return
-	}
-
 	for _, vol := range instanceSpec.TablespaceVolumes {
 		labelMap := map[string]string{
 			naming.LabelCluster:     cluster.Name,
@@ -776,6 +1268,13 @@ func (r *Reconciler) reconcileTablespaceVolumes(
 
 		pvc.Spec = vol.DataVolumeClaimSpec
 
+		r.setVolumeRequest(ctx, cluster, pvc, vol.Name+" tablespace", cluster.Name+"/"+instanceSpec.Name,
+			instanceSetStatus(cluster, instanceSpec.Name).DesiredTablespaceVolumes[vol.Name])
+
+		// Clear any set limit before applying PVC. This is needed to allow the limit
+		// value to change later.
+		pvc.Spec.Resources.Limits = nil
+
 		if err == nil {
 			err = r.handlePersistentVolumeClaimError(cluster,
 				errors.WithStack(r.apply(ctx, pvc)))
@@ -788,6 +1287,25 @@ func (r *Reconciler) reconcileTablespaceVolumes(
 		tablespaceVolumes = append(tablespaceVolumes, pvc)
 	}
 
+	// Keep the volumes of tablespaces that still exist in PostgreSQL after
+	// they are removed from the spec. Dropping them happens on the primary
+	// after this, and PostgreSQL cannot start with a tablespace missing.
+	specified := sets.New[string]()
+	for _, vol := range instanceSpec.TablespaceVolumes {
+		specified.Insert(vol.Name)
+	}
+	for _, status := range cluster.Status.Tablespaces {
+		if specified.Has(status.Name) {
+			continue
+		}
+		for i := range clusterVolumes {
+			if labels := clusterVolumes[i].Labels; labels[naming.LabelInstance] == instance.Name &&
+				labels[naming.LabelRole] == "tablespace" && labels[naming.LabelData] == status.Name {
+				tablespaceVolumes = append(tablespaceVolumes, clusterVolumes[i].DeepCopy())
+			}
+		}
+	}
+
 	return
 }
 
@@ -886,6 +1404,13 @@ func (r *Reconciler) reconcilePostgresWALVolume(
 
 	pvc.Spec = *instanceSpec.WALVolumeClaimSpec
 
+	r.setVolumeRequest(ctx, cluster, pvc, "pgWAL", cluster.Name+"/"+instanceSpec.Name,
+		instanceSetStatus(cluster, instanceSpec.Name).DesiredPGWALVolume)
+
+	// Clear any set limit before applying PVC. This is needed to allow the limit
+	// value to change later.
+	pvc.Spec.Resources.Limits = nil
+
 	if err == nil {
 		err = r.handlePersistentVolumeClaimError(cluster,
 			errors.WithStack(r.apply(ctx, pvc)))
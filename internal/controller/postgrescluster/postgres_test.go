@@ -8,7 +8,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr/funcr"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -18,8 +20,10 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
@@ -281,6 +285,40 @@ volumeMode: Filesystem
 		`))
 	})
 
+	t.Run("DataVolumeShrink", func(t *testing.T) {
+		cluster := testCluster()
+		ns := setupNamespace(t, tClient)
+		cluster.Namespace = ns.Name
+
+		assert.NilError(t, tClient.Create(ctx, cluster))
+		t.Cleanup(func() { assert.Check(t, tClient.Delete(ctx, cluster)) })
+
+		spec := &v1beta1.PostgresInstanceSetSpec{}
+		assert.NilError(t, yaml.Unmarshal([]byte(`{
+			name: "some-instance",
+			dataVolumeShrink: Enabled,
+			dataVolumeClaimSpec: {
+				accessModes: [ReadWriteOnce],
+				resources: { requests: { storage: 2Gi } },
+			},
+		}`), spec))
+		instance := &appsv1.StatefulSet{ObjectMeta: naming.GenerateInstance(cluster, spec)}
+
+		existing, err := reconciler.reconcilePostgresDataVolume(ctx, cluster, spec, instance, nil, nil)
+		assert.NilError(t, err)
+
+		// The existing volume keeps its request while it waits to be replaced.
+		spec.DataVolumeClaimSpec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("1Gi")
+
+		pvc, err := reconciler.reconcilePostgresDataVolume(ctx, cluster, spec, instance,
+			[]corev1.PersistentVolumeClaim{*existing}, nil)
+		assert.NilError(t, err)
+		assert.Equal(t, pvc.Name, existing.Name)
+		assert.Equal(t, pvc.Spec.Resources.Requests.Storage().String(), "2Gi")
+		assert.Equal(t, spec.DataVolumeClaimSpec.Resources.Requests.Storage().String(), "1Gi",
+			"expected no change to the spec")
+	})
+
 	t.Run("DataVolumeSourceClusterWithGoodSnapshot", func(t *testing.T) {
 		cluster := testCluster()
 		ns := setupNamespace(t, tClient)
@@ -911,6 +949,113 @@ resources:
 			assert.Assert(t, found1 && found2)
 		})
 
+		t.Run("Shrink", func(t *testing.T) {
+			recorder := events.NewRecorder(t, runtime.Scheme)
+			reconciler := &Reconciler{Recorder: recorder}
+			ctx, logs := setupLogCapture(ctx)
+
+			shrinking := cluster.DeepCopy()
+			shrinking.Spec.InstanceSets[0].DataVolumeShrink = "Enabled"
+			shrinking.Status = v1beta1.PostgresClusterStatus{
+				InstanceSets: []v1beta1.PostgresInstanceSetStatus{{
+					Name: "some-instance",
+					DesiredPGDataVolume: map[string]string{
+						"elephant-some-instance-abcd-0": "3Gi",
+					},
+				}},
+			}
+
+			pvc := &corev1.PersistentVolumeClaim{ObjectMeta: naming.InstancePostgresDataVolume(instance)}
+			pvc.Labels = map[string]string{naming.LabelInstance: instance.Name}
+			spec := instanceSetSpec("1Gi", "5Gi")
+			pvc.Spec = spec.DataVolumeClaimSpec
+
+			// The suggestion for another instance does not apply.
+			reconciler.setVolumeSize(ctx, shrinking, pvc, spec.Name)
+
+			assert.Assert(t, cmp.MarshalMatches(pvc.Spec, `
+accessModes:
+- ReadWriteOnce
+resources:
+  limits:
+    storage: 5Gi
+  requests:
+    storage: 1Gi
+`))
+			assert.Equal(t, len(*logs), 0)
+			assert.Equal(t, len(recorder.Events), 0)
+
+			// The suggestion for this instance does.
+			shrinking.Status.InstanceSets[0].DesiredPGDataVolume[instance.Name] = "2Gi"
+			reconciler.setVolumeSize(ctx, shrinking, pvc, spec.Name)
+
+			assert.Equal(t, pvc.Spec.Resources.Requests.Storage().String(), "2Gi")
+		})
+
+	})
+}
+
+func TestSetVolumeRequest(t *testing.T) {
+	gate := feature.NewGate()
+	assert.NilError(t, gate.SetFromMap(map[string]bool{
+		feature.AutoGrowVolumes: true,
+	}))
+	ctx := feature.NewContext(context.Background(), gate)
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Name = "elephant"
+
+	pvc := func(request, limit string) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvc.Spec.Resources = corev1.VolumeResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(request)},
+			Limits:   corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(limit)},
+		}
+		return pvc
+	}
+
+	t.Run("Grow", func(t *testing.T) {
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+
+		volume := pvc("1Gi", "5Gi")
+		reconciler.setVolumeRequest(ctx, cluster, volume, "pgWAL", "elephant/some-instance",
+			map[string]string{"a": "2Gi", "b": "3Gi", "c": ""})
+
+		assert.Equal(t, volume.Spec.Resources.Requests.Storage().String(), "3Gi")
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+
+		volume := pvc("1Gi", "5Gi")
+		reconciler.setVolumeRequest(ctx, cluster, volume, "pgBackRest repo", "elephant/repo1",
+			map[string]string{"repo1": "6Gi"})
+
+		assert.Equal(t, volume.Spec.Resources.Requests.Storage().String(), "5Gi")
+		assert.Equal(t, len(recorder.Events), 2)
+		assert.Equal(t, recorder.Events[0].Reason, "VolumeLimitReached")
+		assert.Equal(t, recorder.Events[0].Note,
+			"pgBackRest repo volume(s) for elephant/repo1 are at size limit (5Gi).")
+		assert.Equal(t, recorder.Events[1].Reason, "DesiredVolumeAboveLimit")
+		assert.Equal(t, recorder.Events[1].Note,
+			"The desired size (6Gi) for the elephant/repo1 pgBackRest repo volume(s) is greater than the size limit (5Gi).")
+	})
+
+	t.Run("RequestAboveLimit", func(t *testing.T) {
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+
+		volume := pvc("4Gi", "3Gi")
+		reconciler.setVolumeRequest(ctx, cluster, volume, "castle tablespace", "elephant/some-instance", nil)
+
+		assert.Equal(t, volume.Spec.Resources.Requests.Storage().String(), "3Gi")
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "VolumeRequestOverLimit")
+		assert.Equal(t, recorder.Events[0].Note,
+			"castle tablespace volume request (4Gi) for elephant/some-instance is greater than set limit (3Gi). Limit value will be used.")
 	})
 }
 
@@ -1151,6 +1296,590 @@ func TestReconcileDatabaseInitSQLConfigMap(t *testing.T) {
 	})
 }
 
+func TestReconcilePostgresDatabasesDrift(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Name = "hippo"
+	cluster.Spec.Users = []v1beta1.PostgresUserSpec{
+		{Name: "alice", Databases: []v1beta1.PostgresIdentifier{"app", "other"}},
+	}
+	cluster.Spec.Databases = []v1beta1.PostgresDatabaseSpec{{
+		Name:  "app",
+		Owner: initialize.Pointer(v1beta1.PostgresIdentifier("alice")),
+		Extensions: []v1beta1.PostgresExtensionSpec{
+			{Name: "pg_trgm"}, {Name: "missing"},
+		},
+	}}
+
+	observed := &observedInstances{forCluster: []*Instance{{
+		Pods: []*corev1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pod",
+				Annotations: map[string]string{"status": `{"role":"master"}`},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  naming.ContainerDatabase,
+					State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+				}},
+			},
+		}},
+		Runner: &appsv1.StatefulSet{},
+	}}}
+
+	var calls int
+	drift := `{"app":["owner is postgres, not alice"]}`
+	extensions := `{"installed":{"pg_trgm":"1.6"},"failed":{"missing":"not available"}}`
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &Reconciler{
+		Recorder: recorder,
+		PodExec: func(
+			ctx context.Context, namespace, pod, container string,
+			stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+
+			if strings.Contains(string(b), "CREATE DATABASE") {
+				calls++
+				assert.Assert(t, cmp.Contains(string(b),
+					`{"database":"app","owner":"alice"}`+"\n"+`{"database":"other"}`+"\n"),
+					"expected spec.databases first")
+				_, _ = stdout.Write([]byte(drift + "\n"))
+			}
+			if strings.Contains(string(b), "CREATE EXTENSION %I") {
+				assert.Assert(t, cmp.Contains(strings.Join(command, " "),
+					`--set=extensions=[{"name":"pg_trgm"},{"name":"missing"`))
+				_, _ = stdout.Write([]byte(extensions + "\n"))
+			}
+			return nil
+		},
+	}
+
+	now := time.Date(2020, time.April, 1, 10, 15, 0, 0, time.UTC)
+	assert.NilError(t, reconciler.reconcilePostgresDatabases(ctx, cluster, observed, now))
+	assert.Equal(t, calls, 1)
+	assert.DeepEqual(t, cluster.Status.Databases, []v1beta1.PostgresDatabaseStatus{{
+		Name:  "app",
+		Drift: []string{"owner is postgres, not alice"},
+		Extensions: []v1beta1.PostgresExtensionStatus{
+			{Name: "pg_trgm", Version: "1.6"},
+			{Name: "missing", Error: "not available"},
+		},
+	}})
+	assert.Equal(t, cluster.Status.DatabaseRevision, "",
+		"expected no revision while databases differ")
+
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.DatabaseDrift)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Status, metav1.ConditionTrue)
+	assert.Assert(t, cmp.Contains(condition.Message, `["app"]`))
+	assert.Equal(t, databasesRequeue(cluster, now), time.Minute)
+
+	assert.Equal(t, len(recorder.Events), 1)
+	assert.Equal(t, recorder.Events[0].Reason, "ExtensionFailed")
+	assert.Equal(t, recorder.Events[0].Note,
+		`Unable to create or update extension "missing" in database "app": not available`)
+
+	// Databases that differ are checked again without another event.
+	assert.NilError(t, reconciler.reconcilePostgresDatabases(ctx, cluster, observed, now))
+	assert.Equal(t, calls, 2)
+	assert.Equal(t, len(recorder.Events), 1)
+
+	// The owner exists now, and the extension becomes available.
+	drift = `{}`
+	extensions = `{"installed":{"pg_trgm":"1.6","missing":"1.0"},"failed":{}}`
+	assert.NilError(t, reconciler.reconcilePostgresDatabases(ctx, cluster, observed, now))
+	assert.Equal(t, calls, 3)
+	assert.DeepEqual(t, cluster.Status.Databases, []v1beta1.PostgresDatabaseStatus{{
+		Name: "app",
+		Extensions: []v1beta1.PostgresExtensionStatus{
+			{Name: "pg_trgm", Version: "1.6"},
+			{Name: "missing", Version: "1.0"},
+		},
+	}})
+	assert.Assert(t, cluster.Status.DatabaseRevision != "")
+	assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.DatabaseDrift) == nil)
+	assert.Equal(t, databasesRequeue(cluster, now), 45*time.Minute+time.Second)
+
+	assert.NilError(t, reconciler.reconcilePostgresDatabases(ctx, cluster, observed, now.Add(time.Minute)))
+	assert.Equal(t, calls, 3, "expected no changes")
+
+	// Databases are compared again after some time.
+	assert.NilError(t, reconciler.reconcilePostgresDatabases(ctx, cluster, observed, now.Add(time.Hour)))
+	assert.Equal(t, calls, 4)
+}
+
+func TestReconcilePostgresTablespaces(t *testing.T) {
+	ctx := context.Background()
+	gate := feature.NewGate()
+	assert.NilError(t, gate.SetFromMap(map[string]bool{
+		feature.TablespaceVolumes: true,
+	}))
+	ctx = feature.NewContext(ctx, gate)
+
+	observed := &observedInstances{forCluster: []*Instance{{
+		Pods: []*corev1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pod",
+				Annotations: map[string]string{"status": `{"role":"master"}`},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  naming.ContainerDatabase,
+					State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+				}},
+			},
+		}},
+		Runner: &appsv1.StatefulSet{},
+	}}}
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Name = "hippo"
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Name: "one", TablespaceVolumes: []v1beta1.TablespaceVolume{
+			{Name: "fast", Owner: initialize.Pointer(v1beta1.PostgresIdentifier("alice"))},
+			{Name: "lonely"},
+		}},
+		{Name: "two", TablespaceVolumes: []v1beta1.TablespaceVolume{
+			{Name: "fast"},
+		}},
+	}
+	cluster.Status.Tablespaces = []v1beta1.PostgresTablespaceStatus{
+		{Name: "fast"}, {Name: "old"}, {Name: "gone"},
+	}
+
+	var input string
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &Reconciler{
+		Recorder: recorder,
+		PodExec: func(
+			ctx context.Context, namespace, pod, container string,
+			stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			input = string(b)
+
+			_, _ = stdout.Write([]byte(`{"fast":{"size":1024,"databases":["app"]},"old":{"size":2048,"databases":["app","other"]}}` + "\n"))
+			return nil
+		},
+	}
+
+	assert.NilError(t, reconciler.reconcilePostgresTablespaces(ctx, cluster, observed))
+
+	// The tablespace of only one set is not created; removed ones are dropped.
+	assert.Assert(t, cmp.Contains(input, "\n"+
+		`{"location":"/tablespaces/fast/data","owner":"alice","remove":false,"tablespace":"fast"}`+"\n"+
+		`{"remove":true,"tablespace":"old"}`+"\n"+
+		`{"remove":true,"tablespace":"gone"}`+"\n"))
+
+	assert.DeepEqual(t, cluster.Status.Tablespaces, []v1beta1.PostgresTablespaceStatus{
+		{
+			Name: "fast", Size: resource.NewQuantity(1024, resource.BinarySI),
+			Databases: []string{"app"},
+		},
+		{
+			Name: "old", Size: resource.NewQuantity(2048, resource.BinarySI),
+			Databases: []string{"app", "other"}, Retained: true,
+		},
+	})
+
+	assert.Equal(t, len(recorder.Events), 2)
+	assert.Equal(t, recorder.Events[0].Reason, "TablespaceNotCreated")
+	assert.Equal(t, recorder.Events[0].Note,
+		"Tablespace lonely is not in every instance set; it must be for PostgreSQL to create it")
+	assert.Equal(t, recorder.Events[1].Reason, "TablespaceInUse")
+	assert.Equal(t, recorder.Events[1].Note,
+		"Tablespace old was removed from the spec but has objects in databases [app other]; keeping its volumes")
+
+	t.Run("Disabled", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		assert.NilError(t, reconciler.reconcilePostgresTablespaces(context.Background(), cluster, observed))
+		assert.Equal(t, len(cluster.Status.Tablespaces), 2, "expected no change")
+	})
+
+	t.Run("Nothing", func(t *testing.T) {
+		cluster := v1beta1.NewPostgresCluster()
+		cluster.Status.Tablespaces = nil
+		assert.NilError(t, reconciler.reconcilePostgresTablespaces(ctx, cluster, observed))
+		assert.Assert(t, cluster.Status.Tablespaces == nil)
+	})
+}
+
+func TestReconcileTablespaceVolumesRetained(t *testing.T) {
+	ctx := context.Background()
+	gate := feature.NewGate()
+	assert.NilError(t, gate.SetFromMap(map[string]bool{
+		feature.TablespaceVolumes: true,
+	}))
+	ctx = feature.NewContext(ctx, gate)
+
+	volume := func(instance, tablespace string) corev1.PersistentVolumeClaim {
+		pvc := corev1.PersistentVolumeClaim{}
+		pvc.Name = instance + "-" + tablespace
+		pvc.Labels = map[string]string{
+			naming.LabelInstance: instance,
+			naming.LabelRole:     "tablespace",
+			naming.LabelData:     tablespace,
+		}
+		return pvc
+	}
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Status.Tablespaces = []v1beta1.PostgresTablespaceStatus{
+		{Name: "old", Retained: true},
+	}
+	instance := &appsv1.StatefulSet{}
+	instance.Name = "some-instance"
+
+	reconciler := &Reconciler{}
+	volumes, err := reconciler.reconcileTablespaceVolumes(ctx, cluster,
+		&v1beta1.PostgresInstanceSetSpec{}, instance,
+		[]corev1.PersistentVolumeClaim{
+			volume("some-instance", "old"),
+			volume("some-instance", "other"),
+			volume("other-instance", "old"),
+		})
+	assert.NilError(t, err)
+	assert.Equal(t, len(volumes), 1)
+	assert.Equal(t, volumes[0].Name, "some-instance-old")
+}
+
+func TestReconcilePostgresUsersPruning(t *testing.T) {
+	ctx := context.Background()
+
+	observed := &observedInstances{forCluster: []*Instance{{
+		Pods: []*corev1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pod",
+				Annotations: map[string]string{"status": `{"role":"master"}`},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  naming.ContainerDatabase,
+					State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+				}},
+			},
+		}},
+		Runner: &appsv1.StatefulSet{},
+	}}}
+
+	var pruned []string
+	reconciler := &Reconciler{
+		PodExec: func(
+			ctx context.Context, namespace, pod, container string,
+			stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+
+			for _, statement := range []string{"NOLOGIN", "DROP ROLE"} {
+				if strings.Contains(string(b), statement) {
+					pruned = append(pruned, statement+" "+strings.Join(command[len(command)-3:], " "))
+				}
+			}
+			return nil
+		},
+	}
+
+	cluster := v1beta1.NewPostgresCluster()
+	users := func(names ...string) []v1beta1.PostgresUserSpec {
+		var specs []v1beta1.PostgresUserSpec
+		for _, name := range names {
+			specs = append(specs, v1beta1.PostgresUserSpec{Name: v1beta1.PostgresIdentifier(name)})
+		}
+		return specs
+	}
+
+	assert.NilError(t, reconciler.reconcilePostgresUsersInPostgreSQL(ctx, cluster, observed,
+		users("alice", "bob", "carol"), nil))
+	assert.DeepEqual(t, cluster.Status.Users, []v1beta1.PostgresUserStatus{
+		{Name: "alice"}, {Name: "bob"}, {Name: "carol"},
+	})
+
+	// Retained users are tracked so that a later policy applies to them.
+	assert.NilError(t, reconciler.reconcilePostgresUsersInPostgreSQL(ctx, cluster, observed,
+		users("bob", "carol"), nil))
+	assert.Assert(t, pruned == nil)
+	assert.DeepEqual(t, cluster.Status.Users, []v1beta1.PostgresUserStatus{
+		{Name: "alice", Removed: "Retained"}, {Name: "bob"}, {Name: "carol"},
+	})
+
+	assert.NilError(t, reconciler.reconcilePostgresUsersInPostgreSQL(ctx, cluster, observed,
+		users("bob", "carol"), nil))
+	assert.Assert(t, pruned == nil)
+	assert.DeepEqual(t, cluster.Status.Users, []v1beta1.PostgresUserStatus{
+		{Name: "alice", Removed: "Retained"}, {Name: "bob"}, {Name: "carol"},
+	})
+
+	cluster.Spec.UserPruning = &v1beta1.PostgresUserPruningSpec{
+		Users: v1beta1.PostgresUserPruningDisable,
+	}
+	assert.NilError(t, reconciler.reconcilePostgresUsersInPostgreSQL(ctx, cluster, observed,
+		users("carol"), nil))
+	assert.DeepEqual(t, pruned, []string{"NOLOGIN --set=ON_ERROR_STOP=on --set=QUIET=on --set=users=[\"alice\",\"bob\"]"})
+	assert.DeepEqual(t, cluster.Status.Users, []v1beta1.PostgresUserStatus{
+		{Name: "alice", Removed: "Disabled"}, {Name: "bob", Removed: "Disabled"}, {Name: "carol"},
+	})
+
+	// Returning to "Retain" leaves disabled users unchanged.
+	pruned = nil
+	cluster.Spec.UserPruning.Users = v1beta1.PostgresUserPruningRetain
+	assert.NilError(t, reconciler.reconcilePostgresUsersInPostgreSQL(ctx, cluster, observed,
+		users("carol"), nil))
+	assert.Assert(t, pruned == nil)
+	assert.DeepEqual(t, cluster.Status.Users, []v1beta1.PostgresUserStatus{
+		{Name: "alice", Removed: "Disabled"}, {Name: "bob", Removed: "Disabled"}, {Name: "carol"},
+	})
+
+	cluster.Spec.UserPruning.Users = v1beta1.PostgresUserPruningDrop
+	assert.NilError(t, reconciler.reconcilePostgresUsersInPostgreSQL(ctx, cluster, observed,
+		users("carol"), nil))
+	assert.Equal(t, len(pruned), 1)
+	assert.Assert(t, cmp.Contains(pruned[0], "DROP ROLE"))
+	assert.DeepEqual(t, cluster.Status.Users, []v1beta1.PostgresUserStatus{
+		{Name: "alice", Removed: "Dropped"}, {Name: "bob", Removed: "Dropped"}, {Name: "carol"},
+	})
+
+	// A user that returns to the spec is tracked again.
+	pruned = nil
+	assert.NilError(t, reconciler.reconcilePostgresUsersInPostgreSQL(ctx, cluster, observed,
+		users("bob", "carol"), nil))
+	assert.Assert(t, pruned == nil)
+	assert.DeepEqual(t, cluster.Status.Users, []v1beta1.PostgresUserStatus{
+		{Name: "alice", Removed: "Dropped"}, {Name: "bob"}, {Name: "carol"},
+	})
+}
+
+func TestReconcilePostgresUsersPasswordSecretMissing(t *testing.T) {
+	ctx := context.Background()
+
+	observed := &observedInstances{forCluster: []*Instance{{
+		Pods: []*corev1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pod",
+				Annotations: map[string]string{"status": `{"role":"master"}`},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  naming.ContainerDatabase,
+					State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+				}},
+			},
+		}},
+		Runner: &appsv1.StatefulSet{},
+	}}}
+
+	var commands []string
+	reconciler := &Reconciler{
+		PodExec: func(
+			ctx context.Context, namespace, pod, container string,
+			stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			commands = append(commands, strings.Join(command, " ")+"\n"+string(b))
+			return nil
+		},
+	}
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Status.Users = []v1beta1.PostgresUserStatus{{Name: "alice"}, {Name: "bob"}}
+	cluster.Spec.UserPruning = &v1beta1.PostgresUserPruningSpec{
+		Users: v1beta1.PostgresUserPruningDrop,
+	}
+
+	users := []v1beta1.PostgresUserSpec{
+		{Name: "alice"},
+		{Name: "bob", PasswordSecretRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "vault"},
+			Key:                  "password",
+		}},
+	}
+
+	// The user without a password Secret is neither written nor pruned.
+	assert.NilError(t, reconciler.reconcilePostgresUsersInPostgreSQL(ctx, cluster, observed,
+		users, map[string]*corev1.Secret{"alice": {}}))
+	assert.Assert(t, len(commands) > 0)
+	for _, command := range commands {
+		assert.Assert(t, !strings.Contains(command, "bob"), "command: %q", command)
+	}
+	assert.DeepEqual(t, cluster.Status.Users, []v1beta1.PostgresUserStatus{
+		{Name: "alice"}, {Name: "bob"},
+	})
+}
+
+func TestReconcilePostgresUsersPrivilegesPending(t *testing.T) {
+	ctx := context.Background()
+
+	observed := &observedInstances{forCluster: []*Instance{{
+		Pods: []*corev1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pod",
+				Annotations: map[string]string{"status": `{"role":"master"}`},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  naming.ContainerDatabase,
+					State: corev1.ContainerState{Running: new(corev1.ContainerStateRunning)},
+				}},
+			},
+		}},
+		Runner: &appsv1.StatefulSet{},
+	}}}
+
+	missing := `["app.reports"]`
+	reconciler := &Reconciler{
+		PodExec: func(
+			ctx context.Context, namespace, pod, container string,
+			stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			if strings.Contains(string(b), `\echo :missing`) {
+				_, _ = stdout.Write([]byte(missing + "\n"))
+			}
+			return nil
+		},
+	}
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Spec.Roles = []v1beta1.PostgresRoleSpec{{
+		Name: "readers",
+		Grants: []v1beta1.PostgresGrantSpec{{
+			Database: "app", Schema: "reports", On: "Tables",
+			Privileges: []v1beta1.PostgresPrivilege{"SELECT"},
+		}},
+	}}
+
+	assert.NilError(t, reconciler.reconcilePostgresUsersInPostgreSQL(ctx, cluster, observed,
+		nil, map[string]*corev1.Secret{}))
+	assert.Equal(t, cluster.Status.UsersRevision, "")
+
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.PrivilegesPending)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Reason, "SchemasMissing")
+	assert.Assert(t, cmp.Contains(condition.Message, `["app.reports"]`))
+	assert.Equal(t, privilegesRequeue(cluster), time.Minute)
+
+	// The schema exists now.
+	missing = `[]`
+	assert.NilError(t, reconciler.reconcilePostgresUsersInPostgreSQL(ctx, cluster, observed,
+		nil, map[string]*corev1.Secret{}))
+	assert.Assert(t, cluster.Status.UsersRevision != "")
+	assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions, v1beta1.PrivilegesPending) == nil)
+	assert.Equal(t, privilegesRequeue(cluster), time.Duration(0))
+}
+
+func TestValidatePostgresParameters(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Empty", func(t *testing.T) {
+		cluster := v1beta1.NewPostgresCluster()
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+
+		reconciler.validatePostgresParameters(cluster)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		cluster := v1beta1.NewPostgresCluster()
+		cluster.Spec.PostgresVersion = 16
+		cluster.Spec.Config.Parameters = map[string]intstr.IntOrString{
+			"shared_buffers":  intstr.FromString("1GB"),
+			"max_connections": intstr.FromInt(200),
+			"pgaudit.log":     intstr.FromString("all"),
+		}
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{{
+			Parameters: map[string]intstr.IntOrString{
+				"work_mem": intstr.FromString("64MB"),
+			},
+		}}
+
+		reconciler := &Reconciler{}
+		assert.Assert(t, reconciler.Recorder == nil,
+			"expected the following to not use a Recorder at all")
+
+		reconciler.validatePostgresParameters(cluster)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		cluster := v1beta1.NewPostgresCluster()
+		cluster.Name = "pg2"
+		cluster.Spec.PostgresVersion = 16
+		cluster.Spec.Config.Parameters = map[string]intstr.IntOrString{
+			"shared_buffers":    intstr.FromString("1GB"),
+			"wal_keep_segments": intstr.FromInt(10),
+		}
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+			{Name: "ok"},
+			{Name: "bad", Parameters: map[string]intstr.IntOrString{
+				"jit": intstr.FromString("sometimes"),
+			}},
+		}
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+
+		reconciler.validatePostgresParameters(cluster)
+		assert.Equal(t, len(recorder.Events), 2)
+
+		for _, event := range recorder.Events {
+			assert.Equal(t, event.Regarding.Name, cluster.Name)
+			assert.Equal(t, event.Reason, "InvalidParameters")
+		}
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note,
+			"spec.config.parameters[wal_keep_segments]"))
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note,
+			"does not exist in PostgreSQL 16"))
+		assert.Assert(t, cmp.Contains(recorder.Events[1].Note,
+			"spec.instances[1].parameters[jit]"))
+		assert.Assert(t, cmp.Contains(recorder.Events[1].Note, "not a boolean"))
+	})
+
+	t.Run("Unrecognized", func(t *testing.T) {
+		cluster := v1beta1.NewPostgresCluster()
+		cluster.Spec.PostgresVersion = 16
+		cluster.Spec.Config.Parameters = map[string]intstr.IntOrString{
+			"shared_buffers":  intstr.FromString("1GB"),
+			"some_new_option": intstr.FromString("on"),
+		}
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+
+		reconciler.validatePostgresParameters(cluster)
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "UnrecognizedParameters")
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note,
+			"spec.config.parameters[some_new_option]"))
+	})
+
+	t.Run("AllDelayed", func(t *testing.T) {
+		cluster := v1beta1.NewPostgresCluster()
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+			{Name: "one", RecoveryMinApplyDelay: initialize.String("1h")},
+			{Name: "two", RecoveryMinApplyDelay: initialize.String("2h")},
+		}
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+
+		reconciler.validatePostgresParameters(cluster)
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "spec.instances"))
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "must not have a recoveryMinApplyDelay"))
+
+		cluster.Spec.InstanceSets[1].RecoveryMinApplyDelay = nil
+		recorder.Events = nil
+		reconciler.validatePostgresParameters(cluster)
+		assert.Equal(t, len(recorder.Events), 0)
+	})
+}
+
 func TestValidatePostgresUsers(t *testing.T) {
 	t.Parallel()
 
@@ -1217,6 +1946,51 @@ func TestValidatePostgresUsers(t *testing.T) {
 		}
 	})
 
+	t.Run("PasswordRotation", func(t *testing.T) {
+		cluster := v1beta1.NewPostgresCluster()
+		cluster.Name = "pg6"
+		cluster.Spec.Users = []v1beta1.PostgresUserSpec{
+			{Name: "expires", Options: "LOGIN VALID UNTIL 'infinity'"},
+			{Name: v1beta1.PostgresIdentifier(strings.Repeat("x", 60))},
+			{Name: "postgres", Options: "VALID UNTIL 'infinity'"},
+		}
+		for i := range cluster.Spec.Users {
+			cluster.Spec.Users[i].PasswordRotation = &v1beta1.PostgresPasswordRotationSpec{
+				Period: metav1.Duration{Duration: time.Hour},
+			}
+		}
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+
+		reconciler.validatePostgresUsers(cluster)
+		assert.Equal(t, len(recorder.Events), 2)
+		assert.Equal(t, recorder.Events[0].Reason, "InvalidUser")
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "spec.users[0].options"))
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "cannot set VALID UNTIL"))
+		assert.Equal(t, recorder.Events[1].Reason, "InvalidUser")
+		assert.Assert(t, cmp.Contains(recorder.Events[1].Note, "spec.users[1].name"))
+		assert.Assert(t, cmp.Contains(recorder.Events[1].Note, "at most 59 chars"))
+	})
+
+	t.Run("ClientCertificate", func(t *testing.T) {
+		cluster := v1beta1.NewPostgresCluster()
+		cluster.Name = "pg7"
+		cluster.Spec.CustomTLSSecret = &corev1.SecretProjection{}
+		cluster.Spec.Users = []v1beta1.PostgresUserSpec{
+			{Name: "alice", ClientCertificate: true},
+		}
+
+		recorder := events.NewRecorder(t, runtime.Scheme)
+		reconciler := &Reconciler{Recorder: recorder}
+
+		reconciler.validatePostgresUsers(cluster)
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Equal(t, recorder.Events[0].Reason, "InvalidUser")
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "spec.users[0].clientCertificate"))
+		assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "customTLSSecret"))
+	})
+
 	t.Run("Valid", func(t *testing.T) {
 		cluster := v1beta1.NewPostgresCluster()
 		cluster.Spec.Users = []v1beta1.PostgresUserSpec{
@@ -0,0 +1,164 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// validatePostgresRoles emits warnings when cluster.Spec.Roles conflicts with
+// users or when grants have privileges that do not apply to their objects.
+// NOTE(validation)
+func (r *Reconciler) validatePostgresRoles(cluster *v1beta1.PostgresCluster) {
+	users := sets.New[v1beta1.PostgresIdentifier]()
+	for _, user := range cluster.Spec.Users {
+		users.Insert(user.Name)
+	}
+
+	validate := func(path *field.Path, grants []v1beta1.PostgresGrantSpec) field.ErrorList {
+		errs := field.ErrorList{}
+		for i := range grants {
+			if err := postgres.ValidateGrant(grants[i]); err != nil {
+				errs = append(errs, field.Invalid(path.Index(i).Child("privileges"),
+					grants[i].Privileges, err.Error()))
+			}
+		}
+		return errs
+	}
+
+	for i, role := range cluster.Spec.Roles {
+		path := field.NewPath("spec", "roles").Index(i)
+		errs := validate(path.Child("grants"), role.Grants)
+
+		if users.Has(role.Name) {
+			errs = append(errs, field.Invalid(path.Child("name"), role.Name,
+				"cannot be the name of a user"))
+		}
+		if len(errs) > 0 {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidRole",
+				errs.ToAggregate().Error())
+		}
+	}
+
+	for i, user := range cluster.Spec.Users {
+		path := field.NewPath("spec", "users").Index(i)
+		if errs := validate(path.Child("grants"), user.Grants); len(errs) > 0 {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidUser",
+				errs.ToAggregate().Error())
+		}
+	}
+}
+
+// postgresGroupRoles returns the roles of cluster that are not also users.
+func postgresGroupRoles(
+	cluster *v1beta1.PostgresCluster, users []v1beta1.PostgresUserSpec,
+) []v1beta1.PostgresRoleSpec {
+	names := sets.New[v1beta1.PostgresIdentifier]()
+	for _, user := range users {
+		names.Insert(user.Name)
+	}
+
+	var roles []v1beta1.PostgresRoleSpec
+	for _, role := range cluster.Spec.Roles {
+		if !names.Has(role.Name) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// postgresRoleGrants returns the memberships and privileges of roles and users
+// sorted by role name.
+func postgresRoleGrants(
+	roles []v1beta1.PostgresRoleSpec, users []v1beta1.PostgresUserSpec,
+) []v1beta1.PostgresRoleGrantsStatus {
+	var result []v1beta1.PostgresRoleGrantsStatus
+
+	add := func(name v1beta1.PostgresIdentifier,
+		memberOf []v1beta1.PostgresIdentifier, grants []v1beta1.PostgresGrantSpec,
+	) {
+		if len(memberOf) == 0 && len(grants) == 0 {
+			return
+		}
+		status := v1beta1.PostgresRoleGrantsStatus{Role: string(name), Grants: grants}
+		for _, role := range memberOf {
+			status.MemberOf = append(status.MemberOf, string(role))
+		}
+		result = append(result, status)
+	}
+
+	for _, role := range roles {
+		add(role.Name, role.MemberOf, role.Grants)
+	}
+	for _, user := range users {
+		add(user.Name, user.MemberOf, user.Grants)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Role < result[j].Role })
+	return result
+}
+
+// flattenRoleGrants returns the memberships and privileges in statuses.
+func flattenRoleGrants(
+	statuses []v1beta1.PostgresRoleGrantsStatus,
+) ([]postgres.Membership, []postgres.Grant) {
+	var memberships []postgres.Membership
+	var grants []postgres.Grant
+
+	for _, status := range statuses {
+		for _, role := range status.MemberOf {
+			memberships = append(memberships, postgres.Membership{
+				Role: status.Role, MemberOf: role,
+			})
+		}
+		for _, grant := range status.Grants {
+			grants = append(grants, postgres.Grant{
+				Role: status.Role, PostgresGrantSpec: grant,
+			})
+		}
+	}
+	return memberships, grants
+}
+
+// revokedRoleGrants returns the memberships and privileges in previous that
+// are not in current.
+func revokedRoleGrants(
+	previous, current []v1beta1.PostgresRoleGrantsStatus,
+) ([]postgres.Membership, []postgres.Grant) {
+	previousMemberships, previousGrants := flattenRoleGrants(previous)
+	currentMemberships, currentGrants := flattenRoleGrants(current)
+
+	var memberships []postgres.Membership
+	for _, membership := range previousMemberships {
+		found := false
+		for _, other := range currentMemberships {
+			found = found || membership == other
+		}
+		if !found {
+			memberships = append(memberships, membership)
+		}
+	}
+
+	var grants []postgres.Grant
+	for _, grant := range previousGrants {
+		found := false
+		for _, other := range currentGrants {
+			found = found || equality.Semantic.DeepEqual(grant, other)
+		}
+		if !found {
+			grants = append(grants, grant)
+		}
+	}
+
+	return memberships, grants
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgrescluster
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/internal/testing/events"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestValidatePostgresRoles(t *testing.T) {
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Name = "pg1"
+	cluster.Spec.Users = []v1beta1.PostgresUserSpec{
+		{Name: "alice", Grants: []v1beta1.PostgresGrantSpec{
+			{Database: "db1", Schema: "s1", On: "Tables", Privileges: []v1beta1.PostgresPrivilege{"SELECT"}},
+			{Database: "db1", Schema: "s1", Privileges: []v1beta1.PostgresPrivilege{"EXECUTE"}},
+		}},
+	}
+	cluster.Spec.Roles = []v1beta1.PostgresRoleSpec{
+		{Name: "readers"},
+		{Name: "alice"},
+	}
+
+	recorder := events.NewRecorder(t, runtime.Scheme)
+	reconciler := &Reconciler{Recorder: recorder}
+
+	reconciler.validatePostgresRoles(cluster)
+	assert.Equal(t, len(recorder.Events), 2)
+
+	assert.Equal(t, recorder.Events[0].Reason, "InvalidRole")
+	assert.Assert(t, cmp.Contains(recorder.Events[0].Note, "spec.roles[1].name"))
+	assert.Equal(t, recorder.Events[1].Reason, "InvalidUser")
+	assert.Assert(t, cmp.Contains(recorder.Events[1].Note, "spec.users[0].grants[1].privileges"))
+	assert.Assert(t, cmp.Contains(recorder.Events[1].Note, "EXECUTE do not apply to Schema"))
+
+	assert.DeepEqual(t, postgresGroupRoles(cluster, cluster.Spec.Users),
+		[]v1beta1.PostgresRoleSpec{{Name: "readers"}})
+}
+
+func TestPostgresRoleGrants(t *testing.T) {
+	usage := v1beta1.PostgresGrantSpec{
+		Database: "db1", Schema: "s1", Privileges: []v1beta1.PostgresPrivilege{"USAGE"},
+	}
+	selects := v1beta1.PostgresGrantSpec{
+		Database: "db1", Schema: "s1", On: "Tables", Default: true,
+		Privileges: []v1beta1.PostgresPrivilege{"SELECT"},
+	}
+
+	granted := postgresRoleGrants(
+		[]v1beta1.PostgresRoleSpec{
+			{Name: "readers", Grants: []v1beta1.PostgresGrantSpec{usage, selects}},
+			{Name: "empty"},
+		},
+		[]v1beta1.PostgresUserSpec{
+			{Name: "alice", MemberOf: []v1beta1.PostgresIdentifier{"readers"}},
+			{Name: "bob"},
+		})
+
+	assert.DeepEqual(t, granted, []v1beta1.PostgresRoleGrantsStatus{
+		{Role: "alice", MemberOf: []string{"readers"}},
+		{Role: "readers", Grants: []v1beta1.PostgresGrantSpec{usage, selects}},
+	})
+
+	memberships, grants := flattenRoleGrants(granted)
+	assert.DeepEqual(t, memberships, []postgres.Membership{{Role: "alice", MemberOf: "readers"}})
+	assert.DeepEqual(t, grants, []postgres.Grant{
+		{Role: "readers", PostgresGrantSpec: usage},
+		{Role: "readers", PostgresGrantSpec: selects},
+	})
+
+	t.Run("Revoked", func(t *testing.T) {
+		memberships, grants := revokedRoleGrants(granted, granted)
+		assert.Assert(t, memberships == nil)
+		assert.Assert(t, grants == nil)
+
+		current := postgresRoleGrants(
+			[]v1beta1.PostgresRoleSpec{
+				{Name: "readers", Grants: []v1beta1.PostgresGrantSpec{usage}},
+			},
+			[]v1beta1.PostgresUserSpec{
+				{Name: "alice", MemberOf: []v1beta1.PostgresIdentifier{"writers"}},
+			})
+
+		memberships, grants = revokedRoleGrants(granted, current)
+		assert.DeepEqual(t, memberships, []postgres.Membership{{Role: "alice", MemberOf: "readers"}})
+		assert.DeepEqual(t, grants, []postgres.Grant{{Role: "readers", PostgresGrantSpec: selects}})
+	})
+}
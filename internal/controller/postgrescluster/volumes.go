@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 	batchv1 "k8s.io/api/batch/v1"
@@ -347,9 +348,11 @@ func (r *Reconciler) configureExistingRepoVolumes(
 
 // reconcileDirMoveJobs creates the existing volume move Jobs as defined in
 // the PostgresCluster spec. A boolean value is return to indicate whether
-// the main control loop should return early.
+// the main control loop should return early. The first Jobs are created
+// only during a maintenance window; the second boolean value indicates that
+// they are waiting for one.
 func (r *Reconciler) reconcileDirMoveJobs(ctx context.Context,
-	cluster *v1beta1.PostgresCluster) (bool, error) {
+	cluster *v1beta1.PostgresCluster) (returnEarly, deferred bool, err error) {
 
 	if cluster.Spec.DataSource != nil &&
 		cluster.Spec.DataSource.Volumes != nil {
@@ -359,7 +362,13 @@ func (r *Reconciler) reconcileDirMoveJobs(ctx context.Context,
 			Namespace:     cluster.Namespace,
 			LabelSelector: naming.DirectoryMoveJobLabels(cluster.Name).AsSelector(),
 		}); err != nil {
-			return false, errors.WithStack(err)
+			return false, false, errors.WithStack(err)
+		}
+
+		// Once started, moves continue regardless of maintenance windows.
+		if len(moveJobs.Items) == 0 && dirMovesRequired(cluster) &&
+			!maintenanceAllowed(cluster, time.Now()) {
+			return false, true, nil
 		}
 
 		var err error
@@ -391,10 +400,24 @@ func (r *Reconciler) reconcileDirMoveJobs(ctx context.Context,
 			repoReturn, err = r.reconcileMoveRepoDir(ctx, cluster, moveJobs)
 		}
 		// if any of the 'return early' values are true, return true
-		return pgDataReturn || pgWALReturn || repoReturn, err
+		return pgDataReturn || pgWALReturn || repoReturn, false, err
 	}
 
-	return false, nil
+	return false, false, nil
+}
+
+// dirMovesRequired returns whether or not cluster has any existing directories
+// to move.
+func dirMovesRequired(cluster *v1beta1.PostgresCluster) bool {
+	volumes := cluster.Spec.DataSource.Volumes
+	for _, volume := range []*v1beta1.DataSourceVolume{
+		volumes.PGDataVolume, volumes.PGWALVolume, volumes.PGBackRestVolume,
+	} {
+		if volume != nil && volume.Directory != "" && volume.PVCName != "" {
+			return true
+		}
+	}
+	return false
 }
 
 // +kubebuilder:rbac:groups="batch",resources="jobs",verbs={create,patch,delete}
@@ -7,6 +7,7 @@ package postgrescluster
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
 	"github.com/crunchydata/postgres-operator/internal/initialize"
@@ -722,8 +724,9 @@ func TestReconcileMoveDirectories(t *testing.T) {
 	assert.NilError(t, tClient.Create(ctx, cluster))
 	t.Cleanup(func() { assert.Check(t, tClient.Delete(ctx, cluster)) })
 
-	returnEarly, err := r.reconcileDirMoveJobs(ctx, cluster)
+	returnEarly, deferred, err := r.reconcileDirMoveJobs(ctx, cluster)
 	assert.NilError(t, err)
+	assert.Assert(t, !deferred)
 	// returnEarly will initially be true because the Jobs will not have
 	// completed yet
 	assert.Assert(t, returnEarly)
@@ -916,3 +919,34 @@ volumes:
 
 	})
 }
+
+func TestReconcileDirMoveJobsMaintenance(t *testing.T) {
+	ctx := context.Background()
+	reconciler := &Reconciler{
+		Client: fake.NewClientBuilder().WithScheme(runtime.Scheme).Build(),
+	}
+
+	cluster := v1beta1.NewPostgresCluster()
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	cluster.Spec.DataSource = &v1beta1.DataSource{
+		Volumes: &v1beta1.DataSourceVolumes{
+			PGDataVolume: &v1beta1.DataSourceVolume{PVCName: "old", Directory: "pgdata"},
+		},
+	}
+
+	// A window that opened and closed a while ago.
+	start := time.Now().Add(-2 * time.Hour)
+	cluster.Spec.MaintenanceWindows = []v1beta1.MaintenanceWindow{{
+		Schedule: fmt.Sprintf("%d %d * * *", start.UTC().Minute(), start.UTC().Hour()),
+		Duration: metav1.Duration{Duration: time.Minute},
+	}}
+
+	returnEarly, deferred, err := reconciler.reconcileDirMoveJobs(ctx, cluster)
+	assert.NilError(t, err)
+	assert.Assert(t, !returnEarly, "expected the rest of Reconcile to continue")
+	assert.Assert(t, deferred)
+
+	jobs := &batchv1.JobList{}
+	assert.NilError(t, reconciler.Client.List(ctx, jobs))
+	assert.Equal(t, len(jobs.Items), 0)
+}
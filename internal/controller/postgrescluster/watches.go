@@ -6,6 +6,7 @@ package postgrescluster
 
 import (
 	"context"
+	"strings"
 
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/internal/patroni"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 // watchPods returns a handler.EventHandler for Pods.
@@ -61,10 +63,20 @@ func (*Reconciler) watchPods() handler.Funcs {
 				return
 			}
 
-			oldAnnotations := e.ObjectOld.GetAnnotations()
-			newAnnotations := e.ObjectNew.GetAnnotations()
-			// If the suggested-pgdata-pvc-size annotation is added or changes, reconcile.
-			if len(cluster) != 0 && oldAnnotations["suggested-pgdata-pvc-size"] != newAnnotations["suggested-pgdata-pvc-size"] {
+			// Queue an event when PostgreSQL loads other certificate authorities.
+			// A rotation of the root certificate authority may advance.
+			if len(cluster) != 0 &&
+				e.ObjectOld.GetAnnotations()[naming.TrustedCertificateAuthorities] !=
+					e.ObjectNew.GetAnnotations()[naming.TrustedCertificateAuthorities] {
+				q.Add(reconcile.Request{NamespacedName: client.ObjectKey{
+					Namespace: e.ObjectNew.GetNamespace(),
+					Name:      cluster,
+				}})
+				return
+			}
+
+			// If a suggested volume size annotation is added or changes, reconcile.
+			if len(cluster) != 0 && suggestedVolumeSizesChanged(e.ObjectOld, e.ObjectNew) {
 				q.Add(reconcile.Request{NamespacedName: client.ObjectKey{
 					Namespace: e.ObjectNew.GetNamespace(),
 					Name:      cluster,
@@ -74,3 +86,108 @@ func (*Reconciler) watchPods() handler.Funcs {
 		},
 	}
 }
+
+// suggestedVolumeSizesChanged returns true when any annotation that suggests
+// a volume size, such as "suggested-pgdata-pvc-size", differs between objects.
+func suggestedVolumeSizesChanged(before, after client.Object) bool {
+	oldAnnotations, newAnnotations := before.GetAnnotations(), after.GetAnnotations()
+	suggested := func(key string) bool {
+		return strings.HasPrefix(key, "suggested-") && strings.HasSuffix(key, "-pvc-size")
+	}
+
+	for key, value := range newAnnotations {
+		if suggested(key) && oldAnnotations[key] != value {
+			return true
+		}
+	}
+	for key := range oldAnnotations {
+		if _, ok := newAnnotations[key]; suggested(key) && !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// watchReferencedSecrets returns a [handler.EventHandler] for Secrets that
+// contain the passwords of PostgreSQL users, options of pg_hba rules, or
+// certificate authorities. It also enqueues the cluster of each certificate
+// that cert-manager issues.
+func (r *Reconciler) watchReferencedSecrets() handler.Funcs {
+	handle := func(ctx context.Context, secret client.Object, q workqueue.RateLimitingInterface) {
+		for _, cluster := range r.findPostgresClustersForSecret(ctx, client.ObjectKeyFromObject(secret)) {
+			q.Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+		}
+
+		labels := secret.GetLabels()
+		if name := labels[naming.LabelCluster]; name != "" &&
+			labels[naming.LabelClusterCertificate] == certManagerCertificateLabel {
+			q.Add(reconcile.Request{NamespacedName: client.ObjectKey{
+				Namespace: secret.GetNamespace(), Name: name,
+			}})
+		}
+	}
+
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.RateLimitingInterface) {
+			handle(ctx, e.Object, q)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+			handle(ctx, e.ObjectNew, q)
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+			handle(ctx, e.Object, q)
+		},
+	}
+}
+
+// findPostgresClustersForSecret returns PostgresClusters that have a user with
+// its password in secret, a pg_hba rule with options in secret, or a
+// certificate issuer in secret.
+func (r *Reconciler) findPostgresClustersForSecret(
+	ctx context.Context, secret client.ObjectKey,
+) []*v1beta1.PostgresCluster {
+	var matching []*v1beta1.PostgresCluster
+	var clusters v1beta1.PostgresClusterList
+
+	// NOTE: If this becomes slow due to a large number of PostgresClusters in
+	// a single namespace, we can configure the [ctrl.Manager] field indexer and
+	// pass a [fields.Selector] here.
+	// - https://book.kubebuilder.io/reference/watching-resources/externally-managed.html
+	if err := r.Client.List(ctx, &clusters, &client.ListOptions{
+		Namespace: secret.Namespace,
+	}); err == nil {
+		for i := range clusters.Items {
+			if referencesSecret(&clusters.Items[i], secret.Name) {
+				matching = append(matching, &clusters.Items[i])
+			}
+		}
+	}
+	return matching
+}
+
+// referencesSecret returns whether or not cluster takes any user password,
+// pg_hba option, or certificate authority from the Secret called name. The
+// root certificate authority is shared by clusters in a namespace.
+func referencesSecret(cluster *v1beta1.PostgresCluster, name string) bool {
+	for _, user := range cluster.Spec.Users {
+		if user.PasswordSecretRef != nil && user.PasswordSecretRef.Name == name {
+			return true
+		}
+	}
+	if cluster.Spec.CertificateIssuer == nil && name == naming.RootCertSecret {
+		return true
+	}
+	if issuer := certificateIssuerSecret(cluster); issuer != "" && issuer == name {
+		return true
+	}
+	if cluster.Spec.Authentication != nil {
+		for _, rule := range cluster.Spec.Authentication.Rules {
+			for _, ref := range rule.SecretOptions {
+				if ref.Name == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -12,9 +12,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 func TestWatchPodsUpdate(t *testing.T) {
@@ -132,6 +136,41 @@ func TestWatchPodsUpdate(t *testing.T) {
 		queue.Done(item)
 	})
 
+	t.Run("TrustedCertificateAuthorities", func(t *testing.T) {
+		expected := reconcile.Request{}
+		expected.Namespace = "some-ns"
+		expected.Name = "starfish"
+
+		base := &corev1.Pod{}
+		base.Namespace = "some-ns"
+		base.Labels = map[string]string{
+			"postgres-operator.crunchydata.com/cluster": "starfish",
+		}
+
+		loaded := base.DeepCopy()
+		loaded.Annotations = map[string]string{
+			"postgres-operator.crunchydata.com/trusted-certificate-authorities": "abc",
+		}
+
+		// Newly loaded; one reconcile by label.
+		update(ctx, event.UpdateEvent{
+			ObjectOld: base.DeepCopy(),
+			ObjectNew: loaded.DeepCopy(),
+		}, queue)
+		assert.Equal(t, queue.Len(), 1, "expected one reconcile")
+
+		item, _ := queue.Get()
+		assert.Equal(t, item, expected)
+		queue.Done(item)
+
+		// Unchanged; no reconcile.
+		update(ctx, event.UpdateEvent{
+			ObjectOld: loaded.DeepCopy(),
+			ObjectNew: loaded.DeepCopy(),
+		}, queue)
+		assert.Equal(t, queue.Len(), 0)
+	})
+
 	// Pod annotation with arbitrary key; no reconcile.
 	update(ctx, event.UpdateEvent{
 		ObjectOld: &corev1.Pod{
@@ -181,4 +220,123 @@ func TestWatchPodsUpdate(t *testing.T) {
 		},
 	}, queue)
 	assert.Equal(t, queue.Len(), 1)
+
+	item, _ = queue.Get()
+	queue.Done(item)
+	queue.Forget(item)
+
+	// Pod annotation with suggested-repo1-pvc-size; reconcile.
+	update(ctx, event.UpdateEvent{
+		ObjectOld: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					"postgres-operator.crunchydata.com/cluster": "starfish",
+				},
+			},
+		},
+		ObjectNew: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"suggested-repo1-pvc-size": "3000Mi",
+				},
+				Labels: map[string]string{
+					"postgres-operator.crunchydata.com/cluster": "starfish",
+				},
+			},
+		},
+	}, queue)
+	assert.Equal(t, queue.Len(), 1)
+}
+
+func TestSuggestedVolumeSizesChanged(t *testing.T) {
+	pod := func(annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+
+	assert.Assert(t, !suggestedVolumeSizesChanged(pod(nil), pod(nil)))
+	assert.Assert(t, !suggestedVolumeSizesChanged(
+		pod(map[string]string{"other": "a"}), pod(map[string]string{"other": "b"})))
+	assert.Assert(t, !suggestedVolumeSizesChanged(
+		pod(map[string]string{"suggested-pgwal-pvc-size": "1Gi"}),
+		pod(map[string]string{"suggested-pgwal-pvc-size": "1Gi"})))
+
+	assert.Assert(t, suggestedVolumeSizesChanged(
+		pod(nil), pod(map[string]string{"suggested-tablespace-castle-pvc-size": "1Gi"})))
+	assert.Assert(t, suggestedVolumeSizesChanged(
+		pod(map[string]string{"suggested-pgwal-pvc-size": "1Gi"}),
+		pod(map[string]string{"suggested-pgwal-pvc-size": "2Gi"})))
+	assert.Assert(t, suggestedVolumeSizesChanged(
+		pod(map[string]string{"suggested-repo2-pvc-size": "1Gi"}), pod(nil)))
+}
+
+func TestWatchReferencedSecrets(t *testing.T) {
+	ctx := context.Background()
+	queue := &controllertest.Queue{Interface: workqueue.New()}
+
+	referenced := v1beta1.NewPostgresCluster()
+	referenced.Namespace, referenced.Name = "ns1", "referenced"
+	referenced.Spec.Users = []v1beta1.PostgresUserSpec{{
+		Name: "alice",
+		PasswordSecretRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "vault"},
+			Key:                  "password",
+		},
+	}}
+
+	other := v1beta1.NewPostgresCluster()
+	other.Namespace, other.Name = "ns1", "other"
+	other.Spec.Users = []v1beta1.PostgresUserSpec{{Name: "bob"}}
+
+	ldap := v1beta1.NewPostgresCluster()
+	ldap.Namespace, ldap.Name = "ns1", "ldap"
+	ldap.Spec.Authentication = &v1beta1.PostgresAuthenticationSpec{
+		Rules: []v1beta1.PostgresHBARuleSpec{{
+			Method:  "ldap",
+			Options: map[string]string{"ldapserver": "ldap.example.com"},
+			SecretOptions: map[string]corev1.SecretKeySelector{
+				"ldapbindpasswd": {
+					LocalObjectReference: corev1.LocalObjectReference{Name: "directory"},
+					Key:                  "password",
+				},
+			},
+		}},
+	}
+
+	reconciler := &Reconciler{
+		Client: fake.NewClientBuilder().WithScheme(runtime.Scheme).
+			WithObjects(referenced, other, ldap).Build(),
+	}
+	update := reconciler.watchReferencedSecrets().UpdateFunc
+	assert.Assert(t, update != nil)
+
+	// Unrelated Secret; no reconcile.
+	update(ctx, event.UpdateEvent{
+		ObjectOld: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "other"}},
+		ObjectNew: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "other"}},
+	}, queue)
+	assert.Equal(t, queue.Len(), 0)
+
+	update(ctx, event.UpdateEvent{
+		ObjectOld: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "vault"}},
+		ObjectNew: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "vault"}},
+	}, queue)
+	assert.Equal(t, queue.Len(), 1)
+
+	item, _ := queue.Get()
+	expected := reconcile.Request{}
+	expected.Namespace = "ns1"
+	expected.Name = "referenced"
+	assert.Equal(t, item, expected)
+	queue.Done(item)
+
+	update(ctx, event.UpdateEvent{
+		ObjectOld: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "directory"}},
+		ObjectNew: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "directory"}},
+	}, queue)
+	assert.Equal(t, queue.Len(), 1)
+
+	item, _ = queue.Get()
+	expected.Name = "ldap"
+	assert.Equal(t, item, expected)
+	queue.Done(item)
 }
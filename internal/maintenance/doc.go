@@ -0,0 +1,7 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package maintenance interprets recurring windows of time during which the
+// operator may perform disruptive work, such as restarting PostgreSQL.
+package maintenance
@@ -0,0 +1,170 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package maintenance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a set of minutes described by the five fields of cron.
+// - https://pubs.opengroup.org/onlinepubs/9699919799/utilities/crontab.html
+type Schedule struct {
+	minute, hour, day, month, weekday uint64
+
+	// When both day fields are restricted, a time matches when either does.
+	anyDay, anyWeekday bool
+}
+
+type scheduleField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var scheduleFields = [5]scheduleField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{
+		"", "jan", "feb", "mar", "apr", "may", "jun",
+		"jul", "aug", "sep", "oct", "nov", "dec",
+	}},
+	// Both zero and seven are Sunday.
+	{name: "day of week", min: 0, max: 7, names: []string{
+		"sun", "mon", "tue", "wed", "thu", "fri", "sat",
+	}},
+}
+
+// ParseSchedule interprets text as the five fields of cron: minute, hour, day
+// of month, month, and day of week. Each field is an asterisk or a comma
+// separated list of numbers and ranges, optionally followed by a step. Months
+// and days of the week may also be three letter English names.
+func ParseSchedule(text string) (Schedule, error) {
+	var schedule Schedule
+
+	fields := strings.Fields(text)
+	if len(fields) != len(scheduleFields) {
+		return schedule, fmt.Errorf("expected %d fields, got %d: %q",
+			len(scheduleFields), len(fields), text)
+	}
+
+	bits := [5]*uint64{
+		&schedule.minute, &schedule.hour, &schedule.day,
+		&schedule.month, &schedule.weekday,
+	}
+	for i := range fields {
+		var err error
+		if *bits[i], err = scheduleFields[i].parse(fields[i]); err != nil {
+			return schedule, err
+		}
+	}
+
+	// Sunday is both zero and seven.
+	if schedule.weekday&(1<<7) != 0 {
+		schedule.weekday |= 1
+	}
+
+	schedule.anyDay = strings.HasPrefix(fields[2], "*")
+	schedule.anyWeekday = strings.HasPrefix(fields[4], "*")
+
+	return schedule, nil
+}
+
+func (f scheduleField) parse(text string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(text, ",") {
+		low, high, step := f.min, f.max, 1
+
+		value, stepText, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step in %s: %q", f.name, part)
+			}
+			step = n
+		}
+
+		if value != "*" {
+			lowText, highText, isRange := strings.Cut(value, "-")
+
+			var err error
+			if low, err = f.value(lowText); err != nil {
+				return 0, err
+			}
+			switch {
+			case isRange:
+				if high, err = f.value(highText); err != nil {
+					return 0, err
+				}
+			case hasStep:
+				// A single value with a step continues to the maximum.
+			default:
+				high = low
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range in %s: %q", f.name, part)
+			}
+		}
+
+		for i := low; i <= high; i += step {
+			bits |= 1 << i
+		}
+	}
+
+	return bits, nil
+}
+
+func (f scheduleField) value(text string) (int, error) {
+	for i, name := range f.names {
+		if name != "" && strings.EqualFold(name, text) {
+			return i, nil
+		}
+	}
+
+	n, err := strconv.Atoi(text)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("invalid %s: %q", f.name, text)
+	}
+	return n, nil
+}
+
+func (s Schedule) matchesDay(t time.Time) bool {
+	day := s.day&(1<<t.Day()) != 0
+	weekday := s.weekday&(1<<t.Weekday()) != 0
+
+	if s.anyDay || s.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+// Next returns the first minute of s after t in the location of t. It returns
+// the zero time when s does not occur in the next five years, e.g. February 30th.
+func (s Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		year, month, day := t.Date()
+
+		switch {
+		case s.month&(1<<month) == 0:
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<t.Hour()) == 0:
+			t = time.Date(year, month, day, t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
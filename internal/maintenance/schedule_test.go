@@ -0,0 +1,88 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseSchedule(t *testing.T) {
+	t.Run("Invalid", func(t *testing.T) {
+		for _, tt := range []struct{ text, message string }{
+			{text: "", message: "expected 5 fields"},
+			{text: "* * * *", message: "expected 5 fields"},
+			{text: "* * * * * *", message: "expected 5 fields"},
+			{text: "60 * * * *", message: "invalid minute"},
+			{text: "* 24 * * *", message: "invalid hour"},
+			{text: "* * 0 * *", message: "invalid day of month"},
+			{text: "* * * 13 *", message: "invalid month"},
+			{text: "* * * * 8", message: "invalid day of week"},
+			{text: "* * * * funday", message: "invalid day of week"},
+			{text: "5-1 * * * *", message: "invalid range"},
+			{text: "*/0 * * * *", message: "invalid step"},
+			{text: "*/x * * * *", message: "invalid step"},
+		} {
+			_, err := ParseSchedule(tt.text)
+			assert.ErrorContains(t, err, tt.message, "%q", tt.text)
+		}
+	})
+
+	t.Run("Fields", func(t *testing.T) {
+		s, err := ParseSchedule("0,30 1-3 */10 jan-Mar 7")
+		assert.NilError(t, err)
+		assert.Equal(t, s.minute, uint64(1<<0|1<<30))
+		assert.Equal(t, s.hour, uint64(1<<1|1<<2|1<<3))
+		assert.Equal(t, s.day, uint64(1<<1|1<<11|1<<21|1<<31))
+		assert.Equal(t, s.month, uint64(1<<1|1<<2|1<<3))
+		assert.Equal(t, s.weekday, uint64(1<<0|1<<7), "Sunday is zero and seven")
+		assert.Assert(t, s.anyDay, "like cron, a step of asterisk is unrestricted")
+		assert.Assert(t, !s.anyWeekday)
+
+		s, err = ParseSchedule("15/20 * * * *")
+		assert.NilError(t, err)
+		assert.Equal(t, s.minute, uint64(1<<15|1<<35|1<<55))
+		assert.Assert(t, s.anyDay)
+		assert.Assert(t, s.anyWeekday)
+	})
+}
+
+func TestScheduleNext(t *testing.T) {
+	base := time.Date(2024, time.March, 15, 10, 20, 30, 0, time.UTC) // Friday
+
+	for _, tt := range []struct {
+		schedule string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.March, 15, 10, 21, 0, 0, time.UTC)},
+		{"20 10 * * *", time.Date(2024, time.March, 16, 10, 20, 0, 0, time.UTC)},
+		{"0 2 * * sun", time.Date(2024, time.March, 17, 2, 0, 0, 0, time.UTC)},
+		{"30 4 1 * *", time.Date(2024, time.April, 1, 4, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+
+		// When both days are restricted, either one matches.
+		{"0 0 1 * mon", time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 16 * mon", time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC)},
+	} {
+		s, err := ParseSchedule(tt.schedule)
+		assert.NilError(t, err)
+		assert.Equal(t, s.Next(base), tt.expected, "%q", tt.schedule)
+	}
+
+	t.Run("Location", func(t *testing.T) {
+		zone, err := time.LoadLocation("America/New_York")
+		assert.NilError(t, err)
+
+		s, err := ParseSchedule("0 3 * * *")
+		assert.NilError(t, err)
+
+		next := s.Next(base.In(zone))
+		assert.Equal(t, next.Location(), zone)
+		assert.Equal(t, next.UTC(), time.Date(2024, time.March, 16, 7, 0, 0, 0, time.UTC))
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	// The operator image does not have the IANA Time Zone database; embed it
+	// so that [time.LoadLocation] works there.
+	_ "time/tzdata"
+
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// Window is a recurring period of time that opens according to Schedule in
+// Location and stays open for Duration.
+type Window struct {
+	Schedule Schedule
+	Duration time.Duration
+	Location *time.Location
+}
+
+// NewWindow interprets spec as a Window.
+func NewWindow(spec v1beta1.MaintenanceWindow) (Window, error) {
+	var err error
+	window := Window{Duration: spec.Duration.Duration, Location: time.UTC}
+
+	if window.Schedule, err = ParseSchedule(spec.Schedule); err != nil {
+		return window, err
+	}
+	if window.Duration <= 0 {
+		return window, fmt.Errorf("duration must be positive, got %v", window.Duration)
+	}
+	if spec.TimeZone != nil {
+		if window.Location, err = time.LoadLocation(*spec.TimeZone); err != nil {
+			return window, err
+		}
+	}
+	return window, nil
+}
+
+// Next returns when w opens after t. It returns the zero time when w never opens.
+func (w Window) Next(t time.Time) time.Time {
+	return w.Schedule.Next(t.In(w.Location))
+}
+
+// Open returns whether or not w is open at t.
+func (w Window) Open(t time.Time) bool {
+	start := w.Next(t.Add(-w.Duration))
+	return !start.IsZero() && !start.After(t)
+}
+
+// Status returns whether or not disruptive work is allowed at now according
+// to windows. When it is not, next is when the soonest window opens. When
+// windows is empty, disruptive work is always allowed. Windows that cannot be
+// interpreted are never open, and their errors are returned.
+func Status(windows []v1beta1.MaintenanceWindow, now time.Time) (
+	open bool, next time.Time, err error,
+) {
+	if len(windows) == 0 {
+		return true, time.Time{}, nil
+	}
+
+	var errs []error
+	for i := range windows {
+		window, werr := NewWindow(windows[i])
+		if werr != nil {
+			errs = append(errs, fmt.Errorf("window %d: %w", i, werr))
+			continue
+		}
+		if window.Open(now) {
+			open = true
+		}
+		if start := window.Next(now); !start.IsZero() &&
+			(next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	if open {
+		next = time.Time{}
+	}
+
+	return open, next, errors.Join(errs...)
+}
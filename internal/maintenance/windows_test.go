@@ -0,0 +1,103 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestNewWindow(t *testing.T) {
+	_, err := NewWindow(v1beta1.MaintenanceWindow{
+		Schedule: "nope", Duration: metav1.Duration{Duration: time.Hour},
+	})
+	assert.ErrorContains(t, err, "expected 5 fields")
+
+	_, err = NewWindow(v1beta1.MaintenanceWindow{Schedule: "* * * * *"})
+	assert.ErrorContains(t, err, "duration must be positive")
+
+	_, err = NewWindow(v1beta1.MaintenanceWindow{
+		Schedule: "* * * * *", Duration: metav1.Duration{Duration: time.Hour},
+		TimeZone: initialize.String("Mars/Olympus_Mons"),
+	})
+	assert.ErrorContains(t, err, "Mars/Olympus_Mons")
+
+	window, err := NewWindow(v1beta1.MaintenanceWindow{
+		Schedule: "0 1 * * *", Duration: metav1.Duration{Duration: time.Hour},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, window.Location, time.UTC)
+}
+
+func TestWindowOpen(t *testing.T) {
+	window, err := NewWindow(v1beta1.MaintenanceWindow{
+		Schedule: "30 1 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour},
+		TimeZone: initialize.String("Asia/Tokyo"),
+	})
+	assert.NilError(t, err)
+
+	day := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+	tokyo := 9 * time.Hour
+
+	for _, tt := range []struct {
+		at   time.Duration
+		open bool
+	}{
+		{1*time.Hour + 29*time.Minute, false},
+		{1*time.Hour + 30*time.Minute, true},
+		{3*time.Hour + 29*time.Minute, true},
+		{3*time.Hour + 30*time.Minute, false},
+	} {
+		at := day.Add(tt.at - tokyo)
+		assert.Equal(t, window.Open(at), tt.open, "%v", at)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 10, 20, 0, 0, time.UTC)
+
+	t.Run("Empty", func(t *testing.T) {
+		open, next, err := Status(nil, now)
+		assert.NilError(t, err)
+		assert.Assert(t, open)
+		assert.Assert(t, next.IsZero())
+	})
+
+	t.Run("Open", func(t *testing.T) {
+		open, next, err := Status([]v1beta1.MaintenanceWindow{
+			{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+			{Schedule: "0 10 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+		}, now)
+		assert.NilError(t, err)
+		assert.Assert(t, open)
+		assert.Assert(t, next.IsZero())
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		open, next, err := Status([]v1beta1.MaintenanceWindow{
+			{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+			{Schedule: "0 12 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+		}, now)
+		assert.NilError(t, err)
+		assert.Assert(t, !open)
+		assert.Equal(t, next, time.Date(2024, time.March, 15, 12, 0, 0, 0, time.UTC))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		open, next, err := Status([]v1beta1.MaintenanceWindow{
+			{Schedule: "* * * *", Duration: metav1.Duration{Duration: time.Hour}},
+			{Schedule: "0 12 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+		}, now)
+		assert.ErrorContains(t, err, "window 0: expected 5 fields")
+		assert.Assert(t, !open)
+		assert.Equal(t, next, time.Date(2024, time.March, 15, 12, 0, 0, 0, time.UTC))
+	})
+}
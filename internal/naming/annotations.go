@@ -68,4 +68,25 @@ const (
 	// to a cluster without backups. As usual with the operator, we do not
 	// touch cloud-based backups.
 	AuthorizeBackupRemovalAnnotation = annotationPrefix + "authorizeBackupRemoval"
+
+	// PasswordRotated is the annotation added to a PostgreSQL user Secret when
+	// its password is rotated. The value is an RFC 3339 timestamp of the last
+	// rotation.
+	PasswordRotated = annotationPrefix + "password-rotated"
+
+	// RotateRootCertificate is the annotation added to a PostgresCluster to
+	// rotate the root certificate authority of its namespace. The value is a
+	// unique identifier, such as a timestamp, which is stored in the
+	// PostgresCluster status to start only one rotation for each value.
+	RotateRootCertificate = annotationPrefix + "rotate-root-certificate"
+
+	// RootCertificateRotation is the annotation on the root certificate
+	// Secret that holds the phase of a rotation that is in progress and when
+	// that phase started, separated by a space.
+	RootCertificateRotation = annotationPrefix + "root-certificate-rotation"
+
+	// TrustedCertificateAuthorities is the annotation on instance Pods that
+	// holds the SHA-256 of the certificate authorities that PostgreSQL loaded
+	// last, in hexadecimal.
+	TrustedCertificateAuthorities = annotationPrefix + "trusted-certificate-authorities"
 )
@@ -16,6 +16,7 @@ func TestAnnotationsValid(t *testing.T) {
 	assert.Assert(t, nil == validation.IsQualifiedName(AutoCreateUserSchemaAnnotation))
 	assert.Assert(t, nil == validation.IsQualifiedName(CrunchyBridgeClusterAdoptionAnnotation))
 	assert.Assert(t, nil == validation.IsQualifiedName(Finalizer))
+	assert.Assert(t, nil == validation.IsQualifiedName(PasswordRotated))
 	assert.Assert(t, nil == validation.IsQualifiedName(PatroniSwitchover))
 	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestBackup))
 	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestBackupJobCompletion))
@@ -23,4 +24,6 @@ func TestAnnotationsValid(t *testing.T) {
 	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestIPVersion))
 	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestRestore))
 	assert.Assert(t, nil == validation.IsQualifiedName(PostgresExporterCollectorsAnnotation))
+	assert.Assert(t, nil == validation.IsQualifiedName(RootCertificateRotation))
+	assert.Assert(t, nil == validation.IsQualifiedName(RotateRootCertificate))
 }
@@ -22,6 +22,10 @@ const (
 	LabelPatroni = labelPrefix + "patroni"
 	LabelRole    = labelPrefix + "role"
 
+	// LabelLoadBalance is applied to instance Pods that can receive traffic
+	// from the replica Service when some instances in the cluster cannot.
+	LabelLoadBalance = labelPrefix + "loadbalance"
+
 	// LabelClusterCertificate is used to identify a secret containing a cluster certificate
 	LabelClusterCertificate = labelPrefix + "cluster-certificate"
 
@@ -17,6 +17,7 @@ func TestLabelsValid(t *testing.T) {
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelData))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelInstance))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelInstanceSet))
+	assert.Assert(t, nil == validation.IsQualifiedName(LabelLoadBalance))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelMoveJob))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelMovePGBackRestRepoDir))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelMovePGDataDir))
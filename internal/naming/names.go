@@ -434,6 +434,16 @@ func PatroniScope(cluster *v1beta1.PostgresCluster) string {
 	return cluster.Name + "-ha"
 }
 
+// PatroniSync returns the ObjectMeta necessary to lookup the ConfigMap or
+// Endpoints Patroni creates for cluster to track its synchronous standbys.
+// See Patroni DCS "sync_path".
+func PatroniSync(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      PatroniScope(cluster) + "-sync",
+	}
+}
+
 // PatroniTrigger returns the ObjectMeta necessary to lookup the ConfigMap or
 // Endpoints Patroni creates for cluster to initiate a controlled change of the
 // leader. See Patroni DCS "failover_path".
@@ -551,6 +561,25 @@ func PostgresTLSSecret(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
 	}
 }
 
+// PostgresHBASecret returns the ObjectMeta necessary to lookup the Secret
+// containing the pg_hba.conf records of cluster that have secret options.
+func PostgresHBASecret(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name + "-pg-hba",
+	}
+}
+
+// ClusterLeafCertificate returns the ObjectMeta of a cert-manager Certificate
+// and Secret that hold a leaf certificate of cluster. The suffix identifies
+// the subject of the certificate.
+func ClusterLeafCertificate(cluster *v1beta1.PostgresCluster, suffix string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name + "-cert-" + suffix,
+	}
+}
+
 // MovePGDataDirJob returns the ObjectMeta for a pgData directory move Job
 func MovePGDataDirJob(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
 	return metav1.ObjectMeta{
@@ -86,6 +86,7 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 			{"ClusterPGBouncer", ClusterPGBouncer(cluster)},
 			{"PatroniDistributedConfiguration", PatroniDistributedConfiguration(cluster)},
 			{"PatroniLeaderConfigMap", PatroniLeaderConfigMap(cluster)},
+			{"PatroniSync", PatroniSync(cluster)},
 			{"PatroniTrigger", PatroniTrigger(cluster)},
 			{"PGBackRestConfig", PGBackRestConfig(cluster)},
 			{"PGBackRestSSHConfig", PGBackRestSSHConfig(cluster)},
@@ -137,9 +138,11 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 
 	t.Run("Secrets", func(t *testing.T) {
 		names := testUniqueAndValid(t, []test{
+			{"ClusterLeafCertificate", ClusterLeafCertificate(cluster, "abc")},
 			{"ClusterPGBouncer", ClusterPGBouncer(cluster)},
 			{"DeprecatedPostgresUserSecret", DeprecatedPostgresUserSecret(cluster)},
 			{"PostgresTLSSecret", PostgresTLSSecret(cluster)},
+			{"PostgresHBASecret", PostgresHBASecret(cluster)},
 			{"ReplicationClientCertSecret", ReplicationClientCertSecret(cluster)},
 			{"PGBackRestSSHSecret", PGBackRestSSHSecret(cluster)},
 			{"MonitoringUserSecret", MonitoringUserSecret(cluster)},
@@ -182,6 +185,7 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 			// Patroni can use Endpoints which relate directly to a Service.
 			{"PatroniDistributedConfiguration", PatroniDistributedConfiguration(cluster)},
 			{"PatroniLeaderEndpoints", PatroniLeaderEndpoints(cluster)},
+			{"PatroniSync", PatroniSync(cluster)},
 			{"PatroniTrigger", PatroniTrigger(cluster)},
 		})
 	})
@@ -5,11 +5,14 @@
 package patroni
 
 import (
+	"errors"
 	"fmt"
 	"path"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/yaml"
 
 	"github.com/crunchydata/postgres-operator/internal/config"
@@ -34,6 +37,27 @@ const (
 		"# Your changes will not be saved.\n"
 )
 
+// clusterOnlyParameters are PostgreSQL parameters that must be the same on
+// every instance. Patroni takes these from dynamic configuration and ignores
+// them in local configuration.
+// - https://patroni.readthedocs.io/en/latest/patroni_configuration.html#postgresql-parameters-controlled-by-patroni
+var clusterOnlyParameters = sets.New(
+	"hot_standby",
+	"listen_addresses",
+	"max_connections",
+	"max_locks_per_transaction",
+	"max_prepared_transactions",
+	"max_replication_slots",
+	"max_wal_senders",
+	"max_worker_processes",
+	"port",
+	"track_commit_timestamp",
+	"wal_keep_segments",
+	"wal_keep_size",
+	"wal_level",
+	"wal_log_hints",
+)
+
 // quoteShellWord ensures that s is interpreted by a shell as single word.
 func quoteShellWord(s string) string {
 	// https://www.gnu.org/software/bash/manual/html_node/Quoting.html
@@ -190,6 +214,16 @@ func DynamicConfiguration(
 	root["ttl"] = *cluster.Spec.Patroni.LeaderLeaseDurationSeconds
 	root["loop_wait"] = *cluster.Spec.Patroni.SyncPeriodSeconds
 
+	// Override synchronous replication settings when they are in the spec.
+	// - https://patroni.readthedocs.io/en/latest/replication_modes.html
+	if sync := cluster.Spec.Patroni.Synchronous; sync != nil {
+		root["synchronous_mode"] = sync.Enabled
+		root["synchronous_mode_strict"] = sync.Enabled && sync.Strict
+		if sync.NodeCount != nil {
+			root["synchronous_node_count"] = *sync.NodeCount
+		}
+	}
+
 	// Copy the "postgresql" section before making any changes.
 	postgresql := map[string]any{
 		// TODO(cbandy): explain this. requires an archive, perhaps.
@@ -223,6 +257,13 @@ func DynamicConfiguration(
 			parameters[k] = v
 		}
 	}
+	// Copy typed parameters from the spec over the section above. Those that
+	// are not valid for this version of PostgreSQL are skipped.
+	for k, v := range cluster.Spec.Config.Parameters {
+		if acceptParameter(cluster.Spec.PostgresVersion, k, v) {
+			parameters[strings.ToLower(k)] = parameterValue(v)
+		}
+	}
 	// Override the above with mandatory parameters.
 	if pgParameters.Mandatory != nil {
 		for k, v := range pgParameters.Mandatory.AsMap() {
@@ -319,6 +360,140 @@ func DynamicConfiguration(
 	return root
 }
 
+// parameterValue returns value as a number or string that can be marshaled
+// into Patroni configuration.
+func parameterValue(value intstr.IntOrString) any {
+	if value.Type == intstr.Int {
+		return value.IntVal
+	}
+	return value.StrVal
+}
+
+// acceptParameter returns true when a parameter should be passed to PostgreSQL.
+// Those the catalog does not know are passed along as-is; those it knows must
+// have a valid value for version.
+func acceptParameter(version int, name string, value intstr.IntOrString) bool {
+	err := postgres.ValidateParameter(version, name, value)
+	return err == nil || errors.Is(err, postgres.ErrUnrecognizedParameter)
+}
+
+// instanceParameters returns the PostgreSQL parameters of instance that can be
+// set in Patroni local configuration. Invalid parameters, mandatory parameters,
+// and those that must be the same on every instance are omitted.
+func instanceParameters(
+	cluster *v1beta1.PostgresCluster, instance *v1beta1.PostgresInstanceSetSpec,
+	pgParameters postgres.Parameters,
+) map[string]any {
+	parameters := make(map[string]any, len(instance.Parameters))
+
+	// Local configuration takes precedence over dynamic configuration, so
+	// tune only the parameters that are not specified for the whole cluster.
+	if tuned := postgres.InstanceTuningParameters(cluster, instance); len(tuned) > 0 {
+		specified := sets.New[string]()
+		for name := range cluster.Spec.Config.Parameters {
+			specified.Insert(strings.ToLower(name))
+		}
+		if cluster.Spec.Patroni != nil {
+			if section, ok := cluster.Spec.Patroni.DynamicConfiguration["postgresql"].(map[string]any); ok {
+				if section, ok := section["parameters"].(map[string]any); ok {
+					for name := range section {
+						specified.Insert(strings.ToLower(name))
+					}
+				}
+			}
+		}
+		for name, value := range tuned {
+			if !specified.Has(name) &&
+				(pgParameters.Mandatory == nil || !pgParameters.Mandatory.Has(name)) {
+				parameters[name] = value
+			}
+		}
+	}
+
+	for name, value := range instance.Parameters {
+		if !acceptParameter(cluster.Spec.PostgresVersion, name, value) {
+			continue
+		}
+
+		// All parameter names are case-insensitive.
+		// -- https://www.postgresql.org/docs/current/config-setting.html
+		name = strings.ToLower(name)
+
+		if clusterOnlyParameters.Has(name) ||
+			(pgParameters.Mandatory != nil && pgParameters.Mandatory.Has(name)) {
+			continue
+		}
+		parameters[name] = parameterValue(value)
+	}
+	return parameters
+}
+
+// PendingRestartParameters compares the "postgresql.parameters" sections of
+// two dynamic configurations and returns the sorted names of parameters that
+// changed and take effect only after PostgreSQL restarts.
+func PendingRestartParameters(version int, current, desired map[string]any) []string {
+	section := func(root map[string]any) map[string]string {
+		out := make(map[string]string)
+		if postgresql, ok := root["postgresql"].(map[string]any); ok {
+			if parameters, ok := postgresql["parameters"].(map[string]any); ok {
+				for k, v := range parameters {
+					out[strings.ToLower(k)] = fmt.Sprint(v)
+				}
+			}
+		}
+		return out
+	}
+
+	return PendingRestartInstanceParameters(version, section(current), section(desired))
+}
+
+// PendingRestartInstanceParameters compares two sets of parameters in effect
+// for an instance set, such as those of [InstanceParameters], and returns the
+// sorted names of parameters that changed and take effect only after
+// PostgreSQL restarts.
+func PendingRestartInstanceParameters(version int, before, after map[string]string) []string {
+	names := sets.New[string]()
+	for _, parameters := range []map[string]string{before, after} {
+		for name := range parameters {
+			if def, ok := postgres.LookupParameter(version, name); ok &&
+				def.RequiresRestart() && before[name] != after[name] {
+				names.Insert(name)
+			}
+		}
+	}
+	return sets.List(names)
+}
+
+// InstanceParameters returns the PostgreSQL parameters in effect for instances
+// of instance: the cluster-wide parameters of cluster with the parameters of
+// instance merged on top.
+func InstanceParameters(
+	cluster *v1beta1.PostgresCluster, instance *v1beta1.PostgresInstanceSetSpec,
+	pgParameters postgres.Parameters,
+) map[string]string {
+	var configuration map[string]any
+	if cluster.Spec.Patroni != nil {
+		configuration = cluster.Spec.Patroni.DynamicConfiguration
+	}
+
+	effective := make(map[string]string)
+	root := DynamicConfiguration(cluster, configuration, postgres.HBAs{}, pgParameters)
+	if postgresql, ok := root["postgresql"].(map[string]any); ok {
+		if parameters, ok := postgresql["parameters"].(map[string]any); ok {
+			for name, value := range parameters {
+				effective[name] = fmt.Sprint(value)
+			}
+		}
+	}
+	for name, value := range instanceParameters(cluster, instance, pgParameters) {
+		effective[name] = fmt.Sprint(value)
+	}
+	if instance.RecoveryMinApplyDelay != nil {
+		effective["recovery_min_apply_delay"] = *instance.RecoveryMinApplyDelay
+	}
+	return effective
+}
+
 // instanceEnvironment returns the environment variables needed by Patroni's
 // instance container.
 func instanceEnvironment(
@@ -476,7 +651,7 @@ func instanceConfigFiles(cluster, instance *corev1.ConfigMap) []corev1.VolumePro
 // instanceYAML returns Patroni settings that apply to instance.
 func instanceYAML(
 	cluster *v1beta1.PostgresCluster, instance *v1beta1.PostgresInstanceSetSpec,
-	pgbackrestReplicaCreateCommand []string,
+	pgParameters postgres.Parameters, pgbackrestReplicaCreateCommand []string,
 ) (string, error) {
 	root := map[string]any{
 		// Missing here is "name" which cannot be known until the instance Pod is
@@ -507,6 +682,27 @@ func instanceYAML(
 		},
 	}
 
+	// Patroni promotes the replica with the highest "failover_priority" among
+	// those that are equally healthy. Zero is the same as "nofailover" which
+	// also applies to versions of Patroni that do not understand the former.
+	// - https://patroni.readthedocs.io/en/latest/yaml_configuration.html#tags
+	if instance.FailoverPriority != nil {
+		tags := root["tags"].(map[string]any)
+		tags["failover_priority"] = *instance.FailoverPriority
+		tags["nofailover"] = *instance.FailoverPriority == 0
+	}
+
+	// Delayed instances are far behind the primary by design. Keep them from
+	// being promoted, from being chosen as synchronous standbys, and from
+	// reporting healthy to replica load balancers.
+	// - https://patroni.readthedocs.io/en/latest/yaml_configuration.html#tags
+	if instance.RecoveryMinApplyDelay != nil {
+		tags := root["tags"].(map[string]any)
+		tags["nofailover"] = true
+		tags["noloadbalance"] = true
+		tags["nosync"] = true
+	}
+
 	postgresql := map[string]any{
 		// TODO(cbandy): "bin_dir"
 
@@ -575,6 +771,24 @@ func instanceYAML(
 	// method? This is a list and cannot be merged.
 	postgresql["create_replica_methods"] = methods
 
+	// Override cluster-wide PostgreSQL parameters with those of the instance
+	// set. Patroni merges local "postgresql.parameters" on top of its dynamic
+	// configuration.
+	// - https://patroni.readthedocs.io/en/latest/dynamic_configuration.html
+	if parameters := instanceParameters(cluster, instance, pgParameters); len(parameters) > 0 {
+		postgresql["parameters"] = parameters
+	}
+
+	// Instances in a delayed set wait before applying WAL from the primary.
+	// Patroni writes "postgresql.recovery_conf" to the recovery configuration
+	// of replicas only, so the delay never applies once an instance is primary.
+	// - https://patroni.readthedocs.io/en/latest/yaml_configuration.html#postgresql
+	if instance.RecoveryMinApplyDelay != nil {
+		postgresql["recovery_conf"] = map[string]any{
+			"recovery_min_apply_delay": *instance.RecoveryMinApplyDelay,
+		}
+	}
+
 	if !ClusterBootstrapped(cluster) {
 		isRestore := (cluster.Status.PGBackRest != nil && cluster.Status.PGBackRest.Restore != nil)
 		isDataSource := (cluster.Spec.DataSource != nil && cluster.Spec.DataSource.Volumes != nil &&
@@ -13,7 +13,9 @@ import (
 
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/yaml"
 
 	"github.com/crunchydata/postgres-operator/internal/initialize"
@@ -216,6 +218,66 @@ func TestDynamicConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "top-level: synchronous spec overrides input",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					Patroni: &v1beta1.PatroniSpec{
+						Synchronous: &v1beta1.PatroniSynchronous{
+							Enabled: true,
+							Strict:  true,
+						},
+					},
+				},
+			},
+			input: map[string]any{
+				"synchronous_mode":       false,
+				"synchronous_node_count": 5,
+			},
+			expected: map[string]any{
+				"loop_wait":               int32(10),
+				"ttl":                     int32(30),
+				"synchronous_mode":        true,
+				"synchronous_mode_strict": true,
+				"synchronous_node_count":  int32(1),
+				"postgresql": map[string]any{
+					"parameters":    map[string]any{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
+		{
+			name: "top-level: synchronous spec disabled",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					Patroni: &v1beta1.PatroniSpec{
+						Synchronous: &v1beta1.PatroniSynchronous{
+							Enabled:   false,
+							Strict:    true,
+							NodeCount: initialize.Int32(2),
+						},
+					},
+				},
+			},
+			input: map[string]any{
+				"synchronous_mode": true,
+			},
+			expected: map[string]any{
+				"loop_wait":               int32(10),
+				"ttl":                     int32(30),
+				"synchronous_mode":        false,
+				"synchronous_mode_strict": false,
+				"synchronous_node_count":  int32(2),
+				"postgresql": map[string]any{
+					"parameters":    map[string]any{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
 		{
 			name: "postgresql: wrong-type is ignored",
 			input: map[string]any{
@@ -355,6 +417,51 @@ func TestDynamicConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "postgresql.parameters: spec overrides input",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					Config: v1beta1.PostgresAdditionalConfig{
+						Parameters: map[string]intstr.IntOrString{
+							"Work_Mem":        intstr.FromString("64MB"),
+							"max_connections": intstr.FromInt(200),
+							"ssl":             intstr.FromString("off"),
+							"not_a_parameter": intstr.FromString("passed"),
+							"jit":             intstr.FromString("invalid"),
+						},
+					},
+				},
+			},
+			input: map[string]any{
+				"postgresql": map[string]any{
+					"parameters": map[string]any{
+						"work_mem": "4MB",
+						"another":  5,
+					},
+				},
+			},
+			params: postgres.Parameters{
+				Mandatory: parameters(map[string]string{
+					"ssl": "on",
+				}),
+			},
+			expected: map[string]any{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]any{
+					"parameters": map[string]any{
+						"work_mem":        "64MB",
+						"another":         5,
+						"max_connections": int32(200),
+						"not_a_parameter": "passed",
+						"ssl":             "on",
+					},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
 		{
 			name: "postgresql.parameters: mandatory shared_preload_libraries",
 			input: map[string]any{
@@ -861,7 +968,7 @@ func TestInstanceYAML(t *testing.T) {
 	cluster := &v1beta1.PostgresCluster{Spec: v1beta1.PostgresClusterSpec{PostgresVersion: 12}}
 	instance := new(v1beta1.PostgresInstanceSetSpec)
 
-	data, err := instanceYAML(cluster, instance, nil)
+	data, err := instanceYAML(cluster, instance, postgres.Parameters{}, nil)
 	assert.NilError(t, err)
 	assert.Equal(t, data, strings.Trim(`
 # Generated by postgres-operator. DO NOT EDIT.
@@ -884,7 +991,7 @@ restapi: {}
 tags: {}
 	`, "\t\n")+"\n")
 
-	dataWithReplicaCreate, err := instanceYAML(cluster, instance, []string{"some", "backrest", "cmd"})
+	dataWithReplicaCreate, err := instanceYAML(cluster, instance, postgres.Parameters{}, []string{"some", "backrest", "cmd"})
 	assert.NilError(t, err)
 	assert.Equal(t, dataWithReplicaCreate, strings.Trim(`
 # Generated by postgres-operator. DO NOT EDIT.
@@ -924,7 +1031,7 @@ tags: {}
 		},
 	}
 
-	datawithTDE, err := instanceYAML(cluster, instance, nil)
+	datawithTDE, err := instanceYAML(cluster, instance, postgres.Parameters{}, nil)
 	assert.NilError(t, err)
 	assert.Equal(t, datawithTDE, strings.Trim(`
 # Generated by postgres-operator. DO NOT EDIT.
@@ -948,6 +1055,238 @@ restapi: {}
 tags: {}
 	`, "\t\n")+"\n")
 
+	t.Run("Parameters", func(t *testing.T) {
+		cluster := &v1beta1.PostgresCluster{Spec: v1beta1.PostgresClusterSpec{PostgresVersion: 12}}
+		cluster.Status.Patroni.SystemIdentifier = "some-identifier"
+
+		parameters := postgres.NewParameters()
+		instance := new(v1beta1.PostgresInstanceSetSpec)
+		instance.Parameters = map[string]intstr.IntOrString{
+			"Work_Mem":             intstr.FromString("64MB"),
+			"max_parallel_workers": intstr.FromInt(16),
+			"max_connections":      intstr.FromInt(500),
+			"ssl":                  intstr.FromString("off"),
+		}
+
+		data, err := instanceYAML(cluster, instance, parameters, nil)
+		assert.NilError(t, err)
+		assert.Equal(t, data, strings.Trim(`
+# Generated by postgres-operator. DO NOT EDIT.
+# Your changes will not be saved.
+kubernetes: {}
+postgresql:
+  basebackup:
+  - waldir=/pgdata/pg12_wal
+  create_replica_methods:
+  - basebackup
+  parameters:
+    max_parallel_workers: 16
+    work_mem: 64MB
+  pgpass: /tmp/.pgpass
+  use_unix_socket: true
+restapi: {}
+tags: {}
+		`, "\t\n")+"\n")
+	})
+
+	t.Run("Delayed", func(t *testing.T) {
+		cluster := &v1beta1.PostgresCluster{Spec: v1beta1.PostgresClusterSpec{PostgresVersion: 12}}
+		cluster.Status.Patroni.SystemIdentifier = "some-identifier"
+
+		instance := new(v1beta1.PostgresInstanceSetSpec)
+		instance.RecoveryMinApplyDelay = initialize.String("4h")
+
+		data, err := instanceYAML(cluster, instance, postgres.Parameters{}, nil)
+		assert.NilError(t, err)
+		assert.Equal(t, data, strings.Trim(`
+# Generated by postgres-operator. DO NOT EDIT.
+# Your changes will not be saved.
+kubernetes: {}
+postgresql:
+  basebackup:
+  - waldir=/pgdata/pg12_wal
+  create_replica_methods:
+  - basebackup
+  pgpass: /tmp/.pgpass
+  recovery_conf:
+    recovery_min_apply_delay: 4h
+  use_unix_socket: true
+restapi: {}
+tags:
+  nofailover: true
+  noloadbalance: true
+  nosync: true
+		`, "\t\n")+"\n")
+	})
+
+	t.Run("FailoverPriority", func(t *testing.T) {
+		cluster := &v1beta1.PostgresCluster{Spec: v1beta1.PostgresClusterSpec{PostgresVersion: 12}}
+		cluster.Status.Patroni.SystemIdentifier = "some-identifier"
+
+		instance := new(v1beta1.PostgresInstanceSetSpec)
+		instance.FailoverPriority = initialize.Int32(5)
+
+		data, err := instanceYAML(cluster, instance, postgres.Parameters{}, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, cmp.Contains(data, "\ntags:\n  failover_priority: 5\n  nofailover: false\n"))
+
+		instance.FailoverPriority = initialize.Int32(0)
+
+		data, err = instanceYAML(cluster, instance, postgres.Parameters{}, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, cmp.Contains(data, "\ntags:\n  failover_priority: 0\n  nofailover: true\n"))
+
+		// Delayed instances are never promoted.
+		instance.FailoverPriority = initialize.Int32(5)
+		instance.RecoveryMinApplyDelay = initialize.String("4h")
+
+		data, err = instanceYAML(cluster, instance, postgres.Parameters{}, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, cmp.Contains(data, "\ntags:\n  failover_priority: 5\n  nofailover: true\n"))
+	})
+}
+
+func TestInstanceParameters(t *testing.T) {
+	t.Parallel()
+
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Default()
+	cluster.Spec.PostgresVersion = 16
+	cluster.Spec.Patroni.DynamicConfiguration = map[string]any{
+		"postgresql": map[string]any{
+			"parameters": map[string]any{
+				"hot_standby_feedback": false,
+				"work_mem":             "4MB",
+			},
+		},
+	}
+
+	parameters := postgres.NewParameters()
+	instance := new(v1beta1.PostgresInstanceSetSpec)
+
+	t.Run("ClusterWide", func(t *testing.T) {
+		effective := InstanceParameters(cluster, instance, parameters)
+		assert.Equal(t, effective["hot_standby_feedback"], "false")
+		assert.Equal(t, effective["work_mem"], "4MB")
+		assert.Equal(t, effective["jit"], "off")
+		assert.Equal(t, effective["ssl"], "on")
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		instance := instance.DeepCopy()
+		instance.Parameters = map[string]intstr.IntOrString{
+			"hot_standby_feedback": intstr.FromString("on"),
+			"max_connections":      intstr.FromInt(1000),
+			"ssl":                  intstr.FromString("off"),
+			"work_mem":             intstr.FromString("256MB"),
+		}
+
+		effective := InstanceParameters(cluster, instance, parameters)
+		assert.Equal(t, effective["hot_standby_feedback"], "on")
+		assert.Equal(t, effective["work_mem"], "256MB")
+		assert.Equal(t, effective["jit"], "off")
+
+		// Mandatory and cluster-wide parameters cannot be overridden.
+		assert.Equal(t, effective["ssl"], "on")
+		_, ok := effective["max_connections"]
+		assert.Assert(t, !ok)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		instance := instance.DeepCopy()
+		instance.Parameters = map[string]intstr.IntOrString{
+			"jit":             intstr.FromString("sometimes"),
+			"not_a_parameter": intstr.FromString("value"),
+			"work_mem":        intstr.FromString("lots"),
+		}
+
+		effective := InstanceParameters(cluster, instance, parameters)
+		assert.Equal(t, effective["jit"], "off")
+		assert.Equal(t, effective["work_mem"], "4MB")
+
+		// Parameters that are not in the catalog are passed along.
+		assert.Equal(t, effective["not_a_parameter"], "value")
+	})
+
+	t.Run("Delayed", func(t *testing.T) {
+		instance := instance.DeepCopy()
+		instance.RecoveryMinApplyDelay = initialize.String("4h")
+
+		effective := InstanceParameters(cluster, instance, parameters)
+		assert.Equal(t, effective["recovery_min_apply_delay"], "4h")
+	})
+
+	t.Run("Tuned", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Config.TuningProfile = "mixed"
+		cluster.Spec.Config.Parameters = map[string]intstr.IntOrString{
+			"Effective_Cache_Size": intstr.FromString("1GB"),
+		}
+
+		instance := instance.DeepCopy()
+		instance.Resources.Limits = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		}
+		instance.Parameters = map[string]intstr.IntOrString{
+			"maintenance_work_mem": intstr.FromString("128MB"),
+		}
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{*instance}
+
+		effective := InstanceParameters(cluster, instance, parameters)
+		assert.Equal(t, effective["shared_buffers"], "262144kB")
+
+		// Parameters specified for the cluster or the instance set take
+		// precedence over tuning.
+		assert.Equal(t, effective["effective_cache_size"], "1GB")
+		assert.Equal(t, effective["maintenance_work_mem"], "128MB")
+		assert.Equal(t, effective["work_mem"], "4MB")
+	})
+}
+
+func TestPendingRestartParameters(t *testing.T) {
+	t.Parallel()
+
+	config := func(parameters map[string]any) map[string]any {
+		return map[string]any{
+			"postgresql": map[string]any{"parameters": parameters},
+		}
+	}
+
+	assert.Assert(t, len(PendingRestartParameters(16, nil, nil)) == 0)
+	assert.Assert(t, len(PendingRestartParameters(16,
+		config(map[string]any{"shared_buffers": "1GB"}),
+		config(map[string]any{"shared_buffers": "1GB"}),
+	)) == 0)
+
+	// JSON from DCS has float64 numbers while the desired value is int32.
+	assert.Assert(t, len(PendingRestartParameters(16,
+		config(map[string]any{"max_connections": float64(100)}),
+		config(map[string]any{"max_connections": int32(100)}),
+	)) == 0)
+
+	assert.DeepEqual(t, PendingRestartParameters(16,
+		config(map[string]any{
+			"max_connections": float64(100),
+			"shared_buffers":  "1GB",
+			"work_mem":        "4MB",
+			"wal_level":       "logical",
+		}),
+		config(map[string]any{
+			"max_connections": int32(200),
+			"shared_buffers":  "1GB",
+			"work_mem":        "8MB",
+			"Huge_Pages":      "on",
+		}),
+	), []string{"huge_pages", "max_connections", "wal_level"})
+}
+
+func TestPendingRestartInstanceParameters(t *testing.T) {
+	assert.DeepEqual(t, PendingRestartInstanceParameters(16, nil, nil), []string{})
+
+	assert.DeepEqual(t, PendingRestartInstanceParameters(16,
+		map[string]string{"shared_buffers": "128MB", "work_mem": "4MB"},
+		map[string]string{"shared_buffers": "1GB", "work_mem": "8MB", "huge_pages": "on"},
+	), []string{"huge_pages", "shared_buffers"})
 }
 
 func TestPGBackRestCreateReplicaCommand(t *testing.T) {
@@ -957,7 +1296,7 @@ func TestPGBackRestCreateReplicaCommand(t *testing.T) {
 	cluster := new(v1beta1.PostgresCluster)
 	instance := new(v1beta1.PostgresInstanceSetSpec)
 
-	data, err := instanceYAML(cluster, instance, []string{"some", "backrest", "cmd"})
+	data, err := instanceYAML(cluster, instance, postgres.Parameters{}, []string{"some", "backrest", "cmd"})
 	assert.NilError(t, err)
 
 	var parsed struct {
@@ -47,6 +47,7 @@ func ClusterConfigMap(ctx context.Context,
 func InstanceConfigMap(ctx context.Context,
 	inCluster *v1beta1.PostgresCluster,
 	inInstanceSpec *v1beta1.PostgresInstanceSetSpec,
+	inParameters postgres.Parameters,
 	outInstanceConfigMap *corev1.ConfigMap,
 ) error {
 	var err error
@@ -56,14 +57,14 @@ func InstanceConfigMap(ctx context.Context,
 	command := pgbackrest.ReplicaCreateCommand(inCluster, inInstanceSpec)
 
 	outInstanceConfigMap.Data[configMapFileKey], err = instanceYAML(
-		inCluster, inInstanceSpec, command)
+		inCluster, inInstanceSpec, inParameters, command)
 
 	return err
 }
 
 // InstanceCertificates populates the shared Secret with certificates needed to run Patroni.
 func InstanceCertificates(ctx context.Context,
-	inRoot pki.Certificate, inDNS pki.Certificate,
+	inRoot pki.CertificateBundle, inDNS pki.Certificate,
 	inDNSKey pki.PrivateKey, outInstanceCertificates *corev1.Secret,
 ) error {
 	initialize.Map(&outInstanceCertificates.Data)
@@ -79,7 +79,7 @@ func TestReconcileInstanceCertificates(t *testing.T) {
 	secret := new(corev1.Secret)
 
 	assert.NilError(t, InstanceCertificates(ctx,
-		root.Certificate, leaf.Certificate, leaf.PrivateKey, secret))
+		root.Bundle(), leaf.Certificate, leaf.PrivateKey, secret))
 
 	assert.DeepEqual(t, secret.Data["patroni.ca-roots"], dataCA)
 	assert.DeepEqual(t, secret.Data["patroni.crt-combined"], dataCert)
@@ -87,7 +87,7 @@ func TestReconcileInstanceCertificates(t *testing.T) {
 	// No change when called again.
 	before := secret.DeepCopy()
 	assert.NilError(t, InstanceCertificates(ctx,
-		root.Certificate, leaf.Certificate, leaf.PrivateKey, secret))
+		root.Bundle(), leaf.Certificate, leaf.PrivateKey, secret))
 	assert.DeepEqual(t, secret, before)
 }
 
@@ -98,15 +98,15 @@ func TestInstanceConfigMap(t *testing.T) {
 	cluster := new(v1beta1.PostgresCluster)
 	instance := new(v1beta1.PostgresInstanceSetSpec)
 	config := new(corev1.ConfigMap)
-	data, _ := instanceYAML(cluster, instance, nil)
+	data, _ := instanceYAML(cluster, instance, postgres.Parameters{}, nil)
 
-	assert.NilError(t, InstanceConfigMap(ctx, cluster, instance, config))
+	assert.NilError(t, InstanceConfigMap(ctx, cluster, instance, postgres.Parameters{}, config))
 
 	assert.DeepEqual(t, config.Data["patroni.yaml"], data)
 
 	// No change when called again.
 	before := config.DeepCopy()
-	assert.NilError(t, InstanceConfigMap(ctx, cluster, instance, config))
+	assert.NilError(t, InstanceConfigMap(ctx, cluster, instance, postgres.Parameters{}, config))
 	assert.DeepEqual(t, config, before)
 }
 
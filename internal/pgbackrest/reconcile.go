@@ -400,7 +400,7 @@ func AddServerToRepoPod(
 // InstanceCertificates populates the shared Secret with certificates needed to run pgBackRest.
 func InstanceCertificates(ctx context.Context,
 	inCluster *v1beta1.PostgresCluster,
-	inRoot pki.Certificate,
+	inRoot pki.CertificateBundle,
 	inDNS pki.Certificate, inDNSKey pki.PrivateKey,
 	outInstanceCertificates *corev1.Secret,
 ) error {
@@ -501,7 +501,7 @@ func RestoreConfig(
 func Secret(ctx context.Context,
 	inCluster *v1beta1.PostgresCluster,
 	inRepoHost *appsv1.StatefulSet,
-	inRoot *pki.RootCertificateAuthority,
+	inRoot pki.Issuer,
 	inSecret *corev1.Secret,
 	outSecret *corev1.Secret,
 ) error {
@@ -532,7 +532,7 @@ func Secret(ctx context.Context,
 		}
 
 		if err == nil {
-			outSecret.Data[certAuthoritySecretKey], err = certFile(inRoot.Certificate)
+			outSecret.Data[certAuthoritySecretKey], err = certFile(inRoot.Bundle())
 		}
 		if err == nil {
 			outSecret.Data[certClientPrivateKeySecretKey], err = certFile(leaf.PrivateKey)
@@ -39,7 +39,7 @@ func ConfigMap(
 // Secret populates the PgBouncer Secret.
 func Secret(ctx context.Context,
 	inCluster *v1beta1.PostgresCluster,
-	inRoot *pki.RootCertificateAuthority,
+	inRoot pki.Issuer,
 	inSecret *corev1.Secret,
 	inService *corev1.Service,
 	outSecret *corev1.Secret,
@@ -88,7 +88,7 @@ func Secret(ctx context.Context,
 		}
 
 		if err == nil {
-			outSecret.Data[certFrontendAuthoritySecretKey], err = inRoot.Certificate.MarshalText()
+			outSecret.Data[certFrontendAuthoritySecretKey], err = inRoot.Bundle().MarshalText()
 		}
 		if err == nil {
 			outSecret.Data[certFrontendPrivateKeySecretKey], err = leaf.PrivateKey.MarshalText()
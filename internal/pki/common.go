@@ -5,8 +5,7 @@
 package pki
 
 import (
-	"crypto/ecdsa"
-	"crypto/elliptic"
+	"crypto"
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -14,34 +13,24 @@ import (
 	"time"
 )
 
-// certificateSignatureAlgorithm is ECDSA with SHA-384, the recommended
-// signature algorithm with the P-256 curve.
-const certificateSignatureAlgorithm = x509.ECDSAWithSHA384
-
 // currentTime returns the current local time. It is a variable so it can be
 // replaced during testing.
 var currentTime = time.Now
 
-// generateKey returns a random ECDSA key using a P-256 curve. This curve is
-// roughly equivalent to an RSA 3072-bit key but requires less bits to achieve
-// the equivalent cryptographic strength. Additionally, ECDSA is FIPS 140-2
-// compliant.
-func generateKey() (*ecdsa.PrivateKey, error) {
-	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
-}
-
 // generateSerialNumber returns a random 128-bit integer.
 func generateSerialNumber() (*big.Int, error) {
 	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
 }
 
+// leafStartValid is how long before their creation leaf certificates are
+// valid, to tolerate clocks that disagree.
+const leafStartValid = time.Hour * -1
+
 func generateLeafCertificate(
-	signer *x509.Certificate, signerPrivate *ecdsa.PrivateKey,
-	signeePublic *ecdsa.PublicKey, serialNumber *big.Int,
-	commonName string, dnsNames []string,
+	signer *x509.Certificate, signerPrivate crypto.Signer,
+	signeePublic crypto.PublicKey, serialNumber *big.Int,
+	lifetime time.Duration, commonName string, dnsNames []string,
 ) (*x509.Certificate, error) {
-	const leafExpiration = time.Hour * 24 * 365
-	const leafStartValid = time.Hour * -1
 
 	now := currentTime()
 	template := &x509.Certificate{
@@ -49,9 +38,9 @@ func generateLeafCertificate(
 		DNSNames:              dnsNames,
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
 		NotBefore:             now.Add(leafStartValid),
-		NotAfter:              now.Add(leafExpiration),
+		NotAfter:              now.Add(lifetime),
 		SerialNumber:          serialNumber,
-		SignatureAlgorithm:    certificateSignatureAlgorithm,
+		SignatureAlgorithm:    signatureAlgorithm(signerPrivate.Public()),
 		Subject: pkix.Name{
 			CommonName: commonName,
 		},
@@ -65,7 +54,7 @@ func generateLeafCertificate(
 }
 
 func generateRootCertificate(
-	privateKey *ecdsa.PrivateKey, serialNumber *big.Int,
+	privateKey crypto.Signer, serialNumber *big.Int,
 ) (*x509.Certificate, error) {
 	const rootCommonName = "postgres-operator-ca"
 	const rootExpiration = time.Hour * 24 * 365 * 10
@@ -80,7 +69,7 @@ func generateRootCertificate(
 		NotBefore:             now.Add(rootStartValid),
 		NotAfter:              now.Add(rootExpiration),
 		SerialNumber:          serialNumber,
-		SignatureAlgorithm:    certificateSignatureAlgorithm,
+		SignatureAlgorithm:    signatureAlgorithm(privateKey.Public()),
 		Subject: pkix.Name{
 			CommonName: rootCommonName,
 		},
@@ -8,6 +8,8 @@
 //
 // NewRootCertificateAuthority() creates a new root CA.
 // GenerateLeafCertificate() creates a new leaf certificate.
+// NewCertificateAuthority() loads an authority, such as an intermediate CA.
+// Policy controls the key algorithm and lifetime of generated certificates.
 //
 // Certificate and PrivateKey are primitives that can be marshaled.
 package pki
@@ -5,7 +5,9 @@
 package pki
 
 import (
+	"crypto"
 	"crypto/ecdsa"
+	"crypto/rsa"
 	"crypto/x509"
 	"encoding"
 	"encoding/pem"
@@ -20,6 +22,14 @@ const (
 	// pemLabelECDSAKey is the textual encoding label for an elliptic curve private key
 	// according to RFC 5915. See https://tools.ietf.org/html/rfc5915.
 	pemLabelECDSAKey = "EC PRIVATE KEY"
+
+	// pemLabelRSAKey is the textual encoding label for an RSA private key
+	// according to RFC 8017. See https://tools.ietf.org/html/rfc8017.
+	pemLabelRSAKey = "RSA PRIVATE KEY"
+
+	// pemLabelPrivateKey is the textual encoding label for a PKCS #8 private
+	// key of any algorithm according to RFC 7468, such as Ed25519.
+	pemLabelPrivateKey = "PRIVATE KEY"
 )
 
 var (
@@ -56,25 +66,70 @@ func (c *Certificate) UnmarshalText(data []byte) error {
 	return err
 }
 
+var (
+	_ encoding.TextMarshaler   = CertificateBundle{}
+	_ encoding.TextUnmarshaler = (*CertificateBundle)(nil)
+)
+
+// MarshalText returns the PEM encoding of every certificate in b, in order.
+func (b CertificateBundle) MarshalText() ([]byte, error) {
+	var out []byte
+	for i := range b {
+		text, err := b[i].MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, text...)
+	}
+	return out, nil
+}
+
+// UnmarshalText populates b from consecutive PEM encodings of certificates.
+func (b *CertificateBundle) UnmarshalText(data []byte) error {
+	var bundle CertificateBundle
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		var c Certificate
+		if err := c.UnmarshalText(pem.EncodeToMemory(block)); err != nil {
+			return err
+		}
+		bundle = append(bundle, c)
+	}
+	*b = bundle
+	return nil
+}
+
 var (
 	_ encoding.TextMarshaler   = PrivateKey{}
 	_ encoding.TextMarshaler   = (*PrivateKey)(nil)
 	_ encoding.TextUnmarshaler = (*PrivateKey)(nil)
 )
 
-// MarshalText returns a PEM encoding of k that OpenSSL understands.
+// MarshalText returns a PEM encoding of k that OpenSSL understands. ECDSA and
+// RSA keys have their own encodings; others are encoded as PKCS #8.
 func (k PrivateKey) MarshalText() ([]byte, error) {
-	if k.ecdsa == nil {
-		k.ecdsa = new(ecdsa.PrivateKey)
+	var der []byte
+	var err error
+	var label string
+
+	switch key := k.signer.(type) {
+	case nil:
+		_, err = x509.MarshalECPrivateKey(new(ecdsa.PrivateKey))
+	case *ecdsa.PrivateKey:
+		label = pemLabelECDSAKey
+		der, err = x509.MarshalECPrivateKey(key)
+	case *rsa.PrivateKey:
+		label = pemLabelRSAKey
+		der = x509.MarshalPKCS1PrivateKey(key)
+	default:
+		label = pemLabelPrivateKey
+		der, err = x509.MarshalPKCS8PrivateKey(key)
 	}
-
-	der, err := x509.MarshalECPrivateKey(k.ecdsa)
 	if err != nil {
 		return nil, err
 	}
 
 	return pem.EncodeToMemory(&pem.Block{
-		Type:  pemLabelECDSAKey,
+		Type:  label,
 		Bytes: der,
 	}), nil
 }
@@ -83,13 +138,30 @@ func (k PrivateKey) MarshalText() ([]byte, error) {
 func (k *PrivateKey) UnmarshalText(data []byte) error {
 	block, _ := pem.Decode(data)
 
-	if block == nil || block.Type != pemLabelECDSAKey {
+	if block == nil {
+		return fmt.Errorf("not a PEM-encoded private key")
+	}
+
+	var key any
+	var err error
+
+	switch block.Type {
+	case pemLabelECDSAKey:
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case pemLabelRSAKey:
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case pemLabelPrivateKey:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
 		return fmt.Errorf("not a PEM-encoded private key")
 	}
 
-	key, err := x509.ParseECPrivateKey(block.Bytes)
 	if err == nil {
-		k.ecdsa = key
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return fmt.Errorf("unsupported private key type %T", key)
+		}
+		k.signer = signer
 	}
 	return err
 }
@@ -0,0 +1,55 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pki
+
+import "errors"
+
+var errInvalidAuthority = errors.New(
+	"certificate authority is expired, cannot issue certificates, or does not match its private key")
+
+// Issuer issues leaf certificates. Components that need certificates accept
+// an Issuer so that the authority behind it can come from anywhere.
+type Issuer interface {
+	// Bundle returns the authorities that verify leaf certificates of this
+	// Issuer, starting with the one that signs them.
+	Bundle() CertificateBundle
+
+	// RegenerateLeafWhenNecessary returns leaf when it is valid and has
+	// commonName and dnsNames in its subject. Otherwise, it returns a new key
+	// and certificate.
+	RegenerateLeafWhenNecessary(
+		leaf *LeafCertificate, commonName string, dnsNames []string,
+	) (*LeafCertificate, error)
+}
+
+var _ Issuer = (*RootCertificateAuthority)(nil)
+
+// CertificateBundle is a list of certificates, such as a chain of authorities.
+type CertificateBundle []Certificate
+
+// NewCertificateAuthority returns the authority in PEM encoded certificate and
+// key, issued by the authorities in PEM encoded chain. It returns an error
+// when the authority is not valid according to this package's policies.
+func NewCertificateAuthority(certificate, key, chain []byte) (*RootCertificateAuthority, error) {
+	authority := &RootCertificateAuthority{}
+
+	err := authority.Certificate.UnmarshalText(certificate)
+	if err == nil {
+		err = authority.PrivateKey.UnmarshalText(key)
+	}
+	if err == nil && len(chain) > 0 {
+		err = authority.Chain.UnmarshalText(chain)
+	}
+
+	// A self-signed authority may appear in its own chain; remove it there.
+	if err == nil && len(authority.Chain) > 0 && authority.Chain[0].Equal(authority.Certificate) {
+		authority.Chain = authority.Chain[1:]
+	}
+	if err == nil && !RootIsValid(authority) {
+		err = errInvalidAuthority
+	}
+
+	return authority, err
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+// newAuthority returns an authority issued by parent or, when parent is nil,
+// a self-signed one. Unlike [NewRootCertificateAuthority], the self-signed
+// authority can issue other authorities.
+func newAuthority(t *testing.T, parent *RootCertificateAuthority) *RootCertificateAuthority {
+	key, err := Policy{}.generateKey()
+	assert.NilError(t, err)
+	serial, err := generateSerialNumber()
+	assert.NilError(t, err)
+
+	now := currentTime()
+	template := &x509.Certificate{
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		MaxPathLenZero:        parent != nil,
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "authority"},
+	}
+
+	authority := &RootCertificateAuthority{}
+	authority.PrivateKey.signer = key
+	signer, signerKey := template, key
+	if parent != nil {
+		authority.Chain = parent.Bundle()
+		signer, signerKey = parent.Certificate.x509, parent.PrivateKey.signer
+	}
+
+	bytes, err := x509.CreateCertificate(rand.Reader, template, signer, key.Public(), signerKey)
+	assert.NilError(t, err)
+	authority.Certificate.x509, err = x509.ParseCertificate(bytes)
+	assert.NilError(t, err)
+
+	return authority
+}
+
+func TestCertificateBundle(t *testing.T) {
+	root := newAuthority(t, nil)
+	intermediate := newAuthority(t, root)
+
+	text, err := intermediate.Bundle().MarshalText()
+	assert.NilError(t, err)
+	assert.Equal(t, strings.Count(string(text), "BEGIN CERTIFICATE"), 2)
+
+	var bundle CertificateBundle
+	assert.NilError(t, bundle.UnmarshalText(text))
+	assert.Equal(t, len(bundle), 2)
+	assert.Assert(t, bundle[0].Equal(intermediate.Certificate))
+	assert.Assert(t, bundle[1].Equal(root.Certificate))
+
+	assert.ErrorContains(t, bundle.UnmarshalText([]byte(
+		"-----BEGIN OTHER-----\nAA==\n-----END OTHER-----\n")), "not a PEM-encoded certificate")
+}
+
+func TestNewCertificateAuthority(t *testing.T) {
+	root, err := NewRootCertificateAuthority()
+	assert.NilError(t, err)
+
+	rootCert, _ := root.Certificate.MarshalText()
+	rootKey, _ := root.PrivateKey.MarshalText()
+
+	t.Run("SelfSigned", func(t *testing.T) {
+		authority, err := NewCertificateAuthority(rootCert, rootKey, rootCert)
+		assert.NilError(t, err)
+		assert.Assert(t, authority.Certificate.Equal(root.Certificate))
+		assert.Equal(t, len(authority.Chain), 0)
+	})
+
+	t.Run("Intermediate", func(t *testing.T) {
+		root := newAuthority(t, nil)
+		intermediate := newAuthority(t, root)
+		rootCert, _ := root.Certificate.MarshalText()
+		cert, _ := intermediate.Certificate.MarshalText()
+		key, _ := intermediate.PrivateKey.MarshalText()
+
+		authority, err := NewCertificateAuthority(cert, key, rootCert)
+		assert.NilError(t, err)
+		assert.Equal(t, len(authority.Bundle()), 2)
+
+		// Leaves of the intermediate are valid and verify using the bundle.
+		leaf, err := authority.RegenerateLeafWhenNecessary(nil, "leaf", nil)
+		assert.NilError(t, err)
+		assert.Assert(t, authority.leafIsValid(leaf))
+
+		roots := x509.NewCertPool()
+		roots.AddCert(root.Certificate.x509)
+		intermediates := x509.NewCertPool()
+		intermediates.AddCert(intermediate.Certificate.x509)
+		_, err = leaf.Certificate.x509.Verify(x509.VerifyOptions{
+			Roots: roots, Intermediates: intermediates,
+		})
+		assert.NilError(t, err)
+
+		// The same leaf is kept.
+		again, err := authority.RegenerateLeafWhenNecessary(leaf, "leaf", nil)
+		assert.NilError(t, err)
+		assert.Assert(t, again.Certificate.Equal(leaf.Certificate))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := NewCertificateAuthority(nil, rootKey, nil)
+		assert.ErrorContains(t, err, "certificate")
+
+		leaf, err := root.GenerateLeafCertificate("leaf", nil)
+		assert.NilError(t, err)
+		cert, _ := leaf.Certificate.MarshalText()
+		key, _ := leaf.PrivateKey.MarshalText()
+
+		_, err = NewCertificateAuthority(cert, key, nil)
+		assert.ErrorContains(t, err, "certificate authority is expired")
+
+		other, err := NewRootCertificateAuthority()
+		assert.NilError(t, err)
+		otherKey, _ := other.PrivateKey.MarshalText()
+
+		_, err = NewCertificateAuthority(rootCert, otherKey, nil)
+		assert.ErrorContains(t, err, "does not match its private key")
+	})
+}
@@ -5,7 +5,7 @@
 package pki
 
 import (
-	"crypto/ecdsa"
+	"crypto"
 	"crypto/x509"
 	"math/big"
 	"time"
@@ -17,8 +17,9 @@ const renewalRatio = 3
 // PKI Profile, RFC 5280.
 type Certificate struct{ x509 *x509.Certificate }
 
-// PrivateKey represents the private key of a Certificate.
-type PrivateKey struct{ ecdsa *ecdsa.PrivateKey }
+// PrivateKey represents the private key of a Certificate. It is ECDSA, RSA,
+// or Ed25519.
+type PrivateKey struct{ signer crypto.Signer }
 
 // Equal reports whether c and other have the same value.
 func (c Certificate) Equal(other Certificate) bool {
@@ -42,6 +43,33 @@ func (c Certificate) DNSNames() []string {
 	return append([]string{}, c.x509.DNSNames...)
 }
 
+// Issuer returns the distinguished name of the authority that signed the
+// certificate, or empty when there is no certificate.
+func (c Certificate) Issuer() string {
+	if c.x509 == nil {
+		return ""
+	}
+	return c.x509.Issuer.String()
+}
+
+// NotAfter returns the time when the certificate expires, or the zero time
+// when there is no certificate.
+func (c Certificate) NotAfter() time.Time {
+	if c.x509 == nil {
+		return time.Time{}
+	}
+	return c.x509.NotAfter
+}
+
+// Subject returns the distinguished name of the certificate subject, or empty
+// when there is no certificate.
+func (c Certificate) Subject() string {
+	if c.x509 == nil {
+		return ""
+	}
+	return c.x509.Subject.String()
+}
+
 // hasSubject checks that c has these values in its subject.
 func (c Certificate) hasSubject(commonName string, dnsNames []string) bool {
 	ok := c.x509 != nil &&
@@ -57,10 +85,20 @@ func (c Certificate) hasSubject(commonName string, dnsNames []string) bool {
 
 // Equal reports whether k and other have the same value.
 func (k PrivateKey) Equal(other PrivateKey) bool {
-	if k.ecdsa == nil || other.ecdsa == nil {
-		return k.ecdsa == other.ecdsa
+	if k.signer == nil || other.signer == nil {
+		return k.signer == nil && other.signer == nil
+	}
+	equal, ok := k.signer.(interface{ Equal(crypto.PrivateKey) bool })
+	return ok && equal.Equal(other.signer)
+}
+
+// signs reports whether k is the private key of public.
+func (k PrivateKey) signs(public crypto.PublicKey) bool {
+	if k.signer == nil {
+		return false
 	}
-	return k.ecdsa.Equal(other.ecdsa)
+	equal, ok := k.signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	return ok && equal.Equal(public)
 }
 
 // LeafCertificate is a certificate and private key pair that can be validated
@@ -75,20 +113,48 @@ type LeafCertificate struct {
 type RootCertificateAuthority struct {
 	Certificate Certificate
 	PrivateKey  PrivateKey
+
+	// Chain holds the authorities that issued Certificate when it is an
+	// intermediate authority. It is empty when Certificate is self-signed.
+	Chain CertificateBundle
+
+	// Trusted holds other authorities that should be trusted alongside this
+	// one, such as while it is being replaced. They do not sign certificates.
+	Trusted CertificateBundle
+
+	// Policy controls the keys and lifetimes of leaf certificates that this
+	// authority generates. Leaf certificates that do not follow it are
+	// regenerated.
+	Policy Policy
+}
+
+// Bundle returns the certificate of root followed by the authorities that
+// issued it and any others that are trusted. Leaf certificates of root can be
+// verified using these.
+func (root *RootCertificateAuthority) Bundle() CertificateBundle {
+	bundle := append(CertificateBundle{root.Certificate}, root.Chain...)
+	return append(bundle, root.Trusted...)
 }
 
 // NewRootCertificateAuthority generates a new key and self-signed certificate
 // for issuing other certificates.
 func NewRootCertificateAuthority() (*RootCertificateAuthority, error) {
-	var root RootCertificateAuthority
+	return Policy{}.NewRootCertificateAuthority()
+}
+
+// NewRootCertificateAuthority generates a new key of the algorithm in p and
+// a self-signed certificate for issuing other certificates. The authority
+// generates leaf certificates according to p.
+func (p Policy) NewRootCertificateAuthority() (*RootCertificateAuthority, error) {
+	root := RootCertificateAuthority{Policy: p}
 	var serial *big.Int
 
-	key, err := generateKey()
+	key, err := p.generateKey()
 	if err == nil {
 		serial, err = generateSerialNumber()
 	}
 	if err == nil {
-		root.PrivateKey.ecdsa = key
+		root.PrivateKey.signer = key
 		root.Certificate.x509, err = generateRootCertificate(key, serial)
 	}
 
@@ -124,9 +190,7 @@ func RootIsValid(root *RootCertificateAuthority) bool {
 		len(root.Certificate.x509.SubjectKeyId) > 0
 
 	// It is signed by this private key.
-	ok = ok &&
-		root.PrivateKey.ecdsa != nil &&
-		root.PrivateKey.ecdsa.PublicKey.Equal(root.Certificate.x509.PublicKey)
+	ok = ok && root.PrivateKey.signs(root.Certificate.x509.PublicKey)
 
 	return ok
 }
@@ -138,15 +202,19 @@ func (root *RootCertificateAuthority) GenerateLeafCertificate(
 	var leaf LeafCertificate
 	var serial *big.Int
 
-	key, err := generateKey()
+	if root.PrivateKey.signer == nil {
+		return &leaf, errInvalidAuthority
+	}
+
+	key, err := root.Policy.generateKey()
 	if err == nil {
 		serial, err = generateSerialNumber()
 	}
 	if err == nil {
-		leaf.PrivateKey.ecdsa = key
+		leaf.PrivateKey.signer = key
 		leaf.Certificate.x509, err = generateLeafCertificate(
-			root.Certificate.x509, root.PrivateKey.ecdsa, &key.PublicKey, serial,
-			commonName, dnsNames)
+			root.Certificate.x509, root.PrivateKey.signer, key.Public(), serial,
+			root.Policy.leafLifetime(), commonName, dnsNames)
 	}
 
 	return &leaf, err
@@ -182,24 +250,32 @@ func (root *RootCertificateAuthority) leafIsValid(leaf *LeafCertificate) bool {
 		!leaf.Certificate.x509.IsCA
 
 	// It is signed by this private key.
+	ok = ok && leaf.PrivateKey.signs(leaf.Certificate.x509.PublicKey)
+
+	// Its key and lifetime follow the policy of root. A leaf is regenerated
+	// when the policy changes to another algorithm or a shorter lifetime.
 	ok = ok &&
-		leaf.PrivateKey.ecdsa != nil &&
-		leaf.PrivateKey.ecdsa.PublicKey.Equal(leaf.Certificate.x509.PublicKey)
+		keyAlgorithmOf(leaf.Certificate.x509.PublicKey) == root.Policy.keyAlgorithm() &&
+		leaf.Certificate.x509.NotAfter.Sub(leaf.Certificate.x509.NotBefore) <=
+			root.Policy.leafLifetime()-leafStartValid
 
 	// It is not yet past the "renewal by" time,
 	// as defined by the before and after times of the certificate's expiration
-	// and the default ratio
+	// and the renewal threshold of the policy
 	ok = ok && isBeforeRenewalTime(leaf.Certificate.x509.NotBefore,
-		leaf.Certificate.x509.NotAfter)
+		leaf.Certificate.x509.NotAfter, root.Policy.RenewBefore)
 
 	return ok
 }
 
-// isBeforeRenewalTime checks if the result of `currentTime`
-// is after the default renewal time of
-// 1/3rds before the certificate's expiry
-func isBeforeRenewalTime(before, after time.Time) bool {
+// isBeforeRenewalTime checks if the result of `currentTime` is before the
+// renewal time of a certificate: renewBefore its expiry or, when renewBefore
+// is zero or not less than its lifetime, 1/3rds before its expiry.
+func isBeforeRenewalTime(before, after time.Time, renewBefore time.Duration) bool {
 	renewalDuration := after.Sub(before) / renewalRatio
+	if renewBefore > 0 && renewBefore < after.Sub(before) {
+		renewalDuration = renewBefore
+	}
 	renewalTime := after.Add(-1 * renewalDuration)
 	return currentTime().Before(renewalTime)
 }
@@ -34,6 +34,25 @@ func TestCertificateDNSNames(t *testing.T) {
 	assert.Assert(t, zero.DNSNames() == nil)
 }
 
+func TestCertificateExpiry(t *testing.T) {
+	zero := Certificate{}
+	assert.Equal(t, zero.Issuer(), "")
+	assert.Equal(t, zero.Subject(), "")
+	assert.Assert(t, zero.NotAfter().IsZero())
+
+	root, err := NewRootCertificateAuthority()
+	assert.NilError(t, err)
+
+	leaf, err := root.GenerateLeafCertificate("some-cn", nil)
+	assert.NilError(t, err)
+
+	assert.Equal(t, root.Certificate.Subject(), "CN=postgres-operator-ca")
+	assert.Equal(t, leaf.Certificate.Subject(), "CN=some-cn")
+	assert.Equal(t, leaf.Certificate.Issuer(), root.Certificate.Subject())
+	assert.Assert(t, leaf.Certificate.NotAfter().After(time.Now()))
+	assert.Assert(t, leaf.Certificate.NotAfter().Before(root.Certificate.NotAfter()))
+}
+
 func TestCertificateHasSubject(t *testing.T) {
 	zero := Certificate{}
 
@@ -172,7 +191,9 @@ func TestRootIsInvalid(t *testing.T) {
 		leaf, err := root.GenerateLeafCertificate("", nil)
 		assert.NilError(t, err)
 
-		assert.Assert(t, !RootIsValid((*RootCertificateAuthority)(leaf)))
+		assert.Assert(t, !RootIsValid(&RootCertificateAuthority{
+			Certificate: leaf.Certificate, PrivateKey: leaf.PrivateKey,
+		}))
 	})
 
 	t.Run("TooEarly", func(t *testing.T) {
@@ -356,7 +377,9 @@ func TestLeafIsInvalid(t *testing.T) {
 	})
 
 	t.Run("IsAuthority", func(t *testing.T) {
-		assert.Assert(t, !root.leafIsValid((*LeafCertificate)(root)))
+		assert.Assert(t, !root.leafIsValid(&LeafCertificate{
+			Certificate: root.Certificate, PrivateKey: root.PrivateKey,
+		}))
 	})
 
 	t.Run("TooEarly", func(t *testing.T) {
@@ -409,10 +432,10 @@ func TestIsBeforeRenewalTime(t *testing.T) {
 	oneHourAgo := time.Now().Add(-1 * time.Hour)
 	twoHoursInTheFuture := time.Now().Add(2 * time.Hour)
 
-	assert.Assert(t, isBeforeRenewalTime(oneHourAgo, twoHoursInTheFuture))
+	assert.Assert(t, isBeforeRenewalTime(oneHourAgo, twoHoursInTheFuture, 0))
 
 	sixHoursAgo := time.Now().Add(-6 * time.Hour)
-	assert.Assert(t, !isBeforeRenewalTime(sixHoursAgo, twoHoursInTheFuture))
+	assert.Assert(t, !isBeforeRenewalTime(sixHoursAgo, twoHoursInTheFuture, 0))
 }
 
 func TestRegenerateLeaf(t *testing.T) {
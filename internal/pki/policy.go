@@ -0,0 +1,155 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"time"
+)
+
+// KeyAlgorithm is the kind and size of a private key.
+type KeyAlgorithm string
+
+const (
+	// KeyAlgorithmECDSAP256 is ECDSA with a P-256 curve. This curve is roughly
+	// equivalent to an RSA 3072-bit key but requires less bits to achieve the
+	// equivalent cryptographic strength. Additionally, ECDSA is FIPS 140-2
+	// compliant.
+	KeyAlgorithmECDSAP256 KeyAlgorithm = "ECDSA-P256"
+
+	// KeyAlgorithmECDSAP384 is ECDSA with a P-384 curve.
+	KeyAlgorithmECDSAP384 KeyAlgorithm = "ECDSA-P384"
+
+	// KeyAlgorithmRSA2048 and KeyAlgorithmRSA4096 are RSA with 2048-bit and
+	// 4096-bit moduli.
+	KeyAlgorithmRSA2048 KeyAlgorithm = "RSA-2048"
+	KeyAlgorithmRSA4096 KeyAlgorithm = "RSA-4096"
+
+	// KeyAlgorithmEd25519 is EdDSA with Curve25519. Clients need OpenSSL 1.1.1
+	// or later to verify these certificates.
+	KeyAlgorithmEd25519 KeyAlgorithm = "Ed25519"
+)
+
+// defaultLeafLifetime is how long leaf certificates are valid when a Policy
+// does not say.
+const defaultLeafLifetime = time.Hour * 24 * 365
+
+// ParseKeyAlgorithm returns the KeyAlgorithm named by s. An empty s is the
+// zero value, which a Policy treats as the default algorithm.
+func ParseKeyAlgorithm(s string) (KeyAlgorithm, error) {
+	switch algorithm := KeyAlgorithm(s); algorithm {
+	case "", KeyAlgorithmECDSAP256, KeyAlgorithmECDSAP384,
+		KeyAlgorithmRSA2048, KeyAlgorithmRSA4096, KeyAlgorithmEd25519:
+		return algorithm, nil
+	}
+	return "", fmt.Errorf("unknown key algorithm %q", s)
+}
+
+// Policy controls the keys and lifetimes of certificates that an authority
+// generates. The zero value is ECDSA P-256 keys and leaf certificates that
+// are valid for one year and renewed after two-thirds of that.
+type Policy struct {
+	// KeyAlgorithm is the kind of key in new certificates.
+	KeyAlgorithm KeyAlgorithm
+
+	// LeafLifetime is how long new leaf certificates are valid.
+	LeafLifetime time.Duration
+
+	// RenewBefore is how long before they expire to renew leaf certificates.
+	// When zero or not less than LeafLifetime, certificates are renewed when
+	// one-third of their lifetime remains.
+	RenewBefore time.Duration
+}
+
+// Merge returns p with the fields of other that are not zero.
+func (p Policy) Merge(other Policy) Policy {
+	if other.KeyAlgorithm != "" {
+		p.KeyAlgorithm = other.KeyAlgorithm
+	}
+	if other.LeafLifetime > 0 {
+		p.LeafLifetime = other.LeafLifetime
+	}
+	if other.RenewBefore > 0 {
+		p.RenewBefore = other.RenewBefore
+	}
+	return p
+}
+
+func (p Policy) keyAlgorithm() KeyAlgorithm {
+	if p.KeyAlgorithm == "" {
+		return KeyAlgorithmECDSAP256
+	}
+	return p.KeyAlgorithm
+}
+
+func (p Policy) leafLifetime() time.Duration {
+	if p.LeafLifetime <= 0 {
+		return defaultLeafLifetime
+	}
+	return p.LeafLifetime
+}
+
+// generateKey returns a random key of the algorithm in p.
+func (p Policy) generateKey() (crypto.Signer, error) {
+	switch algorithm := p.keyAlgorithm(); algorithm {
+	case KeyAlgorithmECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyAlgorithmECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case KeyAlgorithmRSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case KeyAlgorithmRSA4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case KeyAlgorithmEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return nil, fmt.Errorf("unknown key algorithm %q", algorithm)
+	}
+}
+
+// keyAlgorithmOf returns the KeyAlgorithm of public, or empty when it is not
+// one this package generates.
+func keyAlgorithmOf(public crypto.PublicKey) KeyAlgorithm {
+	switch key := public.(type) {
+	case *ecdsa.PublicKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return KeyAlgorithmECDSAP256
+		case elliptic.P384():
+			return KeyAlgorithmECDSAP384
+		}
+	case *rsa.PublicKey:
+		switch key.N.BitLen() {
+		case 2048:
+			return KeyAlgorithmRSA2048
+		case 4096:
+			return KeyAlgorithmRSA4096
+		}
+	case ed25519.PublicKey:
+		return KeyAlgorithmEd25519
+	}
+	return ""
+}
+
+// signatureAlgorithm returns the algorithm an authority with public key uses
+// to sign certificates. SHA-384 is recommended with the P-256 curve.
+func signatureAlgorithm(public crypto.PublicKey) x509.SignatureAlgorithm {
+	switch public.(type) {
+	case *rsa.PublicKey:
+		return x509.SHA384WithRSA
+	case ed25519.PublicKey:
+		return x509.PureEd25519
+	default:
+		return x509.ECDSAWithSHA384
+	}
+}
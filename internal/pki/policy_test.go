@@ -0,0 +1,146 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package pki
+
+import (
+	"crypto/x509"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/crunchydata/postgres-operator/internal/testing/require"
+)
+
+func TestParseKeyAlgorithm(t *testing.T) {
+	for _, name := range []string{"", "ECDSA-P256", "ECDSA-P384", "RSA-2048", "RSA-4096", "Ed25519"} {
+		algorithm, err := ParseKeyAlgorithm(name)
+		assert.NilError(t, err)
+		assert.Equal(t, string(algorithm), name)
+	}
+
+	_, err := ParseKeyAlgorithm("DSA-1024")
+	assert.ErrorContains(t, err, `unknown key algorithm "DSA-1024"`)
+}
+
+func TestPolicyMerge(t *testing.T) {
+	operator := Policy{KeyAlgorithm: KeyAlgorithmRSA2048, LeafLifetime: time.Hour}
+
+	assert.DeepEqual(t, operator.Merge(Policy{}), operator)
+	assert.DeepEqual(t,
+		operator.Merge(Policy{KeyAlgorithm: KeyAlgorithmEd25519, RenewBefore: time.Minute}),
+		Policy{KeyAlgorithm: KeyAlgorithmEd25519, LeafLifetime: time.Hour, RenewBefore: time.Minute})
+}
+
+func TestPolicyKeyAlgorithms(t *testing.T) {
+	for _, tt := range []struct {
+		algorithm KeyAlgorithm
+		public    x509.PublicKeyAlgorithm
+		signature x509.SignatureAlgorithm
+		label     string
+	}{
+		{KeyAlgorithmECDSAP256, x509.ECDSA, x509.ECDSAWithSHA384, pemLabelECDSAKey},
+		{KeyAlgorithmECDSAP384, x509.ECDSA, x509.ECDSAWithSHA384, pemLabelECDSAKey},
+		{KeyAlgorithmRSA2048, x509.RSA, x509.SHA384WithRSA, pemLabelRSAKey},
+		{KeyAlgorithmRSA4096, x509.RSA, x509.SHA384WithRSA, pemLabelRSAKey},
+		{KeyAlgorithmEd25519, x509.Ed25519, x509.PureEd25519, pemLabelPrivateKey},
+	} {
+		t.Run(string(tt.algorithm), func(t *testing.T) {
+			policy := Policy{KeyAlgorithm: tt.algorithm}
+
+			root, err := policy.NewRootCertificateAuthority()
+			assert.NilError(t, err)
+			assert.Assert(t, RootIsValid(root))
+			assert.Equal(t, root.Certificate.x509.PublicKeyAlgorithm, tt.public)
+			assert.Equal(t, root.Certificate.x509.SignatureAlgorithm, tt.signature)
+
+			leaf, err := root.GenerateLeafCertificate("some-cn", nil)
+			assert.NilError(t, err)
+			assert.Assert(t, root.leafIsValid(leaf))
+			assert.Equal(t, keyAlgorithmOf(leaf.Certificate.x509.PublicKey), tt.algorithm)
+			assert.Equal(t, leaf.Certificate.x509.SignatureAlgorithm, tt.signature)
+
+			text, err := leaf.PrivateKey.MarshalText()
+			assert.NilError(t, err)
+			assert.Assert(t, len(text) > 0)
+
+			var sink PrivateKey
+			assert.NilError(t, sink.UnmarshalText(text))
+			assert.Assert(t, sink.Equal(leaf.PrivateKey))
+			assert.Assert(t, strings.HasPrefix(string(text), "-----BEGIN "+tt.label+"-----\n"), "got %q", text)
+
+			t.Run("OpenSSLVerify", func(t *testing.T) {
+				openssl := require.OpenSSL(t)
+				basicOpenSSLVerify(t, openssl, root.Certificate, leaf.Certificate)
+			})
+		})
+	}
+}
+
+func TestPolicyLeafIsInvalid(t *testing.T) {
+	root, err := NewRootCertificateAuthority()
+	assert.NilError(t, err)
+
+	leaf, err := root.GenerateLeafCertificate("", nil)
+	assert.NilError(t, err)
+	assert.Assert(t, root.leafIsValid(leaf))
+
+	t.Run("KeyAlgorithm", func(t *testing.T) {
+		other := *root
+		other.Policy = Policy{KeyAlgorithm: KeyAlgorithmECDSAP384}
+		assert.Assert(t, !other.leafIsValid(leaf))
+
+		regenerated, err := other.RegenerateLeafWhenNecessary(leaf, "", nil)
+		assert.NilError(t, err)
+		assert.Assert(t, other.leafIsValid(regenerated))
+		assert.Equal(t, keyAlgorithmOf(regenerated.Certificate.x509.PublicKey), KeyAlgorithmECDSAP384)
+	})
+
+	t.Run("ShorterLifetime", func(t *testing.T) {
+		other := *root
+		other.Policy = Policy{LeafLifetime: 90 * 24 * time.Hour}
+		assert.Assert(t, !other.leafIsValid(leaf))
+
+		regenerated, err := other.RegenerateLeafWhenNecessary(leaf, "", nil)
+		assert.NilError(t, err)
+		assert.Assert(t, other.leafIsValid(regenerated))
+		assert.Equal(t,
+			regenerated.Certificate.x509.NotAfter.Sub(regenerated.Certificate.x509.NotBefore),
+			90*24*time.Hour-leafStartValid)
+
+		// A longer lifetime does not regenerate.
+		other.Policy = Policy{LeafLifetime: 2 * 365 * 24 * time.Hour}
+		assert.Assert(t, other.leafIsValid(leaf))
+	})
+
+	t.Run("RenewBefore", func(t *testing.T) {
+		original := currentTime
+		t.Cleanup(func() { currentTime = original })
+
+		// Four months before expiry is past the default renewal time but
+		// before a renewal threshold of three months.
+		currentTime = func() time.Time { return leaf.Certificate.x509.NotAfter.Add(-120 * 24 * time.Hour) }
+		assert.Assert(t, !root.leafIsValid(leaf))
+
+		other := *root
+		other.Policy = Policy{RenewBefore: 90 * 24 * time.Hour}
+		assert.Assert(t, other.leafIsValid(leaf))
+	})
+}
+
+func TestIsBeforeRenewalTimeThreshold(t *testing.T) {
+	before := time.Now().Add(-6 * time.Hour)
+	after := time.Now().Add(2 * time.Hour)
+
+	// The default is one-third of the lifetime, which has passed.
+	assert.Assert(t, !isBeforeRenewalTime(before, after, 0))
+
+	assert.Assert(t, isBeforeRenewalTime(before, after, time.Hour))
+	assert.Assert(t, !isBeforeRenewalTime(before, after, 3*time.Hour))
+
+	// A threshold longer than the lifetime is ignored.
+	assert.Assert(t, !isBeforeRenewalTime(before, after, 24*time.Hour))
+}
@@ -0,0 +1,460 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ParameterContext indicates when changes to a PostgreSQL parameter take effect.
+// - https://www.postgresql.org/docs/current/view-pg-settings.html
+type ParameterContext string
+
+const (
+	// Changes take effect when PostgreSQL restarts.
+	ParameterContextPostmaster ParameterContext = "postmaster"
+
+	// Changes take effect when PostgreSQL reloads its configuration files.
+	ParameterContextSighup ParameterContext = "sighup"
+
+	// Changes take effect for sessions started after a reload.
+	ParameterContextBackend          ParameterContext = "backend"
+	ParameterContextSuperuserBackend ParameterContext = "superuser-backend"
+
+	// Changes take effect after a reload and can be set by superusers in a session.
+	ParameterContextSuperuser ParameterContext = "superuser"
+
+	// Changes take effect after a reload and can be set by any user in a session.
+	ParameterContextUser ParameterContext = "user"
+)
+
+// ParameterType is the kind of value a PostgreSQL parameter accepts.
+// - https://www.postgresql.org/docs/current/config-setting.html
+type ParameterType string
+
+const (
+	ParameterTypeBool    ParameterType = "bool"
+	ParameterTypeEnum    ParameterType = "enum"
+	ParameterTypeInteger ParameterType = "integer"
+	ParameterTypeReal    ParameterType = "real"
+	ParameterTypeString  ParameterType = "string"
+)
+
+// ParameterDefinition describes a PostgreSQL parameter in one or more major
+// versions of PostgreSQL.
+type ParameterDefinition struct {
+	Name    string
+	Type    ParameterType
+	Context ParameterContext
+
+	// The values allowed for an "enum" parameter.
+	Values []string
+
+	// The first and last major versions of PostgreSQL that have this
+	// definition. Zero means there is no bound.
+	Since, Until int
+}
+
+// RequiresRestart returns whether or not PostgreSQL must restart for changes
+// to the parameter to take effect.
+func (def ParameterDefinition) RequiresRestart() bool {
+	return def.Context == ParameterContextPostmaster
+}
+
+var (
+	// Boolean values are case-insensitive. PostgreSQL also accepts any
+	// unambiguous prefix of these words, but those are rejected here.
+	// - https://www.postgresql.org/docs/current/config-setting.html#CONFIG-SETTING-NAMES-VALUES
+	reParameterBool = regexp.MustCompile(`^(?i:on|off|true|false|yes|no|1|0)$`)
+
+	// Numeric values may be written with a unit of memory or time.
+	// - https://www.postgresql.org/docs/current/config-setting.html#CONFIG-SETTING-NAMES-VALUES
+	reParameterInteger = regexp.MustCompile(`^\s*[-+]?(?:\d+|0x[0-9a-fA-F]+)\s*(?:B|kB|MB|GB|TB|us|ms|s|min|h|d)?\s*$`)
+	reParameterReal    = regexp.MustCompile(`^\s*[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?\s*(?:B|kB|MB|GB|TB|us|ms|s|min|h|d)?\s*$`)
+)
+
+// Validate returns an error when value is not acceptable for def.
+func (def ParameterDefinition) Validate(value intstr.IntOrString) error {
+	switch def.Type {
+	case ParameterTypeBool:
+		if value.Type == intstr.Int && (value.IntVal == 0 || value.IntVal == 1) {
+			return nil
+		}
+		if value.Type == intstr.String && reParameterBool.MatchString(value.StrVal) {
+			return nil
+		}
+		return fmt.Errorf("%q is not a boolean", value.String())
+
+	case ParameterTypeEnum:
+		if slices.ContainsFunc(def.Values, func(v string) bool {
+			return strings.EqualFold(v, value.String())
+		}) {
+			return nil
+		}
+		// Enums that accept "on" and "off" also accept the other spellings of
+		// booleans. PostgreSQL hides these from pg_settings.enumvals.
+		if slices.Contains(def.Values, "on") && reParameterBool.MatchString(value.String()) {
+			return nil
+		}
+		return fmt.Errorf("%q is not one of %q", value.String(), def.Values)
+
+	case ParameterTypeInteger:
+		if value.Type == intstr.Int || reParameterInteger.MatchString(value.StrVal) {
+			return nil
+		}
+		return fmt.Errorf("%q is not an integer", value.String())
+
+	case ParameterTypeReal:
+		if value.Type == intstr.Int || reParameterReal.MatchString(value.StrVal) {
+			return nil
+		}
+		return fmt.Errorf("%q is not a number", value.String())
+	}
+
+	return nil
+}
+
+// LookupParameter returns the definition of parameter name in PostgreSQL
+// version and whether or not it was found. Parameter names are case-insensitive.
+func LookupParameter(version int, name string) (ParameterDefinition, bool) {
+	name = strings.ToLower(name)
+	for _, def := range parameterCatalog[name] {
+		if (def.Since == 0 || version >= def.Since) &&
+			(def.Until == 0 || version <= def.Until) {
+			return def, true
+		}
+	}
+	return ParameterDefinition{}, false
+}
+
+// ErrUnrecognizedParameter is returned by [ValidateParameter] when a parameter
+// is not in the catalog. The catalog is not exhaustive, so such a parameter
+// may still be valid.
+var ErrUnrecognizedParameter = errors.New("unrecognized parameter")
+
+// ValidateParameter returns an error when parameter name does not exist in
+// PostgreSQL version or value is not acceptable for it. Names that contain a
+// period are customized options that belong to extensions; their values are
+// not checked. Names that are not in the catalog at all return an error that
+// wraps [ErrUnrecognizedParameter].
+// - https://www.postgresql.org/docs/current/runtime-config-custom.html
+func ValidateParameter(version int, name string, value intstr.IntOrString) error {
+	if strings.Contains(name, ".") {
+		return nil
+	}
+
+	def, ok := LookupParameter(version, name)
+	if !ok {
+		if _, exists := parameterCatalog[strings.ToLower(name)]; exists {
+			return fmt.Errorf("parameter %q does not exist in PostgreSQL %d", name, version)
+		}
+		return fmt.Errorf("%w %q", ErrUnrecognizedParameter, name)
+	}
+	if err := def.Validate(value); err != nil {
+		return fmt.Errorf("parameter %q: %w", name, err)
+	}
+	return nil
+}
+
+// parameterCatalog contains PostgreSQL parameters that are commonly configured,
+// keyed by their lowercase name. It is not an exhaustive list; parameters that
+// only make sense for developers or are controlled entirely by this operator
+// are omitted.
+// - https://www.postgresql.org/docs/current/runtime-config.html
+var parameterCatalog = func() map[string][]ParameterDefinition {
+	const (
+		postmaster       = ParameterContextPostmaster
+		sighup           = ParameterContextSighup
+		superuserBackend = ParameterContextSuperuserBackend
+		superuser        = ParameterContextSuperuser
+		user             = ParameterContextUser
+
+		boolean = ParameterTypeBool
+		enum    = ParameterTypeEnum
+		integer = ParameterTypeInteger
+		number  = ParameterTypeReal
+		str     = ParameterTypeString
+	)
+
+	logLevels := []string{
+		"debug5", "debug4", "debug3", "debug2", "debug1",
+		"info", "notice", "warning", "error", "log", "fatal", "panic",
+	}
+
+	definitions := []ParameterDefinition{
+		// Resource Consumption
+		// - https://www.postgresql.org/docs/current/runtime-config-resource.html
+		{Name: "autovacuum_work_mem", Type: integer, Context: sighup},
+		{Name: "bgwriter_delay", Type: integer, Context: sighup},
+		{Name: "bgwriter_flush_after", Type: integer, Context: sighup},
+		{Name: "bgwriter_lru_maxpages", Type: integer, Context: sighup},
+		{Name: "bgwriter_lru_multiplier", Type: number, Context: sighup},
+		{Name: "dynamic_shared_memory_type", Type: enum, Context: postmaster, Values: []string{"posix", "sysv", "windows", "mmap"}},
+		{Name: "effective_io_concurrency", Type: integer, Context: user},
+		{Name: "hash_mem_multiplier", Type: number, Context: user, Since: 13},
+		{Name: "huge_page_size", Type: integer, Context: postmaster, Since: 14},
+		{Name: "huge_pages", Type: enum, Context: postmaster, Values: []string{"on", "off", "try"}},
+		{Name: "logical_decoding_work_mem", Type: integer, Context: user, Since: 13},
+		{Name: "maintenance_io_concurrency", Type: integer, Context: user, Since: 13},
+		{Name: "maintenance_work_mem", Type: integer, Context: user},
+		{Name: "max_files_per_process", Type: integer, Context: postmaster},
+		{Name: "max_parallel_maintenance_workers", Type: integer, Context: user},
+		{Name: "max_parallel_workers", Type: integer, Context: user},
+		{Name: "max_parallel_workers_per_gather", Type: integer, Context: user},
+		{Name: "max_prepared_transactions", Type: integer, Context: postmaster},
+		{Name: "max_stack_depth", Type: integer, Context: superuser},
+		{Name: "max_worker_processes", Type: integer, Context: postmaster},
+		{Name: "min_dynamic_shared_memory", Type: integer, Context: postmaster, Since: 14},
+		{Name: "parallel_leader_participation", Type: boolean, Context: user},
+		{Name: "shared_buffers", Type: integer, Context: postmaster},
+		{Name: "shared_memory_type", Type: enum, Context: postmaster, Values: []string{"mmap", "sysv", "windows"}, Since: 12},
+		{Name: "temp_buffers", Type: integer, Context: user},
+		{Name: "temp_file_limit", Type: integer, Context: superuser},
+		{Name: "vacuum_cost_delay", Type: number, Context: user},
+		{Name: "vacuum_cost_limit", Type: integer, Context: user},
+		{Name: "work_mem", Type: integer, Context: user},
+
+		// Connections and Authentication
+		// - https://www.postgresql.org/docs/current/runtime-config-connection.html
+		{Name: "authentication_timeout", Type: integer, Context: sighup},
+		{Name: "client_connection_check_interval", Type: integer, Context: user, Since: 14},
+		{Name: "max_connections", Type: integer, Context: postmaster},
+		{Name: "password_encryption", Type: enum, Context: user, Values: []string{"md5", "scram-sha-256", "on", "off"}, Until: 13},
+		{Name: "password_encryption", Type: enum, Context: user, Values: []string{"md5", "scram-sha-256"}, Since: 14},
+		{Name: "reserved_connections", Type: integer, Context: postmaster, Since: 16},
+		{Name: "ssl_ciphers", Type: str, Context: sighup},
+		{Name: "ssl_ecdh_curve", Type: str, Context: sighup},
+		{Name: "ssl_max_protocol_version", Type: enum, Context: sighup, Values: []string{"", "TLSv1", "TLSv1.1", "TLSv1.2", "TLSv1.3"}, Since: 12},
+		{Name: "ssl_min_protocol_version", Type: enum, Context: sighup, Values: []string{"TLSv1", "TLSv1.1", "TLSv1.2", "TLSv1.3"}, Since: 12},
+		{Name: "ssl_prefer_server_ciphers", Type: boolean, Context: sighup},
+		{Name: "superuser_reserved_connections", Type: integer, Context: postmaster},
+		{Name: "tcp_keepalives_count", Type: integer, Context: user},
+		{Name: "tcp_keepalives_idle", Type: integer, Context: user},
+		{Name: "tcp_keepalives_interval", Type: integer, Context: user},
+		{Name: "tcp_user_timeout", Type: integer, Context: user, Since: 12},
+
+		// Write Ahead Log
+		// - https://www.postgresql.org/docs/current/runtime-config-wal.html
+		{Name: "archive_command", Type: str, Context: sighup},
+		{Name: "archive_library", Type: str, Context: sighup, Since: 15},
+		{Name: "archive_mode", Type: enum, Context: postmaster, Values: []string{"always", "on", "off"}},
+		{Name: "archive_timeout", Type: integer, Context: sighup},
+		{Name: "checkpoint_completion_target", Type: number, Context: sighup},
+		{Name: "checkpoint_flush_after", Type: integer, Context: sighup},
+		{Name: "checkpoint_timeout", Type: integer, Context: sighup},
+		{Name: "checkpoint_warning", Type: integer, Context: sighup},
+		{Name: "commit_delay", Type: integer, Context: superuser},
+		{Name: "commit_siblings", Type: integer, Context: user},
+		{Name: "fsync", Type: boolean, Context: sighup},
+		{Name: "full_page_writes", Type: boolean, Context: sighup},
+		{Name: "max_wal_size", Type: integer, Context: sighup},
+		{Name: "min_wal_size", Type: integer, Context: sighup},
+		{Name: "recovery_prefetch", Type: enum, Context: sighup, Values: []string{"off", "on", "try"}, Since: 15},
+		{Name: "summarize_wal", Type: boolean, Context: sighup, Since: 17},
+		{Name: "synchronous_commit", Type: enum, Context: user, Values: []string{"local", "remote_write", "remote_apply", "on", "off"}},
+		{Name: "wal_buffers", Type: integer, Context: postmaster},
+		{Name: "wal_compression", Type: boolean, Context: superuser, Until: 14},
+		{Name: "wal_compression", Type: enum, Context: superuser, Values: []string{"pglz", "lz4", "zstd", "on", "off"}, Since: 15},
+		{Name: "wal_decode_buffer_size", Type: integer, Context: postmaster, Since: 15},
+		{Name: "wal_init_zero", Type: boolean, Context: superuser, Since: 12},
+		{Name: "wal_level", Type: enum, Context: postmaster, Values: []string{"minimal", "replica", "logical"}},
+		{Name: "wal_log_hints", Type: boolean, Context: postmaster},
+		{Name: "wal_recycle", Type: boolean, Context: superuser, Since: 12},
+		{Name: "wal_skip_threshold", Type: integer, Context: user, Since: 13},
+		{Name: "wal_writer_delay", Type: integer, Context: sighup},
+		{Name: "wal_writer_flush_after", Type: integer, Context: sighup},
+
+		// Archive Recovery and Recovery Target
+		// - https://www.postgresql.org/docs/current/runtime-config-wal.html#RUNTIME-CONFIG-WAL-ARCHIVE-RECOVERY
+		{Name: "archive_cleanup_command", Type: str, Context: sighup, Since: 12},
+		{Name: "recovery_end_command", Type: str, Context: sighup, Since: 12},
+		{Name: "restore_command", Type: str, Context: postmaster, Since: 12, Until: 14},
+		{Name: "restore_command", Type: str, Context: sighup, Since: 15},
+
+		// Replication
+		// - https://www.postgresql.org/docs/current/runtime-config-replication.html
+		{Name: "hot_standby", Type: boolean, Context: postmaster},
+		{Name: "hot_standby_feedback", Type: boolean, Context: sighup},
+		{Name: "max_logical_replication_workers", Type: integer, Context: postmaster},
+		{Name: "max_parallel_apply_workers_per_subscription", Type: integer, Context: sighup, Since: 16},
+		{Name: "max_replication_slots", Type: integer, Context: postmaster},
+		{Name: "max_slot_wal_keep_size", Type: integer, Context: sighup, Since: 13},
+		{Name: "max_standby_archive_delay", Type: integer, Context: sighup},
+		{Name: "max_standby_streaming_delay", Type: integer, Context: sighup},
+		{Name: "max_sync_workers_per_subscription", Type: integer, Context: sighup},
+		{Name: "max_wal_senders", Type: integer, Context: postmaster},
+		{Name: "primary_conninfo", Type: str, Context: postmaster, Since: 12, Until: 12},
+		{Name: "primary_conninfo", Type: str, Context: sighup, Since: 13},
+		{Name: "primary_slot_name", Type: str, Context: postmaster, Since: 12, Until: 12},
+		{Name: "primary_slot_name", Type: str, Context: sighup, Since: 13},
+		{Name: "recovery_min_apply_delay", Type: integer, Context: sighup, Since: 12},
+		{Name: "synchronous_standby_names", Type: str, Context: sighup},
+		{Name: "track_commit_timestamp", Type: boolean, Context: postmaster},
+		{Name: "vacuum_defer_cleanup_age", Type: integer, Context: sighup, Until: 15},
+		{Name: "wal_keep_segments", Type: integer, Context: sighup, Until: 12},
+		{Name: "wal_keep_size", Type: integer, Context: sighup, Since: 13},
+		{Name: "wal_receiver_create_temp_slot", Type: boolean, Context: sighup, Since: 13},
+		{Name: "wal_receiver_status_interval", Type: integer, Context: sighup},
+		{Name: "wal_receiver_timeout", Type: integer, Context: sighup},
+		{Name: "wal_retrieve_retry_interval", Type: integer, Context: sighup},
+		{Name: "wal_sender_timeout", Type: integer, Context: sighup, Until: 11},
+		{Name: "wal_sender_timeout", Type: integer, Context: user, Since: 12},
+
+		// Query Planning
+		// - https://www.postgresql.org/docs/current/runtime-config-query.html
+		{Name: "constraint_exclusion", Type: enum, Context: user, Values: []string{"partition", "on", "off"}},
+		{Name: "cpu_index_tuple_cost", Type: number, Context: user},
+		{Name: "cpu_operator_cost", Type: number, Context: user},
+		{Name: "cpu_tuple_cost", Type: number, Context: user},
+		{Name: "default_statistics_target", Type: integer, Context: user},
+		{Name: "effective_cache_size", Type: integer, Context: user},
+		{Name: "enable_bitmapscan", Type: boolean, Context: user},
+		{Name: "enable_hashjoin", Type: boolean, Context: user},
+		{Name: "enable_indexonlyscan", Type: boolean, Context: user},
+		{Name: "enable_indexscan", Type: boolean, Context: user},
+		{Name: "enable_mergejoin", Type: boolean, Context: user},
+		{Name: "enable_nestloop", Type: boolean, Context: user},
+		{Name: "enable_partitionwise_aggregate", Type: boolean, Context: user},
+		{Name: "enable_partitionwise_join", Type: boolean, Context: user},
+		{Name: "enable_seqscan", Type: boolean, Context: user},
+		{Name: "from_collapse_limit", Type: integer, Context: user},
+		{Name: "jit", Type: boolean, Context: user},
+		{Name: "jit_above_cost", Type: number, Context: user},
+		{Name: "jit_inline_above_cost", Type: number, Context: user},
+		{Name: "jit_optimize_above_cost", Type: number, Context: user},
+		{Name: "join_collapse_limit", Type: integer, Context: user},
+		{Name: "parallel_setup_cost", Type: number, Context: user},
+		{Name: "parallel_tuple_cost", Type: number, Context: user},
+		{Name: "plan_cache_mode", Type: enum, Context: user, Values: []string{"auto", "force_generic_plan", "force_custom_plan"}, Since: 12},
+		{Name: "random_page_cost", Type: number, Context: user},
+		{Name: "seq_page_cost", Type: number, Context: user},
+
+		// Reporting and Logging
+		// - https://www.postgresql.org/docs/current/runtime-config-logging.html
+		{Name: "log_autovacuum_min_duration", Type: integer, Context: sighup},
+		{Name: "log_checkpoints", Type: boolean, Context: sighup},
+		{Name: "log_connections", Type: boolean, Context: superuserBackend},
+		{Name: "log_destination", Type: str, Context: sighup},
+		{Name: "log_directory", Type: str, Context: sighup},
+		{Name: "log_disconnections", Type: boolean, Context: superuserBackend},
+		{Name: "log_duration", Type: boolean, Context: superuser},
+		{Name: "log_error_verbosity", Type: enum, Context: superuser, Values: []string{"terse", "default", "verbose"}},
+		{Name: "log_filename", Type: str, Context: sighup},
+		{Name: "log_hostname", Type: boolean, Context: sighup},
+		{Name: "log_line_prefix", Type: str, Context: sighup},
+		{Name: "log_lock_waits", Type: boolean, Context: superuser},
+		{Name: "log_min_duration_sample", Type: integer, Context: superuser, Since: 13},
+		{Name: "log_min_duration_statement", Type: integer, Context: superuser},
+		{Name: "log_min_error_statement", Type: enum, Context: superuser, Values: logLevels},
+		{Name: "log_min_messages", Type: enum, Context: superuser, Values: logLevels},
+		{Name: "log_parameter_max_length", Type: integer, Context: superuser, Since: 13},
+		{Name: "log_recovery_conflict_waits", Type: boolean, Context: sighup, Since: 14},
+		{Name: "log_rotation_age", Type: integer, Context: sighup},
+		{Name: "log_rotation_size", Type: integer, Context: sighup},
+		{Name: "log_startup_progress_interval", Type: integer, Context: sighup, Since: 15},
+		{Name: "log_statement", Type: enum, Context: superuser, Values: []string{"none", "ddl", "mod", "all"}},
+		{Name: "log_statement_sample_rate", Type: number, Context: superuser, Since: 13},
+		{Name: "log_temp_files", Type: integer, Context: superuser},
+		{Name: "log_timezone", Type: str, Context: sighup},
+		{Name: "log_transaction_sample_rate", Type: number, Context: superuser, Since: 12},
+		{Name: "log_truncate_on_rotation", Type: boolean, Context: sighup},
+		{Name: "logging_collector", Type: boolean, Context: postmaster},
+		{Name: "cluster_name", Type: str, Context: postmaster},
+
+		// Run-time Statistics
+		// - https://www.postgresql.org/docs/current/runtime-config-statistics.html
+		{Name: "compute_query_id", Type: enum, Context: superuser, Values: []string{"auto", "regress", "on", "off"}, Since: 14},
+		{Name: "stats_temp_directory", Type: str, Context: sighup, Until: 14},
+		{Name: "track_activities", Type: boolean, Context: superuser},
+		{Name: "track_activity_query_size", Type: integer, Context: postmaster},
+		{Name: "track_counts", Type: boolean, Context: superuser},
+		{Name: "track_functions", Type: enum, Context: superuser, Values: []string{"none", "pl", "all"}},
+		{Name: "track_io_timing", Type: boolean, Context: superuser},
+		{Name: "track_wal_io_timing", Type: boolean, Context: superuser, Since: 14},
+
+		// Automatic Vacuuming
+		// - https://www.postgresql.org/docs/current/runtime-config-autovacuum.html
+		{Name: "autovacuum", Type: boolean, Context: sighup},
+		{Name: "autovacuum_analyze_scale_factor", Type: number, Context: sighup},
+		{Name: "autovacuum_analyze_threshold", Type: integer, Context: sighup},
+		{Name: "autovacuum_freeze_max_age", Type: integer, Context: postmaster},
+		{Name: "autovacuum_max_workers", Type: integer, Context: postmaster},
+		{Name: "autovacuum_multixact_freeze_max_age", Type: integer, Context: postmaster},
+		{Name: "autovacuum_naptime", Type: integer, Context: sighup},
+		{Name: "autovacuum_vacuum_cost_delay", Type: number, Context: sighup},
+		{Name: "autovacuum_vacuum_cost_limit", Type: integer, Context: sighup},
+		{Name: "autovacuum_vacuum_insert_scale_factor", Type: number, Context: sighup, Since: 13},
+		{Name: "autovacuum_vacuum_insert_threshold", Type: integer, Context: sighup, Since: 13},
+		{Name: "autovacuum_vacuum_scale_factor", Type: number, Context: sighup},
+		{Name: "autovacuum_vacuum_threshold", Type: integer, Context: sighup},
+
+		// Client Connection Defaults
+		// - https://www.postgresql.org/docs/current/runtime-config-client.html
+		{Name: "client_min_messages", Type: enum, Context: user, Values: logLevels},
+		{Name: "datestyle", Type: str, Context: user},
+		{Name: "default_table_access_method", Type: str, Context: user, Since: 12},
+		{Name: "default_tablespace", Type: str, Context: user},
+		{Name: "default_text_search_config", Type: str, Context: user},
+		{Name: "default_transaction_isolation", Type: enum, Context: user, Values: []string{"serializable", "repeatable read", "read committed", "read uncommitted"}},
+		{Name: "default_transaction_read_only", Type: boolean, Context: user},
+		{Name: "extra_float_digits", Type: integer, Context: user},
+		{Name: "gin_pending_list_limit", Type: integer, Context: user},
+		{Name: "idle_in_transaction_session_timeout", Type: integer, Context: user},
+		{Name: "idle_session_timeout", Type: integer, Context: user, Since: 14},
+		{Name: "intervalstyle", Type: enum, Context: user, Values: []string{"postgres", "postgres_verbose", "sql_standard", "iso_8601"}},
+		{Name: "lc_messages", Type: str, Context: superuser},
+		{Name: "lc_monetary", Type: str, Context: user},
+		{Name: "lc_numeric", Type: str, Context: user},
+		{Name: "lc_time", Type: str, Context: user},
+		{Name: "local_preload_libraries", Type: str, Context: user},
+		{Name: "lock_timeout", Type: integer, Context: user},
+		{Name: "search_path", Type: str, Context: user},
+		{Name: "session_preload_libraries", Type: str, Context: superuser},
+		{Name: "shared_preload_libraries", Type: str, Context: postmaster},
+		{Name: "statement_timeout", Type: integer, Context: user},
+		{Name: "temp_tablespaces", Type: str, Context: user},
+		{Name: "timezone", Type: str, Context: user},
+		{Name: "transaction_timeout", Type: integer, Context: user, Since: 17},
+		{Name: "vacuum_freeze_min_age", Type: integer, Context: user},
+		{Name: "vacuum_freeze_table_age", Type: integer, Context: user},
+		{Name: "vacuum_multixact_freeze_min_age", Type: integer, Context: user},
+		{Name: "vacuum_multixact_freeze_table_age", Type: integer, Context: user},
+
+		// Lock Management
+		// - https://www.postgresql.org/docs/current/runtime-config-locks.html
+		{Name: "deadlock_timeout", Type: integer, Context: superuser},
+		{Name: "max_locks_per_transaction", Type: integer, Context: postmaster},
+		{Name: "max_pred_locks_per_page", Type: integer, Context: sighup},
+		{Name: "max_pred_locks_per_relation", Type: integer, Context: sighup},
+		{Name: "max_pred_locks_per_transaction", Type: integer, Context: postmaster},
+
+		// Error Handling
+		// - https://www.postgresql.org/docs/current/runtime-config-error-handling.html
+		{Name: "data_sync_retry", Type: boolean, Context: postmaster},
+		{Name: "remove_temp_files_after_crash", Type: boolean, Context: sighup, Since: 14},
+		{Name: "restart_after_crash", Type: boolean, Context: sighup},
+
+		// Version and Platform Compatibility
+		// - https://www.postgresql.org/docs/current/runtime-config-compatible.html
+		{Name: "allow_alter_system", Type: boolean, Context: sighup, Since: 17},
+		{Name: "array_nulls", Type: boolean, Context: user},
+		{Name: "bytea_output", Type: enum, Context: user, Values: []string{"escape", "hex"}},
+		{Name: "row_security", Type: boolean, Context: user},
+		{Name: "session_replication_role", Type: enum, Context: superuser, Values: []string{"origin", "replica", "local"}},
+		{Name: "standard_conforming_strings", Type: boolean, Context: user},
+	}
+
+	catalog := make(map[string][]ParameterDefinition, len(definitions))
+	for _, def := range definitions {
+		catalog[def.Name] = append(catalog[def.Name], def)
+	}
+	return catalog
+}()
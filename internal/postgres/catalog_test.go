@@ -0,0 +1,98 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestParameterCatalog(t *testing.T) {
+	for name, definitions := range parameterCatalog {
+		assert.Equal(t, name, strings.ToLower(name))
+
+		for _, def := range definitions {
+			assert.Equal(t, def.Name, name)
+			assert.Assert(t, def.Type != "", "%q", name)
+			assert.Assert(t, def.Context != "", "%q", name)
+			assert.Assert(t, (def.Type == ParameterTypeEnum) == (len(def.Values) > 0), "%q", name)
+		}
+	}
+}
+
+func TestLookupParameter(t *testing.T) {
+	def, ok := LookupParameter(16, "Shared_Buffers")
+	assert.Assert(t, ok)
+	assert.Equal(t, def.Name, "shared_buffers")
+	assert.Assert(t, def.RequiresRestart())
+
+	def, ok = LookupParameter(16, "work_mem")
+	assert.Assert(t, ok)
+	assert.Assert(t, !def.RequiresRestart())
+
+	_, ok = LookupParameter(16, "wal_keep_segments")
+	assert.Assert(t, !ok, "removed in PostgreSQL 13")
+
+	_, ok = LookupParameter(12, "wal_keep_segments")
+	assert.Assert(t, ok)
+
+	_, ok = LookupParameter(16, "not_a_parameter")
+	assert.Assert(t, !ok)
+}
+
+func TestValidateParameter(t *testing.T) {
+	for _, tt := range []struct {
+		name, value string
+		version     int
+		expected    string
+	}{
+		{version: 16, name: "work_mem", value: "64MB"},
+		{version: 16, name: "work_mem", value: "16384"},
+		{version: 16, name: "work_mem", value: "lots", expected: `"lots" is not an integer`},
+		{version: 16, name: "random_page_cost", value: "1.1"},
+		{version: 16, name: "random_page_cost", value: "cheap", expected: "not a number"},
+		{version: 16, name: "jit", value: "OFF"},
+		{version: 16, name: "jit", value: "maybe", expected: "not a boolean"},
+		{version: 16, name: "wal_compression", value: "lz4"},
+		{version: 16, name: "wal_compression", value: "true"},
+		{version: 16, name: "huge_pages", value: "Yes"},
+		{version: 16, name: "huge_pages", value: "0"},
+		{version: 16, name: "huge_pages", value: "maybe", expected: "is not one of"},
+		{version: 16, name: "wal_level", value: "on", expected: "is not one of"},
+		{version: 16, name: "log_min_messages", value: "sometimes", expected: "is not one of"},
+		{version: 16, name: "pg_stat_statements.track", value: "anything"},
+		{version: 16, name: "wal_keep_segments", value: "10", expected: "does not exist in PostgreSQL 16"},
+		{version: 16, name: "not_a_parameter", value: "1", expected: "unrecognized parameter"},
+	} {
+		err := ValidateParameter(tt.version, tt.name, intstr.FromString(tt.value))
+		if tt.expected == "" {
+			assert.NilError(t, err, "%v", tt)
+		} else {
+			assert.ErrorContains(t, err, tt.expected, "%v", tt)
+		}
+	}
+
+	t.Run("Unrecognized", func(t *testing.T) {
+		assert.ErrorIs(t,
+			ValidateParameter(16, "not_a_parameter", intstr.FromInt(1)),
+			ErrUnrecognizedParameter)
+		assert.Assert(t, !errors.Is(
+			ValidateParameter(16, "wal_keep_segments", intstr.FromInt(1)),
+			ErrUnrecognizedParameter))
+	})
+
+	t.Run("Numbers", func(t *testing.T) {
+		assert.NilError(t, ValidateParameter(16, "max_parallel_workers", intstr.FromInt(8)))
+		assert.NilError(t, ValidateParameter(16, "seq_page_cost", intstr.FromInt(2)))
+		assert.NilError(t, ValidateParameter(16, "jit", intstr.FromInt(0)))
+		assert.NilError(t, ValidateParameter(16, "huge_pages", intstr.FromInt(1)))
+		assert.ErrorContains(t,
+			ValidateParameter(16, "jit", intstr.FromInt(5)), "not a boolean")
+	})
+}
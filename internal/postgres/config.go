@@ -5,14 +5,18 @@
 package postgres
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/crunchydata/postgres-operator/internal/config"
 	"github.com/crunchydata/postgres-operator/internal/feature"
+	"github.com/crunchydata/postgres-operator/internal/logging"
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
@@ -105,6 +109,28 @@ func WALStorage(instance *v1beta1.PostgresInstanceSetSpec) string {
 	return dataMountPath
 }
 
+// DataVolumeUsed calls exec to measure the number of bytes used on the main
+// data volume of an instance.
+func DataVolumeUsed(ctx context.Context, exec Executor) (int64, error) {
+	var stdout, stderr bytes.Buffer
+	err := exec(ctx, nil, &stdout, &stderr,
+		"df", "--block-size=1", "--output=used", dataMountPath)
+
+	logging.FromContext(ctx).V(1).Info("measured data volume",
+		"stdout", stdout.String(), "stderr", stderr.String())
+
+	if err != nil {
+		return 0, err
+	}
+
+	// The first line is a header; the second is the value.
+	fields := strings.Fields(stdout.String())
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("unexpected output from df: %q", stdout.String())
+	}
+	return strconv.ParseInt(fields[1], 10, 64)
+}
+
 // Environment returns the environment variables required to invoke PostgreSQL
 // utilities.
 func Environment(cluster *v1beta1.PostgresCluster) []corev1.EnvVar {
@@ -154,9 +180,10 @@ func Environment(cluster *v1beta1.PostgresCluster) []corev1.EnvVar {
 }
 
 // reloadCommand returns an entrypoint that convinces PostgreSQL to reload
-// certificate files when they change. The process will appear as name in `ps`
-// and `top`.
-func reloadCommand(name string) []string {
+// certificate files when they change. It also suggests a larger size for each
+// of volumes, keyed by annotation name and holding mount paths, when it is
+// nearly full. The process will appear as name in `ps` and `top`.
+func reloadCommand(name string, volumes map[string]string) []string {
 	// Use a Bash loop to periodically check the mtime of the mounted
 	// certificate volume. When it changes, copy the replication certificate,
 	// signal PostgreSQL, and print the observed timestamp.
@@ -165,6 +192,10 @@ func reloadCommand(name string) []string {
 	// - https://www.postgresql.org/docs/current/ssl-tcp.html#SSL-SERVER-FILES
 	// - https://www.postgresql.org/docs/current/app-postgres.html
 	//
+	// After PostgreSQL loads the certificate authorities, the loop annotates
+	// the Pod with their SHA-256. They are hashed before any reload, so the
+	// annotation is never ahead of PostgreSQL.
+	//
 	// PostgreSQL reads its replication credentials every time it opens a
 	// replication connection. It does not need to be signaled when the
 	// certificate contents change.
@@ -179,8 +210,17 @@ func reloadCommand(name string) []string {
 	// descriptor gets closed and reopened to use the builtin `[ -nt` to check
 	// mtimes.
 	// - https://unix.stackexchange.com/a/407383
+	//
+	// Each volume is "name=path" where name is part of the annotation that
+	// holds the suggested size of the volume mounted at path.
+	suggestions := make([]string, 0, len(volumes))
+	for name, path := range volumes {
+		suggestions = append(suggestions, fmt.Sprintf("%q", name+"="+path))
+	}
+	slices.Sort(suggestions)
+
 	script := fmt.Sprintf(`
-# Parameters for curl when managing autogrow annotation.
+# Parameters for curl when managing annotations.
 APISERVER="https://kubernetes.default.svc"
 SERVICEACCOUNT="/var/run/secrets/kubernetes.io/serviceaccount"
 NAMESPACE=$(cat ${SERVICEACCOUNT}/namespace)
@@ -188,8 +228,11 @@ TOKEN=$(cat ${SERVICEACCOUNT}/token)
 CACERT=${SERVICEACCOUNT}/ca.crt
 
 declare -r directory=%q
+declare -ra volumes=(%s)
 exec {fd}<> <(:||:)
 while read -r -t 5 -u "${fd}" ||:; do
+  read -r trusted _ < <(sha256sum "${directory}/ca.crt") ||:
+
   # Manage replication certificate.
   if [[ "${directory}" -nt "/proc/self/fd/${fd}" ]] &&
     install -D --mode=0600 -t %q "${directory}"/{%s,%s,%s} &&
@@ -199,27 +242,38 @@ while read -r -t 5 -u "${fd}" ||:; do
     stat --format='Loaded certificates dated %%y' "${directory}"
   fi
 
-  # Manage autogrow annotation.
-  # Return size in Mebibytes.
-  size=$(df --human-readable --block-size=M /pgdata | awk 'FNR == 2 {print $2}')
-  use=$(df --human-readable /pgdata | awk 'FNR == 2 {print $5}')
-  sizeInt="${size//M/}"
-  # Use the sed punctuation class, because the shell will not accept the percent sign in an expansion.
-  useInt=$(echo $use | sed 's/[[:punct:]]//g')
-  triggerExpansion="$((useInt > 75))"
-  if [ $triggerExpansion -eq 1 ]; then
-    newSize="$(((sizeInt / 2)+sizeInt))"
-    newSizeMi="${newSize}Mi"
-    d='[{"op": "add", "path": "/metadata/annotations/suggested-pgdata-pvc-size", "value": "'"$newSizeMi"'"}]'
-    curl --cacert ${CACERT} --header "Authorization: Bearer ${TOKEN}" -XPATCH "${APISERVER}/api/v1/namespaces/${NAMESPACE}/pods/${HOSTNAME}?fieldManager=kubectl-annotate" -H "Content-Type: application/json-patch+json" --data "$d"
+  # Manage trusted authorities annotation.
+  if [[ "${trusted}" != "${reported-}" ]]; then
+    d='[{"op": "add", "path": "/metadata/annotations/%s", "value": "'"${trusted}"'"}]'
+    curl --fail --silent --cacert ${CACERT} --header "Authorization: Bearer ${TOKEN}" -XPATCH "${APISERVER}/api/v1/namespaces/${NAMESPACE}/pods/${HOSTNAME}?fieldManager=kubectl-annotate" -H "Content-Type: application/json-patch+json" --data "$d" >/dev/null &&
+      reported="${trusted}"
   fi
+
+  # Manage autogrow annotations.
+  for volume in "${volumes[@]}"; do
+    # Return size in Mebibytes.
+    size=$(df --human-readable --block-size=M "${volume#*=}" | awk 'FNR == 2 {print $2}')
+    use=$(df --human-readable "${volume#*=}" | awk 'FNR == 2 {print $5}')
+    sizeInt="${size//M/}"
+    # Use the sed punctuation class, because the shell will not accept the percent sign in an expansion.
+    useInt=$(echo $use | sed 's/[[:punct:]]//g')
+    triggerExpansion="$((useInt > 75))"
+    if [ $triggerExpansion -eq 1 ]; then
+      newSize="$(((sizeInt / 2)+sizeInt))"
+      newSizeMi="${newSize}Mi"
+      d='[{"op": "add", "path": "/metadata/annotations/suggested-'"${volume%%=*}"'-pvc-size", "value": "'"$newSizeMi"'"}]'
+      curl --cacert ${CACERT} --header "Authorization: Bearer ${TOKEN}" -XPATCH "${APISERVER}/api/v1/namespaces/${NAMESPACE}/pods/${HOSTNAME}?fieldManager=kubectl-annotate" -H "Content-Type: application/json-patch+json" --data "$d"
+    fi
+  done
 done
 `,
 		naming.CertMountPath,
+		strings.Join(suggestions, " "),
 		naming.ReplicationTmp,
 		naming.ReplicationCertPath,
 		naming.ReplicationPrivateKeyPath,
 		naming.ReplicationCACertPath,
+		strings.ReplaceAll(naming.TrustedCertificateAuthorities, "/", "~1"),
 	)
 
 	// Elide the above script from `ps` and `top` by wrapping it in a function
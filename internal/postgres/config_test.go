@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -51,6 +52,51 @@ func TestWALDirectory(t *testing.T) {
 	assert.Equal(t, WALDirectory(cluster, instance), "/pgwal/pg13_wal")
 }
 
+func TestDataVolumeUsed(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Output", func(t *testing.T) {
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			assert.Assert(t, stdin == nil)
+			assert.DeepEqual(t, command,
+				[]string{"df", "--block-size=1", "--output=used", "/pgdata"})
+
+			_, _ = stdout.Write([]byte("       Used\n 1073741824\n"))
+			return nil
+		}
+
+		used, err := DataVolumeUsed(ctx, exec)
+		assert.NilError(t, err)
+		assert.Equal(t, used, int64(1073741824))
+	})
+
+	t.Run("Unexpected", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte("nope"))
+			return nil
+		}
+
+		_, err := DataVolumeUsed(ctx, exec)
+		assert.ErrorContains(t, err, "unexpected output")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, _ io.Reader, _, _ io.Writer, _ ...string,
+		) error {
+			return expected
+		}
+
+		_, err := DataVolumeUsed(ctx, exec)
+		assert.Equal(t, err, expected)
+	})
+}
+
 func TestBashHalt(t *testing.T) {
 	t.Run("NoPipeline", func(t *testing.T) {
 		cmd := exec.Command("bash")
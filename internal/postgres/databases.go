@@ -8,15 +8,26 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"strings"
 
 	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
+// databaseRecord is the definition of the JSON fields of each database
+// specification, in a form accepted by "json_to_record".
+// - https://www.postgresql.org/docs/current/functions-json.html
+const databaseRecord = `spec(
+       database text, owner text, template text, encoding text,
+       lc_collate text, lc_ctype text, tablespace text, connection_limit integer)`
+
 // CreateDatabasesInPostgreSQL calls exec to create databases that do not exist
-// in PostgreSQL.
+// in PostgreSQL. It returns how the databases that do exist differ from their
+// specifications, keyed by database name. Existing databases are not changed
+// except to give one owned by the bootstrap superuser to its specified owner.
 func CreateDatabasesInPostgreSQL(
-	ctx context.Context, exec Executor, databases []string,
-) error {
+	ctx context.Context, exec Executor, databases []v1beta1.PostgresDatabaseSpec,
+) (map[string][]string, error) {
 	log := logging.FromContext(ctx)
 
 	var err error
@@ -39,25 +50,116 @@ CREATE TEMPORARY TABLE input (id serial, data json);
 	encoder.SetEscapeHTML(false)
 
 	for i := range databases {
+		spec := map[string]any{
+			"database": databases[i].Name,
+		}
+		if databases[i].Owner != nil {
+			spec["owner"] = *databases[i].Owner
+		}
+		if databases[i].Template != nil {
+			spec["template"] = *databases[i].Template
+		}
+		if databases[i].Encoding != nil {
+			spec["encoding"] = *databases[i].Encoding
+		}
+		if databases[i].LCCollate != nil {
+			spec["lc_collate"] = *databases[i].LCCollate
+		}
+		if databases[i].LCCType != nil {
+			spec["lc_ctype"] = *databases[i].LCCType
+		}
+		if databases[i].Tablespace != nil {
+			spec["tablespace"] = *databases[i].Tablespace
+		}
+		if databases[i].ConnectionLimit != nil {
+			spec["connection_limit"] = *databases[i].ConnectionLimit
+		}
 		if err == nil {
-			err = encoder.Encode(map[string]any{
-				"database": databases[i],
-			})
+			err = encoder.Encode(spec)
 		}
 	}
 	_, _ = sql.WriteString(`\.` + "\n")
 
-	// Create databases that do not already exist.
+	// Create databases that do not already exist. Assign an owner only when
+	// that role exists; it might be created after its database.
 	// - https://www.postgresql.org/docs/current/sql-createdatabase.html
 	_, _ = sql.WriteString(`
-SELECT pg_catalog.format('CREATE DATABASE %I',
-       pg_catalog.json_extract_path_text(input.data, 'database'))
-  FROM input
+SELECT pg_catalog.concat_ws(' ',
+       pg_catalog.format('CREATE DATABASE %I', spec.database),
+       CASE WHEN spec.owner IN (SELECT rolname FROM pg_catalog.pg_roles)
+            THEN pg_catalog.format('OWNER %I', spec.owner) END,
+       CASE WHEN spec.template IS NOT NULL
+            THEN pg_catalog.format('TEMPLATE %I', spec.template) END,
+       CASE WHEN spec.encoding IS NOT NULL
+            THEN pg_catalog.format('ENCODING %L', spec.encoding) END,
+       CASE WHEN spec.lc_collate IS NOT NULL
+            THEN pg_catalog.format('LC_COLLATE %L', spec.lc_collate) END,
+       CASE WHEN spec.lc_ctype IS NOT NULL
+            THEN pg_catalog.format('LC_CTYPE %L', spec.lc_ctype) END,
+       CASE WHEN spec.tablespace IS NOT NULL
+            THEN pg_catalog.format('TABLESPACE %I', spec.tablespace) END,
+       CASE WHEN spec.connection_limit IS NOT NULL
+            THEN pg_catalog.format('CONNECTION LIMIT %s', spec.connection_limit) END)
+  FROM input, pg_catalog.json_to_record(input.data) AS ` + databaseRecord + `
  WHERE NOT EXISTS (
-       SELECT 1 FROM pg_catalog.pg_database
-       WHERE datname = pg_catalog.json_extract_path_text(input.data, 'database'))
+       SELECT 1 FROM pg_catalog.pg_database WHERE datname = spec.database)
+ ORDER BY input.id
+\gexec
+`)
+
+	// Give databases to their owner when they are still owned by the bootstrap
+	// superuser, OID 10. This happens when a database is created before its owner.
+	// - https://www.postgresql.org/docs/current/sql-alterdatabase.html
+	_, _ = sql.WriteString(`
+SELECT pg_catalog.format('ALTER DATABASE %I OWNER TO %I', spec.database, spec.owner)
+  FROM input, pg_catalog.json_to_record(input.data) AS ` + databaseRecord + `,
+       pg_catalog.pg_database
+ WHERE datname = spec.database
+   AND datdba = 10 AND spec.owner <> pg_catalog.pg_get_userbyid(datdba)
+   AND spec.owner IN (SELECT rolname FROM pg_catalog.pg_roles)
  ORDER BY input.id
 \gexec
+`)
+
+	// Compare existing databases to their specifications. Locale names are
+	// compared after normalizing their codeset the way the C library does, so
+	// "en_US.UTF-8" matches "en_US.utf8". Store the result in a psql variable
+	// and print only that.
+	// - https://www.gnu.org/software/libc/manual/html_node/Locale-Names.html
+	// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-META-COMMAND-GSET
+	_, _ = sql.WriteString(`
+CREATE FUNCTION pg_temp.normalize_locale(text) RETURNS text
+  LANGUAGE SQL IMMUTABLE STRICT AS $$
+SELECT pg_catalog.concat(
+       pg_catalog.regexp_replace($1, '[.@].*$', ''),
+       '.' || pg_catalog.lower(pg_catalog.regexp_replace(
+              pg_catalog.substring($1, '\.([^@]*)'), '[^[:alnum:]]', '', 'g')),
+       pg_catalog.substring($1, '@.*$'))
+$$;
+SELECT COALESCE(pg_catalog.json_object_agg(spec.database, differences.drift), '{}') AS drift
+  FROM input, pg_catalog.json_to_record(input.data) AS ` + databaseRecord + `,
+       pg_catalog.pg_database AS db, pg_catalog.pg_tablespace AS ts,
+       LATERAL (SELECT pg_catalog.array_remove(ARRAY[
+       CASE WHEN spec.owner <> pg_catalog.pg_get_userbyid(db.datdba)
+            THEN pg_catalog.format('owner is %s, not %s',
+                 pg_catalog.pg_get_userbyid(db.datdba), spec.owner) END,
+       CASE WHEN pg_catalog.pg_char_to_encoding(spec.encoding) <> db.encoding
+            THEN pg_catalog.format('encoding is %s, not %s',
+                 pg_catalog.pg_encoding_to_char(db.encoding), spec.encoding) END,
+       CASE WHEN pg_temp.normalize_locale(spec.lc_collate) <> pg_temp.normalize_locale(db.datcollate)
+            THEN pg_catalog.format('lcCollate is %s, not %s', db.datcollate, spec.lc_collate) END,
+       CASE WHEN pg_temp.normalize_locale(spec.lc_ctype) <> pg_temp.normalize_locale(db.datctype)
+            THEN pg_catalog.format('lcCType is %s, not %s', db.datctype, spec.lc_ctype) END,
+       CASE WHEN spec.tablespace <> ts.spcname
+            THEN pg_catalog.format('tablespace is %s, not %s', ts.spcname, spec.tablespace) END,
+       CASE WHEN spec.connection_limit <> db.datconnlimit
+            THEN pg_catalog.format('connectionLimit is %s, not %s',
+                 db.datconnlimit, spec.connection_limit) END
+       ]::text[], NULL) AS drift) AS differences
+ WHERE db.datname = spec.database AND ts.oid = db.dattablespace
+   AND pg_catalog.cardinality(differences.drift) > 0
+\gset
+\echo :drift
 `)
 
 	stdout, stderr, err := exec.Exec(ctx, &sql,
@@ -68,5 +170,12 @@ SELECT pg_catalog.format('CREATE DATABASE %I',
 
 	log.V(1).Info("created PostgreSQL databases", "stdout", stdout, "stderr", stderr)
 
-	return err
+	var drift map[string][]string
+	if err == nil {
+		if output := strings.TrimSpace(stdout); output != "" {
+			err = json.Unmarshal([]byte(output), &drift)
+		}
+	}
+
+	return drift, err
 }
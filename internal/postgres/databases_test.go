@@ -13,7 +13,9 @@ import (
 
 	"gotest.tools/v3/assert"
 
+	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 func TestCreateDatabasesInPostgreSQL(t *testing.T) {
@@ -29,7 +31,8 @@ func TestCreateDatabasesInPostgreSQL(t *testing.T) {
 			return expected
 		}
 
-		assert.Equal(t, expected, CreateDatabasesInPostgreSQL(ctx, exec, nil))
+		_, err := CreateDatabasesInPostgreSQL(ctx, exec, nil)
+		assert.Equal(t, expected, err)
 	})
 
 	t.Run("Empty", func(t *testing.T) {
@@ -47,22 +50,86 @@ CREATE TEMPORARY TABLE input (id serial, data json);
 \copy input (data) from stdin with (format text)
 \.
 
-SELECT pg_catalog.format('CREATE DATABASE %I',
-       pg_catalog.json_extract_path_text(input.data, 'database'))
-  FROM input
+SELECT pg_catalog.concat_ws(' ',
+       pg_catalog.format('CREATE DATABASE %I', spec.database),
+       CASE WHEN spec.owner IN (SELECT rolname FROM pg_catalog.pg_roles)
+            THEN pg_catalog.format('OWNER %I', spec.owner) END,
+       CASE WHEN spec.template IS NOT NULL
+            THEN pg_catalog.format('TEMPLATE %I', spec.template) END,
+       CASE WHEN spec.encoding IS NOT NULL
+            THEN pg_catalog.format('ENCODING %L', spec.encoding) END,
+       CASE WHEN spec.lc_collate IS NOT NULL
+            THEN pg_catalog.format('LC_COLLATE %L', spec.lc_collate) END,
+       CASE WHEN spec.lc_ctype IS NOT NULL
+            THEN pg_catalog.format('LC_CTYPE %L', spec.lc_ctype) END,
+       CASE WHEN spec.tablespace IS NOT NULL
+            THEN pg_catalog.format('TABLESPACE %I', spec.tablespace) END,
+       CASE WHEN spec.connection_limit IS NOT NULL
+            THEN pg_catalog.format('CONNECTION LIMIT %s', spec.connection_limit) END)
+  FROM input, pg_catalog.json_to_record(input.data) AS spec(
+       database text, owner text, template text, encoding text,
+       lc_collate text, lc_ctype text, tablespace text, connection_limit integer)
  WHERE NOT EXISTS (
-       SELECT 1 FROM pg_catalog.pg_database
-       WHERE datname = pg_catalog.json_extract_path_text(input.data, 'database'))
+       SELECT 1 FROM pg_catalog.pg_database WHERE datname = spec.database)
  ORDER BY input.id
 \gexec
+
+SELECT pg_catalog.format('ALTER DATABASE %I OWNER TO %I', spec.database, spec.owner)
+  FROM input, pg_catalog.json_to_record(input.data) AS spec(
+       database text, owner text, template text, encoding text,
+       lc_collate text, lc_ctype text, tablespace text, connection_limit integer),
+       pg_catalog.pg_database
+ WHERE datname = spec.database
+   AND datdba = 10 AND spec.owner <> pg_catalog.pg_get_userbyid(datdba)
+   AND spec.owner IN (SELECT rolname FROM pg_catalog.pg_roles)
+ ORDER BY input.id
+\gexec
+
+CREATE FUNCTION pg_temp.normalize_locale(text) RETURNS text
+  LANGUAGE SQL IMMUTABLE STRICT AS $$
+SELECT pg_catalog.concat(
+       pg_catalog.regexp_replace($1, '[.@].*$', ''),
+       '.' || pg_catalog.lower(pg_catalog.regexp_replace(
+              pg_catalog.substring($1, '\.([^@]*)'), '[^[:alnum:]]', '', 'g')),
+       pg_catalog.substring($1, '@.*$'))
+$$;
+SELECT COALESCE(pg_catalog.json_object_agg(spec.database, differences.drift), '{}') AS drift
+  FROM input, pg_catalog.json_to_record(input.data) AS spec(
+       database text, owner text, template text, encoding text,
+       lc_collate text, lc_ctype text, tablespace text, connection_limit integer),
+       pg_catalog.pg_database AS db, pg_catalog.pg_tablespace AS ts,
+       LATERAL (SELECT pg_catalog.array_remove(ARRAY[
+       CASE WHEN spec.owner <> pg_catalog.pg_get_userbyid(db.datdba)
+            THEN pg_catalog.format('owner is %s, not %s',
+                 pg_catalog.pg_get_userbyid(db.datdba), spec.owner) END,
+       CASE WHEN pg_catalog.pg_char_to_encoding(spec.encoding) <> db.encoding
+            THEN pg_catalog.format('encoding is %s, not %s',
+                 pg_catalog.pg_encoding_to_char(db.encoding), spec.encoding) END,
+       CASE WHEN pg_temp.normalize_locale(spec.lc_collate) <> pg_temp.normalize_locale(db.datcollate)
+            THEN pg_catalog.format('lcCollate is %s, not %s', db.datcollate, spec.lc_collate) END,
+       CASE WHEN pg_temp.normalize_locale(spec.lc_ctype) <> pg_temp.normalize_locale(db.datctype)
+            THEN pg_catalog.format('lcCType is %s, not %s', db.datctype, spec.lc_ctype) END,
+       CASE WHEN spec.tablespace <> ts.spcname
+            THEN pg_catalog.format('tablespace is %s, not %s', ts.spcname, spec.tablespace) END,
+       CASE WHEN spec.connection_limit <> db.datconnlimit
+            THEN pg_catalog.format('connectionLimit is %s, not %s',
+                 db.datconnlimit, spec.connection_limit) END
+       ]::text[], NULL) AS drift) AS differences
+ WHERE db.datname = spec.database AND ts.oid = db.dattablespace
+   AND pg_catalog.cardinality(differences.drift) > 0
+\gset
+\echo :drift
 `, "\n"))
 			return nil
 		}
 
-		assert.NilError(t, CreateDatabasesInPostgreSQL(ctx, exec, nil))
+		drift, err := CreateDatabasesInPostgreSQL(ctx, exec, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, drift == nil)
 		assert.Equal(t, calls, 1)
 
-		assert.NilError(t, CreateDatabasesInPostgreSQL(ctx, exec, []string{}))
+		_, err = CreateDatabasesInPostgreSQL(ctx, exec, []v1beta1.PostgresDatabaseSpec{})
+		assert.NilError(t, err)
 		assert.Equal(t, calls, 2)
 	})
 
@@ -79,14 +146,59 @@ SELECT pg_catalog.format('CREATE DATABASE %I',
 \copy input (data) from stdin with (format text)
 {"database":"white space"}
 {"database":"eXaCtLy"}
+{"connection_limit":-1,"database":"app","encoding":"UTF8","lc_collate":"C","lc_ctype":"C","owner":"alice","tablespace":"fast","template":"template0"}
 \.
 `))
 			return nil
 		}
 
-		assert.NilError(t, CreateDatabasesInPostgreSQL(ctx, exec,
-			[]string{"white space", "eXaCtLy"},
-		))
+		_, err := CreateDatabasesInPostgreSQL(ctx, exec, []v1beta1.PostgresDatabaseSpec{
+			{Name: "white space"},
+			{Name: "eXaCtLy"},
+			{
+				Name:            "app",
+				Owner:           initialize.Pointer(v1beta1.PostgresIdentifier("alice")),
+				Encoding:        initialize.String("UTF8"),
+				LCCollate:       initialize.String("C"),
+				LCCType:         initialize.String("C"),
+				Template:        initialize.Pointer(v1beta1.PostgresIdentifier("template0")),
+				ConnectionLimit: initialize.Int32(-1),
+				Tablespace:      initialize.Pointer(v1beta1.PostgresIdentifier("fast")),
+			},
+		})
+		assert.NilError(t, err)
 		assert.Equal(t, calls, 1)
 	})
+
+	t.Run("Drift", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(`{"app":["owner is postgres, not alice","connectionLimit is -1, not 10"]}` + "\n"))
+			return nil
+		}
+
+		drift, err := CreateDatabasesInPostgreSQL(ctx, exec, []v1beta1.PostgresDatabaseSpec{
+			{Name: "app"},
+		})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, drift, map[string][]string{
+			"app": {"owner is postgres, not alice", "connectionLimit is -1, not 10"},
+		})
+	})
+
+	t.Run("NoDrift", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte("{}\n"))
+			return nil
+		}
+
+		drift, err := CreateDatabasesInPostgreSQL(ctx, exec, []v1beta1.PostgresDatabaseSpec{
+			{Name: "app"},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, len(drift), 0)
+	})
 }
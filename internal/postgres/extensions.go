@@ -0,0 +1,171 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// extensionLibraries are the shared libraries of well-known extensions that
+// must be loaded when PostgreSQL starts for those extensions to work. These are
+// used when an extension does not specify its own libraries.
+var extensionLibraries = map[string][]string{
+	"pg_cron":            {"pg_cron"},
+	"pg_squeeze":         {"pg_squeeze"},
+	"pg_stat_monitor":    {"pg_stat_monitor"},
+	"pg_stat_statements": {"pg_stat_statements"},
+	"pgaudit":            {"pgaudit"},
+	"pgnodemx":           {"pgnodemx"},
+	"timescaledb":        {"timescaledb"},
+}
+
+// SetExtensionLibraries appends to "shared_preload_libraries" the libraries
+// needed by extensions in the databases of cluster. Libraries already in the
+// list are not added again.
+func SetExtensionLibraries(cluster *v1beta1.PostgresCluster, outParameters *Parameters) {
+	loaded := sets.New[string]()
+	for _, library := range strings.Split(outParameters.Mandatory.Value("shared_preload_libraries"), ",") {
+		loaded.Insert(strings.TrimSpace(library))
+	}
+
+	for _, database := range cluster.Spec.Databases {
+		for _, extension := range database.Extensions {
+			libraries := extension.Libraries
+			if libraries == nil {
+				libraries = extensionLibraries[string(extension.Name)]
+			}
+			for _, library := range libraries {
+				if !loaded.Has(library) {
+					loaded.Insert(library)
+					outParameters.Mandatory.AppendToList("shared_preload_libraries", library)
+				}
+			}
+		}
+	}
+}
+
+// UpdateExtensionsInPostgreSQL calls exec to create extensions that do not
+// exist in database and to update those that have a different version. It
+// returns the installed version of each extension and the error of each
+// extension that could not be created or updated, keyed by name. One failing
+// extension does not prevent the others.
+// - https://www.postgresql.org/docs/current/sql-createextension.html
+// - https://www.postgresql.org/docs/current/sql-alterextension.html
+func UpdateExtensionsInPostgreSQL(
+	ctx context.Context, exec Executor, database string,
+	extensions []v1beta1.PostgresExtensionSpec,
+) (installed, failed map[string]string, err error) {
+	log := logging.FromContext(ctx)
+
+	specs := make([]map[string]any, 0, len(extensions))
+	for i := range extensions {
+		spec := map[string]any{"name": extensions[i].Name}
+		if extensions[i].Version != nil {
+			spec["version"] = *extensions[i].Version
+		}
+		if extensions[i].Schema != nil {
+			spec["schema"] = *extensions[i].Schema
+		}
+		specs = append(specs, spec)
+	}
+
+	encoded, err := json.Marshal(specs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Quiet NOTICE messages. Pass the specification to an anonymous code block
+	// through a custom setting; psql does not interpolate variables there. Each
+	// extension is created or updated in a subtransaction so that its error
+	// can be recorded while the others proceed. Print only the installed
+	// versions and the errors.
+	// - https://www.postgresql.org/docs/current/plpgsql-control-structures.html#PLPGSQL-ERROR-TRAPPING
+	// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-META-COMMAND-GSET
+	const sql = `
+SET client_min_messages = WARNING;
+SET pgo.extensions TO :'extensions';
+DO $$
+DECLARE
+  failed jsonb := '{}';
+  installed text;
+  spec record;
+BEGIN
+  FOR spec IN
+    SELECT * FROM pg_catalog.json_to_recordset(
+      pg_catalog.current_setting('pgo.extensions')::json
+    ) AS spec(name text, version text, schema text)
+  LOOP
+    BEGIN
+      SELECT extversion INTO installed
+        FROM pg_catalog.pg_extension WHERE extname = spec.name;
+
+      IF NOT FOUND THEN
+        EXECUTE pg_catalog.concat_ws(' ',
+          pg_catalog.format('CREATE EXTENSION %I', spec.name),
+          CASE WHEN spec.schema IS NOT NULL
+               THEN pg_catalog.format('SCHEMA %I', spec.schema) END,
+          CASE WHEN spec.version IS NOT NULL
+               THEN pg_catalog.format('VERSION %L', spec.version) END,
+          'CASCADE');
+      ELSIF installed <> spec.version THEN
+        EXECUTE pg_catalog.format('ALTER EXTENSION %I UPDATE TO %L', spec.name, spec.version);
+      END IF;
+    EXCEPTION WHEN OTHERS THEN
+      failed := failed || pg_catalog.jsonb_build_object(spec.name, SQLERRM);
+    END;
+  END LOOP;
+  PERFORM pg_catalog.set_config('pgo.extensions_failed', failed::text, false);
+END
+$$;
+SELECT pg_catalog.json_build_object(
+         'installed', (
+           SELECT COALESCE(pg_catalog.json_object_agg(extname, extversion), '{}')
+             FROM pg_catalog.json_to_recordset(:'extensions') AS spec(name text),
+                  pg_catalog.pg_extension
+            WHERE extname = spec.name),
+         'failed', pg_catalog.current_setting('pgo.extensions_failed')::json
+       ) AS result
+\gset
+\echo :result
+`
+
+	stdout, stderr, err := exec.ExecInDatabasesFromQuery(ctx,
+		`SELECT datname FROM pg_catalog.pg_database WHERE datallowconn AND datname = :'database'`,
+		sql,
+		map[string]string{
+			"database":   database,
+			"extensions": string(encoded),
+
+			"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+			"QUIET":         "on", // Do not print successful statements to stdout.
+		})
+
+	log.V(1).Info("updated PostgreSQL extensions", "stdout", stdout, "stderr", stderr)
+
+	var result struct {
+		Installed map[string]string `json:"installed"`
+		Failed    map[string]string `json:"failed"`
+	}
+	// The result is the last line of output; psql prints the results of any
+	// other queries before it.
+	if err == nil {
+		output := strings.TrimSpace(stdout)
+		if i := strings.LastIndexByte(output, '\n'); i >= 0 {
+			output = output[i+1:]
+		}
+		if output != "" {
+			err = json.Unmarshal([]byte(output), &result)
+		}
+	}
+
+	return result.Installed, result.Failed, err
+}
@@ -0,0 +1,127 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestSetExtensionLibraries(t *testing.T) {
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Spec.Databases = []v1beta1.PostgresDatabaseSpec{
+		{Name: "one", Extensions: []v1beta1.PostgresExtensionSpec{
+			{Name: "pg_cron"}, {Name: "pg_trgm"}, {Name: "pgaudit"},
+		}},
+		{Name: "two", Extensions: []v1beta1.PostgresExtensionSpec{
+			{Name: "pg_cron"}, {Name: "timescaledb"},
+		}},
+	}
+
+	parameters := NewParameters()
+	parameters.Mandatory.AppendToList("shared_preload_libraries", "pgaudit")
+
+	SetExtensionLibraries(cluster, &parameters)
+	assert.Equal(t, parameters.Mandatory.Value("shared_preload_libraries"),
+		"pgaudit,pg_cron,timescaledb")
+
+	t.Run("Specified", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Databases[1].Extensions[1].Libraries = []string{"timescaledb-2.14.2"}
+		cluster.Spec.Databases[1].Extensions = append(cluster.Spec.Databases[1].Extensions,
+			v1beta1.PostgresExtensionSpec{Name: "custom", Libraries: []string{"custom", "pg_cron"}})
+
+		parameters := NewParameters()
+		SetExtensionLibraries(cluster, &parameters)
+		assert.Equal(t, parameters.Mandatory.Value("shared_preload_libraries"),
+			"pg_cron,pgaudit,timescaledb-2.14.2,custom")
+	})
+}
+
+func TestUpdateExtensionsInPostgreSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(string(b), "CREATE EXTENSION %I"))
+			assert.Assert(t, cmp.Contains(string(b), "ALTER EXTENSION %I UPDATE TO %L"))
+			assert.Assert(t, cmp.Contains(string(b), "EXCEPTION WHEN OTHERS"),
+				"expected each extension to be attempted")
+			assert.Assert(t, cmp.Contains(string(b), `\echo :result`))
+			assert.Assert(t, !strings.Contains(string(b), "SELECT pg_catalog.set_config"),
+				"expected no other query results")
+
+			args := strings.Join(command, "\n")
+			assert.Assert(t, cmp.Contains(args, "datname = :'database'"))
+			assert.Assert(t, cmp.Contains(args, "--set=database=app"))
+			assert.Assert(t, cmp.Contains(args,
+				`--set=extensions=[{"name":"pg_trgm"},{"name":"postgis","schema":"gis","version":"3.4.2"}]`))
+			return expected
+		}
+
+		_, _, err := UpdateExtensionsInPostgreSQL(ctx, exec, "app", []v1beta1.PostgresExtensionSpec{
+			{Name: "pg_trgm"},
+			{
+				Name:    "postgis",
+				Version: initialize.String("3.4.2"),
+				Schema:  initialize.Pointer(v1beta1.PostgresIdentifier("gis")),
+			},
+		})
+		assert.Equal(t, expected, err)
+	})
+
+	t.Run("Installed", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(`{"installed":{"pg_trgm":"1.6"},` +
+				`"failed":{"missing":"extension \"missing\" is not available"}}` + "\n"))
+			return nil
+		}
+
+		installed, failed, err := UpdateExtensionsInPostgreSQL(ctx, exec, "app",
+			[]v1beta1.PostgresExtensionSpec{{Name: "pg_trgm"}, {Name: "missing"}})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, installed, map[string]string{"pg_trgm": "1.6"})
+		assert.DeepEqual(t, failed, map[string]string{"missing": `extension "missing" is not available`})
+	})
+
+	t.Run("OtherOutput", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(strings.Join([]string{
+				` set_config `,
+				`------------`,
+				` `,
+				`(1 row)`,
+				``,
+				`{"installed" : {"pg_trgm" : "1.6"}, "failed" : {}}`,
+				``,
+			}, "\n")))
+			return nil
+		}
+
+		installed, failed, err := UpdateExtensionsInPostgreSQL(ctx, exec, "app",
+			[]v1beta1.PostgresExtensionSpec{{Name: "pg_trgm"}})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, installed, map[string]string{"pg_trgm": "1.6"})
+		assert.DeepEqual(t, failed, map[string]string{})
+	})
+}
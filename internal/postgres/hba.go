@@ -5,8 +5,17 @@
 package postgres
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"path"
+	"slices"
 	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 // NewHBAs returns HostBasedAuthentication records required by this package.
@@ -35,6 +44,25 @@ func NewHBAs() HBAs {
 	}
 }
 
+// UserCertificateHBAs appends to outHBAs records that require users of cluster
+// that have client certificates to authenticate with them over TLS. Those
+// certificates are not issued when cluster has a custom TLS Secret.
+//
+// NOTE: PgBouncer connects to PostgreSQL with its own certificate, if any, so
+// these users cannot log in through it. Their connections are indistinguishable
+// from others by address, so they are not exempt.
+func UserCertificateHBAs(cluster *v1beta1.PostgresCluster, outHBAs *HBAs) {
+	if cluster.Spec.CustomTLSSecret != nil {
+		return
+	}
+	for _, user := range cluster.Spec.Users {
+		if user.ClientCertificate {
+			outHBAs.Mandatory = append(outHBAs.Mandatory,
+				*NewHBA().TLS().User(string(user.Name)).Method("cert"))
+		}
+	}
+}
+
 // HBAs is a pairing of HostBasedAuthentication records.
 type HBAs struct{ Mandatory, Default []HostBasedAuthentication }
 
@@ -42,6 +70,18 @@ type HBAs struct{ Mandatory, Default []HostBasedAuthentication }
 // - https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
 type HostBasedAuthentication struct {
 	origin, database, user, address, method, options string
+
+	include string
+}
+
+// NewHBAInclude returns an HBA record that includes the records in file. This
+// requires PostgreSQL 16 or later. When file does not exist, PostgreSQL does
+// not start and keeps its previous records during reload.
+// - https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
+func NewHBAInclude(file string) *HostBasedAuthentication {
+	hba := new(HostBasedAuthentication)
+	hba.include = hba.quote(file)
+	return hba
 }
 
 // NewHBA returns an HBA record that matches all databases, networks, and users.
@@ -101,11 +141,17 @@ func (hba *HostBasedAuthentication) NoSSL() *HostBasedAuthentication {
 	return hba
 }
 
-// Options specifies any options for the authentication method.
+// Options specifies any options for the authentication method. They are
+// sorted by name so that the record is always the same.
 func (hba *HostBasedAuthentication) Options(opts map[string]string) *HostBasedAuthentication {
 	hba.options = ""
-	for k, v := range opts {
-		hba.options = fmt.Sprintf("%s %s=%s", hba.options, k, hba.quote(v))
+	keys := make([]string, 0, len(opts))
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		hba.options = fmt.Sprintf("%s %s=%s", hba.options, k, hba.quote(opts[k]))
 	}
 	return hba
 }
@@ -149,6 +195,9 @@ func (hba *HostBasedAuthentication) User(name string) *HostBasedAuthentication {
 
 // String returns hba formatted for the pg_hba.conf file without a newline.
 func (hba HostBasedAuthentication) String() string {
+	if hba.include != "" {
+		return "include " + hba.include
+	}
 	if hba.origin == "local" {
 		return strings.TrimSpace(fmt.Sprintf("local %s %s %s %s",
 			hba.database, hba.user, hba.method, hba.options))
@@ -157,3 +206,107 @@ func (hba HostBasedAuthentication) String() string {
 	return strings.TrimSpace(fmt.Sprintf("%s %s %s %s %s %s",
 		hba.origin, hba.database, hba.user, hba.address, hba.method, hba.options))
 }
+
+// requiredHBAOptions are the options that each authentication method needs
+// to work. LDAP needs either "ldapserver" or "ldapurl".
+// - https://www.postgresql.org/docs/current/auth-ldap.html
+// - https://www.postgresql.org/docs/current/auth-radius.html
+var requiredHBAOptions = map[string][][]string{
+	"ldap":   {{"ldapserver", "ldapurl"}},
+	"radius": {{"radiusservers"}, {"radiussecrets"}},
+}
+
+// NewHBAFromSpec returns the HBA record of rule. The values of its Secret
+// options are in secretOptions, keyed by option name. It returns an error
+// when rule cannot work as written.
+func NewHBAFromSpec(
+	rule v1beta1.PostgresHBARuleSpec, secretOptions map[string]string,
+) (*HostBasedAuthentication, error) {
+	hba := NewHBA().Method(rule.Method)
+
+	switch rule.Connection {
+	case v1beta1.PostgresHBALocal:
+		hba.Local()
+	case v1beta1.PostgresHBAHost:
+		hba.TCP()
+	case v1beta1.PostgresHBAHostNoSSL:
+		hba.NoSSL()
+	default:
+		hba.TLS()
+	}
+
+	switch rule.Address {
+	case "", "all":
+	case "samehost", "samenet":
+		hba.address = rule.Address
+	default:
+		if _, _, err := net.ParseCIDR(rule.Address); err != nil {
+			return nil, fmt.Errorf("address %q is not in CIDR notation", rule.Address)
+		}
+		hba.Network(rule.Address)
+	}
+
+	if len(rule.Databases) > 0 {
+		names := make([]string, len(rule.Databases))
+		for i := range rule.Databases {
+			names[i] = hba.quote(string(rule.Databases[i]))
+		}
+		hba.database = strings.Join(names, ",")
+	}
+
+	if len(rule.Users)+len(rule.Roles) > 0 {
+		names := make([]string, 0, len(rule.Users)+len(rule.Roles))
+		for i := range rule.Users {
+			names = append(names, hba.quote(string(rule.Users[i])))
+		}
+		for i := range rule.Roles {
+			names = append(names, "+"+hba.quote(string(rule.Roles[i])))
+		}
+		hba.user = strings.Join(names, ",")
+	}
+
+	options := make(map[string]string, len(rule.Options)+len(secretOptions))
+	for k, v := range rule.Options {
+		options[k] = v
+	}
+	for k, v := range secretOptions {
+		options[k] = v
+	}
+
+	var errs []error
+	for _, choices := range requiredHBAOptions[rule.Method] {
+		if !sets.KeySet(options).HasAny(choices...) {
+			errs = append(errs, fmt.Errorf("%s requires the %s option",
+				rule.Method, strings.Join(choices, " or ")))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if len(options) > 0 {
+		hba.Options(options)
+	}
+	return hba, nil
+}
+
+// HBASecretOptions returns whether or not any rule of cluster has options
+// that come from Secrets. Those rules are in files of a Secret that is
+// mounted in instance Pods.
+func HBASecretOptions(cluster *v1beta1.PostgresCluster) bool {
+	if cluster.Spec.Authentication != nil {
+		for _, rule := range cluster.Spec.Authentication.Rules {
+			if len(rule.SecretOptions) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HBAFilePath returns the path in instance Pods of the file called name in
+// the Secret of pg_hba.conf records. It is in the certificate volume so that
+// PostgreSQL reloads when the file changes.
+func HBAFilePath(name string) string {
+	return path.Join(naming.CertMountPath, name)
+}
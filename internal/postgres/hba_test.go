@@ -9,8 +9,10 @@ import (
 	"testing"
 
 	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 func TestNewHBAs(t *testing.T) {
@@ -59,4 +61,106 @@ func TestHostBasedAuthentication(t *testing.T) {
 
 	assert.Equal(t, `hostnossl all all all reject`,
 		NewHBA().NoSSL().Method("reject").String())
+
+	assert.Equal(t, `include "/pgconf/tls/some ""file"".conf"`,
+		NewHBAInclude(`/pgconf/tls/some "file".conf`).String())
+}
+
+func TestNewHBAFromSpec(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		hba, err := NewHBAFromSpec(v1beta1.PostgresHBARuleSpec{Method: "scram-sha-256"}, nil)
+		assert.NilError(t, err)
+		assert.Equal(t, hba.String(), `hostssl all all all scram-sha-256`)
+	})
+
+	t.Run("Match", func(t *testing.T) {
+		hba, err := NewHBAFromSpec(v1beta1.PostgresHBARuleSpec{
+			Connection: "host",
+			Databases:  []v1beta1.PostgresIdentifier{"app", "has\"quote"},
+			Users:      []v1beta1.PostgresIdentifier{"alice"},
+			Roles:      []v1beta1.PostgresIdentifier{"readers"},
+			Address:    "10.0.0.0/8",
+			Method:     "reject",
+		}, nil)
+		assert.NilError(t, err)
+		assert.Equal(t, hba.String(),
+			`host "app","has""quote" "alice",+"readers" "10.0.0.0/8" reject`)
+
+		hba, err = NewHBAFromSpec(v1beta1.PostgresHBARuleSpec{
+			Connection: "hostnossl", Address: "samenet", Method: "md5",
+		}, nil)
+		assert.NilError(t, err)
+		assert.Equal(t, hba.String(), `hostnossl all all samenet md5`)
+
+		hba, err = NewHBAFromSpec(v1beta1.PostgresHBARuleSpec{
+			Connection: "local", Users: []v1beta1.PostgresIdentifier{"app"}, Method: "peer",
+		}, nil)
+		assert.NilError(t, err)
+		assert.Equal(t, hba.String(), `local all "app" peer`)
+	})
+
+	t.Run("Address", func(t *testing.T) {
+		_, err := NewHBAFromSpec(v1beta1.PostgresHBARuleSpec{
+			Address: "10.0.0.1", Method: "md5",
+		}, nil)
+		assert.ErrorContains(t, err, `"10.0.0.1" is not in CIDR`)
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		hba, err := NewHBAFromSpec(v1beta1.PostgresHBARuleSpec{
+			Method: "ldap",
+			Options: map[string]string{
+				"ldapserver":     "ldap.example.com",
+				"ldapbinddn":     "cn=postgres",
+				"ldapbindpasswd": "overridden",
+			},
+		}, map[string]string{"ldapbindpasswd": "secret"})
+		assert.NilError(t, err)
+		assert.Equal(t, hba.String(), `hostssl all all all ldap `+
+			` ldapbinddn="cn=postgres" ldapbindpasswd="secret" ldapserver="ldap.example.com"`)
+
+		hba, err = NewHBAFromSpec(v1beta1.PostgresHBARuleSpec{
+			Method:  "cert",
+			Options: map[string]string{"map": "certs"},
+		}, nil)
+		assert.NilError(t, err)
+		assert.Equal(t, hba.String(), `hostssl all all all cert  map="certs"`)
+	})
+
+	t.Run("RequiredOptions", func(t *testing.T) {
+		_, err := NewHBAFromSpec(v1beta1.PostgresHBARuleSpec{Method: "ldap"}, nil)
+		assert.ErrorContains(t, err, "ldap requires the ldapserver or ldapurl option")
+
+		_, err = NewHBAFromSpec(v1beta1.PostgresHBARuleSpec{
+			Method: "radius", Options: map[string]string{"radiusservers": "radius.local"},
+		}, nil)
+		assert.ErrorContains(t, err, "radius requires the radiussecrets option")
+
+		_, err = NewHBAFromSpec(v1beta1.PostgresHBARuleSpec{
+			Method: "radius", Options: map[string]string{"radiusservers": "radius.local"},
+		}, map[string]string{"radiussecrets": "shh"})
+		assert.NilError(t, err)
+	})
+}
+
+func TestUserCertificateHBAs(t *testing.T) {
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Spec.Users = []v1beta1.PostgresUserSpec{
+		{Name: "alice", ClientCertificate: true},
+		{Name: "bob"},
+	}
+
+	hbas := HBAs{}
+	UserCertificateHBAs(cluster, &hbas)
+	assert.Equal(t, len(hbas.Mandatory), 1)
+	assert.Equal(t, hbas.Mandatory[0].String(), `hostssl all "alice" all cert`)
+
+	t.Run("CustomTLSSecret", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.CustomTLSSecret = new(corev1.SecretProjection)
+
+		hbas := HBAs{}
+		UserCertificateHBAs(cluster, &hbas)
+		assert.Equal(t, len(hbas.Mandatory), 0)
+	})
 }
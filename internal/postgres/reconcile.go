@@ -86,6 +86,19 @@ func InstancePod(ctx context.Context,
 		},
 	}
 
+	// Rules of pg_hba.conf that have secret options are files in a Secret.
+	// Project them into the certificate volume so that PostgreSQL reloads
+	// when they change.
+	if HBASecretOptions(inCluster) {
+		certVolume.Projected.Sources = append(certVolume.Projected.Sources,
+			corev1.VolumeProjection{Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: naming.PostgresHBASecret(inCluster).Name,
+				},
+				Optional: initialize.Bool(true),
+			}})
+	}
+
 	dataVolumeMount := DataVolumeMount()
 	dataVolume := corev1.Volume{
 		Name: dataVolumeMount.Name,
@@ -178,8 +191,6 @@ func InstancePod(ctx context.Context,
 	reloader := corev1.Container{
 		Name: naming.ContainerClientCertCopy,
 
-		Command: reloadCommand(naming.ContainerClientCertCopy),
-
 		Image:           container.Image,
 		ImagePullPolicy: container.ImagePullPolicy,
 		SecurityContext: initialize.RestrictedSecurityContext(),
@@ -213,6 +224,10 @@ func InstancePod(ctx context.Context,
 		downwardAPIVolume,
 	}
 
+	// The reloader suggests sizes for the PostgreSQL volumes that it mounts.
+	// The keys here are part of the annotations that hold those sizes.
+	autogrowVolumes := map[string]string{"pgdata": dataVolumeMount.MountPath}
+
 	// If `TablespaceVolumes` FeatureGate is enabled, `inTablespaceVolumes` may not be nil.
 	// In that case, add any tablespace volumes to the pod, and
 	// add volumeMounts to the database and startup containers
@@ -230,6 +245,8 @@ func InstancePod(ctx context.Context,
 		outInstancePod.Volumes = append(outInstancePod.Volumes, tablespaceVolume)
 		container.VolumeMounts = append(container.VolumeMounts, tablespaceVolumeMount)
 		startup.VolumeMounts = append(startup.VolumeMounts, tablespaceVolumeMount)
+		reloader.VolumeMounts = append(reloader.VolumeMounts, tablespaceVolumeMount)
+		autogrowVolumes[tablespaceVolumeMount.Name] = tablespaceVolumeMount.MountPath
 	}
 
 	if len(inCluster.Spec.Config.Files) != 0 {
@@ -258,9 +275,13 @@ func InstancePod(ctx context.Context,
 
 		container.VolumeMounts = append(container.VolumeMounts, walVolumeMount)
 		startup.VolumeMounts = append(startup.VolumeMounts, walVolumeMount)
+		reloader.VolumeMounts = append(reloader.VolumeMounts, walVolumeMount)
 		outInstancePod.Volumes = append(outInstancePod.Volumes, walVolume)
+		autogrowVolumes["pgwal"] = walVolumeMount.MountPath
 	}
 
+	reloader.Command = reloadCommand(naming.ContainerClientCertCopy, autogrowVolumes)
+
 	outInstancePod.Containers = []corev1.Container{container, reloader}
 
 	// If the InstanceSidecars feature gate is enabled and instance sidecars are
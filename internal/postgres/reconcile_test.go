@@ -167,7 +167,7 @@ containers:
   - --
   - |-
     monitor() {
-    # Parameters for curl when managing autogrow annotation.
+    # Parameters for curl when managing annotations.
     APISERVER="https://kubernetes.default.svc"
     SERVICEACCOUNT="/var/run/secrets/kubernetes.io/serviceaccount"
     NAMESPACE=$(cat ${SERVICEACCOUNT}/namespace)
@@ -175,8 +175,11 @@ containers:
     CACERT=${SERVICEACCOUNT}/ca.crt
 
     declare -r directory="/pgconf/tls"
+    declare -ra volumes=("pgdata=/pgdata")
     exec {fd}<> <(:||:)
     while read -r -t 5 -u "${fd}" ||:; do
+      read -r trusted _ < <(sha256sum "${directory}/ca.crt") ||:
+
       # Manage replication certificate.
       if [[ "${directory}" -nt "/proc/self/fd/${fd}" ]] &&
         install -D --mode=0600 -t "/tmp/replication" "${directory}"/{replication/tls.crt,replication/tls.key,replication/ca.crt} &&
@@ -186,20 +189,29 @@ containers:
         stat --format='Loaded certificates dated %y' "${directory}"
       fi
 
-      # Manage autogrow annotation.
-      # Return size in Mebibytes.
-      size=$(df --human-readable --block-size=M /pgdata | awk 'FNR == 2 {print $2}')
-      use=$(df --human-readable /pgdata | awk 'FNR == 2 {print $5}')
-      sizeInt="${size//M/}"
-      # Use the sed punctuation class, because the shell will not accept the percent sign in an expansion.
-      useInt=$(echo $use | sed 's/[[:punct:]]//g')
-      triggerExpansion="$((useInt > 75))"
-      if [ $triggerExpansion -eq 1 ]; then
-        newSize="$(((sizeInt / 2)+sizeInt))"
-        newSizeMi="${newSize}Mi"
-        d='[{"op": "add", "path": "/metadata/annotations/suggested-pgdata-pvc-size", "value": "'"$newSizeMi"'"}]'
-        curl --cacert ${CACERT} --header "Authorization: Bearer ${TOKEN}" -XPATCH "${APISERVER}/api/v1/namespaces/${NAMESPACE}/pods/${HOSTNAME}?fieldManager=kubectl-annotate" -H "Content-Type: application/json-patch+json" --data "$d"
+      # Manage trusted authorities annotation.
+      if [[ "${trusted}" != "${reported-}" ]]; then
+        d='[{"op": "add", "path": "/metadata/annotations/postgres-operator.crunchydata.com~1trusted-certificate-authorities", "value": "'"${trusted}"'"}]'
+        curl --fail --silent --cacert ${CACERT} --header "Authorization: Bearer ${TOKEN}" -XPATCH "${APISERVER}/api/v1/namespaces/${NAMESPACE}/pods/${HOSTNAME}?fieldManager=kubectl-annotate" -H "Content-Type: application/json-patch+json" --data "$d" >/dev/null &&
+          reported="${trusted}"
       fi
+
+      # Manage autogrow annotations.
+      for volume in "${volumes[@]}"; do
+        # Return size in Mebibytes.
+        size=$(df --human-readable --block-size=M "${volume#*=}" | awk 'FNR == 2 {print $2}')
+        use=$(df --human-readable "${volume#*=}" | awk 'FNR == 2 {print $5}')
+        sizeInt="${size//M/}"
+        # Use the sed punctuation class, because the shell will not accept the percent sign in an expansion.
+        useInt=$(echo $use | sed 's/[[:punct:]]//g')
+        triggerExpansion="$((useInt > 75))"
+        if [ $triggerExpansion -eq 1 ]; then
+          newSize="$(((sizeInt / 2)+sizeInt))"
+          newSizeMi="${newSize}Mi"
+          d='[{"op": "add", "path": "/metadata/annotations/suggested-'"${volume%=*}"'-pvc-size", "value": "'"$newSizeMi"'"}]'
+          curl --cacert ${CACERT} --header "Authorization: Bearer ${TOKEN}" -XPATCH "${APISERVER}/api/v1/namespaces/${NAMESPACE}/pods/${HOSTNAME}?fieldManager=kubectl-annotate" -H "Content-Type: application/json-patch+json" --data "$d"
+        fi
+      done
     done
     }; export -f monitor; exec -a "$0" bash -ceu monitor
   - replication-cert-copy
@@ -519,6 +531,31 @@ volumes:
   name: postgres-data`), "expected WAL mount, no downwardAPI mount in %q container", pod.InitContainers[0].Name)
 	})
 
+	t.Run("WithHBASecretOptions", func(t *testing.T) {
+		clusterWithRules := cluster.DeepCopy()
+		clusterWithRules.Name = "hippo"
+		clusterWithRules.Spec.Authentication = &v1beta1.PostgresAuthenticationSpec{
+			Rules: []v1beta1.PostgresHBARuleSpec{{
+				Method: "ldap",
+				SecretOptions: map[string]corev1.SecretKeySelector{
+					"ldapbindpasswd": {Key: "password"},
+				},
+			}},
+		}
+
+		pod := new(corev1.PodSpec)
+		InstancePod(ctx, clusterWithRules, instance,
+			serverSecretProjection, clientSecretProjection, dataVolume, nil, nil, pod)
+
+		// The Secret of pg_hba.conf files is in the certificate volume.
+		assert.Equal(t, pod.Volumes[0].Name, "cert-volume")
+		assert.Assert(t, cmp.MarshalMatches(pod.Volumes[0].Projected.Sources[2:], `
+- secret:
+    name: hippo-pg-hba
+    optional: true
+		`))
+	})
+
 	t.Run("WithCustomSidecarContainer", func(t *testing.T) {
 		sidecarInstance := new(v1beta1.PostgresInstanceSetSpec)
 		sidecarInstance.Containers = []corev1.Container{
@@ -604,6 +641,20 @@ volumes:
   name: tablespace-castle
 - mountPath: /tablespaces/trial
   name: tablespace-trial`), "expected tablespace mount(s) in %q container", pod.InitContainers[0].Name)
+
+		// The reloader suggests sizes for tablespace volumes.
+		assert.Assert(t, cmp.MarshalMatches(pod.Containers[1].VolumeMounts, `
+- mountPath: /pgconf/tls
+  name: cert-volume
+  readOnly: true
+- mountPath: /pgdata
+  name: postgres-data
+- mountPath: /tablespaces/castle
+  name: tablespace-castle
+- mountPath: /tablespaces/trial
+  name: tablespace-trial`), "expected tablespace mount(s) in %q container", pod.Containers[1].Name)
+		assert.Assert(t, cmp.Contains(pod.Containers[1].Command[3],
+			`declare -ra volumes=("pgdata=/pgdata" "tablespace-castle=/tablespaces/castle" "tablespace-trial=/tablespaces/trial")`))
 	})
 
 	t.Run("WithWALVolumeWithWALVolumeSpec", func(t *testing.T) {
@@ -641,6 +692,18 @@ volumes:
 - mountPath: /pgwal
   name: postgres-wal`), "expected WAL mount, no downwardAPI mount in %q container", pod.InitContainers[0].Name)
 
+		// The reloader suggests sizes for the WAL volume.
+		assert.Assert(t, cmp.MarshalMatches(pod.Containers[1].VolumeMounts, `
+- mountPath: /pgconf/tls
+  name: cert-volume
+  readOnly: true
+- mountPath: /pgdata
+  name: postgres-data
+- mountPath: /pgwal
+  name: postgres-wal`), "expected WAL mount in %q container", pod.Containers[1].Name)
+		assert.Assert(t, cmp.Contains(pod.Containers[1].Command[3],
+			`declare -ra volumes=("pgdata=/pgdata" "pgwal=/pgwal")`))
+
 		assert.Assert(t, cmp.MarshalMatches(pod.Volumes, `
 - name: cert-volume
   projected:
@@ -0,0 +1,139 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+)
+
+// ApplyLag calls exec to measure the time between now and the last transaction
+// replayed by a standby. It returns false when the server is not a standby or
+// has not replayed any transactions since it started. The value grows while
+// the primary is idle, so it is an upper bound on how far behind the standby is.
+// - https://www.postgresql.org/docs/current/functions-admin.html#FUNCTIONS-RECOVERY-INFO-TABLE
+func ApplyLag(ctx context.Context, exec Executor) (time.Duration, bool, error) {
+	log := logging.FromContext(ctx)
+
+	// Store the result in a psql variable and print only that. The variable
+	// is empty when the value is NULL.
+	// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-META-COMMAND-GSET
+	const sql = `
+SELECT CASE WHEN pg_catalog.pg_is_in_recovery() THEN
+       EXTRACT(EPOCH FROM pg_catalog.clock_timestamp()
+                        - pg_catalog.pg_last_xact_replay_timestamp())::bigint
+       END AS lag
+\gset
+\echo :lag
+`
+
+	stdout, stderr, err := exec.Exec(ctx, strings.NewReader(sql),
+		map[string]string{
+			"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+			"QUIET":         "on", // Do not print successful statements to stdout.
+		})
+
+	log.V(1).Info("measured apply lag", "stdout", stdout, "stderr", stderr)
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	seconds, err := strconv.ParseInt(strings.TrimSpace(stdout), 10, 64)
+	if err != nil {
+		return 0, false, nil
+	}
+	return time.Duration(seconds) * time.Second, true, nil
+}
+
+// ReplicationLag calls exec on a primary to measure how far behind each of its
+// streaming replicas is in replaying WAL. It returns the number of bytes keyed
+// by the "application_name" of each replica. Patroni sets this to the name of
+// the replica's member.
+// - https://www.postgresql.org/docs/current/monitoring-stats.html#MONITORING-PG-STAT-REPLICATION-VIEW
+func ReplicationLag(ctx context.Context, exec Executor) (map[string]int64, error) {
+	log := logging.FromContext(ctx)
+
+	// Store the result in a psql variable and print only that.
+	// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-META-COMMAND-GSET
+	const sql = `
+SELECT COALESCE(pg_catalog.json_object_agg(application_name,
+         pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), replay_lsn)::bigint),
+       '{}') AS replicas
+  FROM pg_catalog.pg_stat_replication
+ WHERE state = 'streaming' AND replay_lsn IS NOT NULL
+\gset
+\echo :replicas
+`
+
+	stdout, stderr, err := exec.Exec(ctx, strings.NewReader(sql),
+		map[string]string{
+			"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+			"QUIET":         "on", // Do not print successful statements to stdout.
+		})
+
+	log.V(1).Info("measured replication lag", "stdout", stdout, "stderr", stderr)
+
+	var lag map[string]int64
+	if err == nil {
+		err = json.Unmarshal([]byte(stdout), &lag)
+	}
+	return lag, err
+}
+
+// WALPosition calls exec to find the last location in the WAL that was written
+// by a primary or replayed by a standby. It also returns the number of bytes
+// that location is past since, which is negative when it is behind. The
+// difference is zero when since is empty. It returns an error when a standby
+// has not replayed any WAL since it started.
+// - https://www.postgresql.org/docs/current/functions-admin.html#FUNCTIONS-RECOVERY-INFO-TABLE
+func WALPosition(ctx context.Context, exec Executor, since string) (string, int64, error) {
+	log := logging.FromContext(ctx)
+
+	// Store the result in a psql variable and print only that. The location
+	// is NULL when a standby has not replayed any WAL.
+	// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-META-COMMAND-GSET
+	const sql = `
+SELECT pg_catalog.json_build_object('lsn', position.lsn, 'past',
+         pg_catalog.pg_wal_lsn_diff(position.lsn,
+           COALESCE(NULLIF(:'since', '')::pg_lsn, position.lsn))::bigint
+       ) AS position
+  FROM (SELECT CASE WHEN pg_catalog.pg_is_in_recovery()
+                    THEN pg_catalog.pg_last_wal_replay_lsn()
+                    ELSE pg_catalog.pg_current_wal_lsn() END AS lsn) AS position
+\gset
+\echo :position
+`
+
+	stdout, stderr, err := exec.Exec(ctx, strings.NewReader(sql),
+		map[string]string{
+			"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+			"QUIET":         "on", // Do not print successful statements to stdout.
+			"since":         since,
+		})
+
+	log.V(1).Info("measured WAL position", "stdout", stdout, "stderr", stderr)
+
+	var position struct {
+		LSN  *string `json:"lsn"`
+		Past int64   `json:"past"`
+	}
+	if err == nil {
+		err = json.Unmarshal([]byte(stdout), &position)
+	}
+	if err == nil && position.LSN == nil {
+		err = errors.New("standby has not replayed any WAL")
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return *position.LSN, position.Past, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestApplyLag(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(string(b), "pg_last_xact_replay_timestamp()"))
+			assert.Assert(t, strings.Contains(string(b), `\echo :lag`))
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			return expected
+		}
+
+		_, known, err := ApplyLag(ctx, exec)
+		assert.Equal(t, expected, err)
+		assert.Assert(t, !known)
+	})
+
+	t.Run("Standby", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte("3605\n"))
+			return nil
+		}
+
+		lag, known, err := ApplyLag(ctx, exec)
+		assert.NilError(t, err)
+		assert.Assert(t, known)
+		assert.Equal(t, lag, time.Hour+5*time.Second)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte("\n"))
+			return nil
+		}
+
+		_, known, err := ApplyLag(ctx, exec)
+		assert.NilError(t, err)
+		assert.Assert(t, !known)
+	})
+}
+
+func TestReplicationLag(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(string(b), "pg_stat_replication"))
+			assert.Assert(t, strings.Contains(string(b), `\echo :replicas`))
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			return expected
+		}
+
+		_, err := ReplicationLag(ctx, exec)
+		assert.Equal(t, expected, err)
+	})
+
+	t.Run("Replicas", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(`{ "pod-1" : 0, "pod-2" : 16777216 }` + "\n"))
+			return nil
+		}
+
+		lag, err := ReplicationLag(ctx, exec)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, lag, map[string]int64{"pod-1": 0, "pod-2": 16777216})
+	})
+
+	t.Run("None", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte("{}\n"))
+			return nil
+		}
+
+		lag, err := ReplicationLag(ctx, exec)
+		assert.NilError(t, err)
+		assert.Equal(t, len(lag), 0)
+	})
+}
+
+func TestWALPosition(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(string(b), "pg_last_wal_replay_lsn()"))
+			assert.Assert(t, strings.Contains(string(b), `\echo :position`))
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			assert.Assert(t, strings.Contains(strings.Join(command, " "), "--set=since=0/3000148"))
+			return expected
+		}
+
+		_, _, err := WALPosition(ctx, exec, "0/3000148")
+		assert.Equal(t, expected, err)
+	})
+
+	t.Run("Behind", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(`{"lsn" : "0/3000060", "past" : -232}` + "\n"))
+			return nil
+		}
+
+		lsn, past, err := WALPosition(ctx, exec, "0/3000148")
+		assert.NilError(t, err)
+		assert.Equal(t, lsn, "0/3000060")
+		assert.Equal(t, past, int64(-232))
+	})
+
+	t.Run("NothingReplayed", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(`{"lsn" : null, "past" : null}` + "\n"))
+			return nil
+		}
+
+		lsn, _, err := WALPosition(ctx, exec, "")
+		assert.ErrorContains(t, err, "not replayed")
+		assert.Equal(t, lsn, "")
+	})
+}
@@ -0,0 +1,273 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// grantablePrivileges are the privileges that apply to each kind of object.
+// - https://www.postgresql.org/docs/current/ddl-priv.html#PRIVILEGE-ABBREVS-TABLE
+var grantablePrivileges = map[string]sets.Set[v1beta1.PostgresPrivilege]{
+	v1beta1.PostgresGrantOnFunctions: sets.New[v1beta1.PostgresPrivilege]("ALL", "EXECUTE"),
+	v1beta1.PostgresGrantOnSchema:    sets.New[v1beta1.PostgresPrivilege]("ALL", "CREATE", "USAGE"),
+	v1beta1.PostgresGrantOnSequences: sets.New[v1beta1.PostgresPrivilege]("ALL", "SELECT", "UPDATE", "USAGE"),
+	v1beta1.PostgresGrantOnTables: sets.New[v1beta1.PostgresPrivilege](
+		"ALL", "DELETE", "INSERT", "REFERENCES", "SELECT", "TRIGGER", "TRUNCATE", "UPDATE"),
+}
+
+// Grant is a set of privileges that Role has on objects in a schema.
+type Grant struct {
+	Role string
+	v1beta1.PostgresGrantSpec
+}
+
+// Membership is a Role that is a member of another role, MemberOf.
+type Membership struct {
+	Role     string `json:"role"`
+	MemberOf string `json:"member_of"`
+}
+
+// ValidateGrant returns an error when grant has privileges that do not apply
+// to its objects. Those privileges are not granted.
+func ValidateGrant(grant v1beta1.PostgresGrantSpec) error {
+	on := grant.On
+	if on == "" {
+		on = v1beta1.PostgresGrantOnSchema
+	}
+
+	var invalid []string
+	for _, privilege := range grant.Privileges {
+		if !grantablePrivileges[on].Has(privilege) {
+			invalid = append(invalid, string(privilege))
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("%s do not apply to %s", strings.Join(invalid, ", "), on)
+	}
+	return nil
+}
+
+// encodeGrants returns the JSON of grants in the form expected by SQL in
+// [WriteGrantsInPostgreSQL]. Only privileges that apply to their objects are
+// included.
+func encodeGrants(grants []Grant) (string, error) {
+	records := make([]map[string]any, 0, len(grants))
+
+	for _, grant := range grants {
+		on := grant.On
+		if on == "" {
+			on = v1beta1.PostgresGrantOnSchema
+		}
+
+		var privileges []string
+		for _, privilege := range grant.Privileges {
+			if grantablePrivileges[on].Has(privilege) {
+				privileges = append(privileges, string(privilege))
+			}
+		}
+		if len(privileges) == 0 {
+			continue
+		}
+
+		records = append(records, map[string]any{
+			"role":       grant.Role,
+			"database":   grant.Database,
+			"schema":     grant.Schema,
+			"objects":    strings.ToUpper(on),
+			"privileges": strings.Join(privileges, ", "),
+			"default":    grant.Default && on != v1beta1.PostgresGrantOnSchema,
+		})
+	}
+
+	encoded, err := json.Marshal(records)
+	return string(encoded), err
+}
+
+// WriteRolesInPostgreSQL calls exec to create group roles that do not exist
+// in PostgreSQL and to update their options. It then revokes the memberships
+// in revoked and grants those in memberships. Memberships of roles that do
+// not exist are ignored.
+// - https://www.postgresql.org/docs/current/role-membership.html
+func WriteRolesInPostgreSQL(
+	ctx context.Context, exec Executor, roles []v1beta1.PostgresRoleSpec,
+	memberships, revoked []Membership,
+) error {
+	log := logging.FromContext(ctx)
+
+	if len(roles) == 0 && len(memberships) == 0 && len(revoked) == 0 {
+		return nil
+	}
+
+	groups := make([]map[string]any, 0, len(roles))
+	for _, role := range roles {
+		groups = append(groups, map[string]any{
+			"name":    role.Name,
+			"options": filterAlterRoleOptions(role.Options, "password", "canlogin"),
+		})
+	}
+
+	// Encode empty lists as JSON arrays rather than null.
+	if memberships == nil {
+		memberships = []Membership{}
+	}
+	if revoked == nil {
+		revoked = []Membership{}
+	}
+
+	encodedGroups, err := json.Marshal(groups)
+	encodedGrants, _ := json.Marshal(memberships)
+	encodedRevokes, _ := json.Marshal(revoked)
+	if err != nil {
+		return err
+	}
+
+	// Quiet the NOTICE about memberships that already exist or do not exist.
+	// The "postgres" superuser and reserved roles are never changed.
+	// - https://www.postgresql.org/docs/current/sql-createrole.html
+	// - https://www.postgresql.org/docs/current/sql-alterrole.html
+	// - https://www.postgresql.org/docs/current/sql-grant.html
+	// - https://www.postgresql.org/docs/current/sql-revoke.html
+	const sql = `
+SET client_min_messages = WARNING;
+SELECT pg_catalog.format('CREATE ROLE %I NOLOGIN', role.name)
+  FROM pg_catalog.json_to_recordset(:'roles') AS role(name text, options text)
+ WHERE role.name <> 'postgres' AND role.name NOT LIKE 'pg\_%'
+   AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_roles WHERE rolname = role.name)
+\gexec
+SELECT pg_catalog.format('ALTER ROLE %I WITH NOLOGIN %s', role.name, role.options)
+  FROM pg_catalog.json_to_recordset(:'roles') AS role(name text, options text)
+ WHERE role.name <> 'postgres' AND role.name NOT LIKE 'pg\_%'
+\gexec
+SELECT pg_catalog.format('REVOKE %I FROM %I', m.member_of, m.role)
+  FROM pg_catalog.json_to_recordset(:'revokes') AS m(role text, member_of text)
+ WHERE m.member_of IN (SELECT rolname FROM pg_catalog.pg_roles)
+   AND m.role IN (SELECT rolname FROM pg_catalog.pg_roles)
+\gexec
+SELECT pg_catalog.format('GRANT %I TO %I', m.member_of, m.role)
+  FROM pg_catalog.json_to_recordset(:'memberships') AS m(role text, member_of text)
+ WHERE m.member_of IN (SELECT rolname FROM pg_catalog.pg_roles)
+   AND m.role IN (SELECT rolname FROM pg_catalog.pg_roles)
+\gexec
+`
+
+	stdout, stderr, err := exec.Exec(ctx, strings.NewReader(sql),
+		map[string]string{
+			"memberships": string(encodedGrants),
+			"revokes":     string(encodedRevokes),
+			"roles":       string(encodedGroups),
+
+			"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+			"QUIET":         "on", // Do not print successful statements to stdout.
+		})
+
+	log.V(1).Info("wrote PostgreSQL roles", "stdout", stdout, "stderr", stderr)
+
+	return err
+}
+
+// WriteGrantsInPostgreSQL calls exec to revoke the privileges in revoked and
+// grant those in grants, including default privileges for objects that schema
+// owners create later. It returns the schemas, as "database.schema", that do
+// not exist yet.
+// - https://www.postgresql.org/docs/current/sql-grant.html
+// - https://www.postgresql.org/docs/current/sql-revoke.html
+// - https://www.postgresql.org/docs/current/sql-alterdefaultprivileges.html
+func WriteGrantsInPostgreSQL(
+	ctx context.Context, exec Executor, grants, revoked []Grant,
+) ([]string, error) {
+	log := logging.FromContext(ctx)
+
+	if len(grants) == 0 && len(revoked) == 0 {
+		return nil, nil
+	}
+
+	databases := sets.New[string]()
+	for _, grant := range append(grants[:len(grants):len(grants)], revoked...) {
+		databases.Insert(string(grant.Database))
+	}
+
+	encodedGrants, err := encodeGrants(grants)
+	encodedRevokes, _ := encodeGrants(revoked)
+	encodedDatabases, _ := json.Marshal(sets.List(databases))
+	if err != nil {
+		return nil, err
+	}
+
+	// Each statement applies only to the current database. Roles and schemas
+	// that do not exist are skipped. Store the missing schemas in a psql
+	// variable and print only that.
+	// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-META-COMMAND-GSET
+	const sql = `
+SET client_min_messages = WARNING;
+SELECT CASE WHEN g.objects = 'SCHEMA'
+            THEN pg_catalog.format('REVOKE %s ON SCHEMA %I FROM %I', g.privileges, g.schema, g.role)
+            ELSE pg_catalog.format('REVOKE %s ON ALL %s IN SCHEMA %I FROM %I',
+                 g.privileges, g.objects, g.schema, g.role) END,
+       CASE WHEN g."default"
+            THEN pg_catalog.format('ALTER DEFAULT PRIVILEGES FOR ROLE %I IN SCHEMA %I REVOKE %s ON %s FROM %I',
+                 pg_catalog.pg_get_userbyid(n.nspowner), g.schema, g.privileges, g.objects, g.role) END
+  FROM pg_catalog.json_to_recordset(:'revokes')
+       AS g(role text, database text, schema text, objects text, privileges text, "default" boolean),
+       pg_catalog.pg_namespace AS n
+ WHERE g.database = pg_catalog.current_database() AND n.nspname = g.schema
+   AND g.role IN (SELECT rolname FROM pg_catalog.pg_roles)
+\gexec
+SELECT CASE WHEN g.objects = 'SCHEMA'
+            THEN pg_catalog.format('GRANT %s ON SCHEMA %I TO %I', g.privileges, g.schema, g.role)
+            ELSE pg_catalog.format('GRANT %s ON ALL %s IN SCHEMA %I TO %I',
+                 g.privileges, g.objects, g.schema, g.role) END,
+       CASE WHEN g."default"
+            THEN pg_catalog.format('ALTER DEFAULT PRIVILEGES FOR ROLE %I IN SCHEMA %I GRANT %s ON %s TO %I',
+                 pg_catalog.pg_get_userbyid(n.nspowner), g.schema, g.privileges, g.objects, g.role) END
+  FROM pg_catalog.json_to_recordset(:'grants')
+       AS g(role text, database text, schema text, objects text, privileges text, "default" boolean),
+       pg_catalog.pg_namespace AS n
+ WHERE g.database = pg_catalog.current_database() AND n.nspname = g.schema
+   AND g.role IN (SELECT rolname FROM pg_catalog.pg_roles)
+\gexec
+SELECT COALESCE(pg_catalog.json_agg(DISTINCT g.database || '.' || g.schema), '[]') AS missing
+  FROM pg_catalog.json_to_recordset(:'grants') AS g(database text, schema text)
+ WHERE g.database = pg_catalog.current_database()
+   AND g.schema NOT IN (SELECT nspname FROM pg_catalog.pg_namespace)
+\gset
+\echo :missing
+`
+
+	stdout, stderr, err := exec.ExecInDatabasesFromQuery(ctx,
+		`SELECT datname FROM pg_catalog.pg_database`+
+			` WHERE datallowconn AND datname IN (SELECT pg_catalog.json_array_elements_text(:'databases'))`,
+		sql,
+		map[string]string{
+			"databases": string(encodedDatabases),
+			"grants":    encodedGrants,
+			"revokes":   encodedRevokes,
+
+			"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+			"QUIET":         "on", // Do not print successful statements to stdout.
+		})
+
+	log.V(1).Info("wrote PostgreSQL grants", "stdout", stdout, "stderr", stderr)
+
+	// Each database prints one JSON array.
+	var missing []string
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		var schemas []string
+		if err == nil && strings.TrimSpace(line) != "" {
+			err = json.Unmarshal([]byte(line), &schemas)
+			missing = append(missing, schemas...)
+		}
+	}
+
+	return missing, err
+}
@@ -0,0 +1,148 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestValidateGrant(t *testing.T) {
+	assert.NilError(t, ValidateGrant(v1beta1.PostgresGrantSpec{
+		Privileges: []v1beta1.PostgresPrivilege{"USAGE", "CREATE"},
+	}))
+	assert.NilError(t, ValidateGrant(v1beta1.PostgresGrantSpec{
+		On: "Tables", Privileges: []v1beta1.PostgresPrivilege{"SELECT", "ALL"},
+	}))
+
+	assert.ErrorContains(t, ValidateGrant(v1beta1.PostgresGrantSpec{
+		On: "Functions", Privileges: []v1beta1.PostgresPrivilege{"EXECUTE", "SELECT", "USAGE"},
+	}), "SELECT, USAGE do not apply to Functions")
+	assert.ErrorContains(t, ValidateGrant(v1beta1.PostgresGrantSpec{
+		Privileges: []v1beta1.PostgresPrivilege{"SELECT"},
+	}), "SELECT do not apply to Schema")
+}
+
+func TestEncodeGrants(t *testing.T) {
+	encoded, err := encodeGrants(nil)
+	assert.NilError(t, err)
+	assert.Equal(t, encoded, `[]`)
+
+	encoded, err = encodeGrants([]Grant{
+		{Role: "app", PostgresGrantSpec: v1beta1.PostgresGrantSpec{
+			Database: "db1", Schema: "s1", Default: true,
+			Privileges: []v1beta1.PostgresPrivilege{"USAGE"},
+		}},
+		{Role: "app", PostgresGrantSpec: v1beta1.PostgresGrantSpec{
+			Database: "db1", Schema: "s1", On: "Tables", Default: true,
+			Privileges: []v1beta1.PostgresPrivilege{"SELECT", "EXECUTE", "INSERT"},
+		}},
+		{Role: "app", PostgresGrantSpec: v1beta1.PostgresGrantSpec{
+			Database: "db1", Schema: "s1", On: "Functions",
+			Privileges: []v1beta1.PostgresPrivilege{"SELECT"},
+		}},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, encoded, `[`+
+		`{"database":"db1","default":false,"objects":"SCHEMA","privileges":"USAGE","role":"app","schema":"s1"},`+
+		`{"database":"db1","default":true,"objects":"TABLES","privileges":"SELECT, INSERT","role":"app","schema":"s1"}`+
+		`]`, "expected privileges that do not apply to be skipped")
+}
+
+func TestWriteRolesInPostgreSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Empty", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, _, _ io.Writer, _ ...string,
+		) error {
+			t.Fatal("expected no calls")
+			return nil
+		}
+
+		assert.NilError(t, WriteRolesInPostgreSQL(ctx, exec, nil, nil, nil))
+	})
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(string(b), `CREATE ROLE %I NOLOGIN`))
+			assert.Assert(t, cmp.Contains(string(b), `REVOKE %I FROM %I`))
+			assert.Assert(t, cmp.Contains(string(b), `GRANT %I TO %I`))
+
+			args := strings.Join(command, " ")
+			assert.Assert(t, cmp.Contains(args,
+				`--set=memberships=[{"role":"alice","member_of":"readers"}]`))
+			assert.Assert(t, cmp.Contains(args, `--set=revokes=[]`))
+			assert.Assert(t, cmp.Contains(args,
+				`--set=roles=[{"name":"readers","options":"CREATEDB"}]`),
+				"expected LOGIN to be removed")
+			return expected
+		}
+
+		assert.Equal(t, expected, WriteRolesInPostgreSQL(ctx, exec,
+			[]v1beta1.PostgresRoleSpec{{Name: "readers", Options: "LOGIN CREATEDB"}},
+			[]Membership{{Role: "alice", MemberOf: "readers"}}, nil))
+	})
+}
+
+func TestWriteGrantsInPostgreSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Empty", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, _, _ io.Writer, _ ...string,
+		) error {
+			t.Fatal("expected no calls")
+			return nil
+		}
+
+		missing, err := WriteGrantsInPostgreSQL(ctx, exec, nil, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, missing == nil)
+	})
+
+	t.Run("Arguments", func(t *testing.T) {
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(string(b), `ALTER DEFAULT PRIVILEGES FOR ROLE %I IN SCHEMA %I GRANT`))
+			assert.Assert(t, cmp.Contains(string(b), `ALTER DEFAULT PRIVILEGES FOR ROLE %I IN SCHEMA %I REVOKE`))
+
+			args := strings.Join(command, " ")
+			assert.Assert(t, cmp.Contains(args, `--set=databases=["db1","db2"]`))
+			assert.Assert(t, cmp.Contains(args, `"role":"app"`))
+			assert.Assert(t, cmp.Contains(args, `"role":"old"`))
+
+			// One line for each database.
+			_, _ = io.WriteString(stdout, "[\"db1.s1\"]\n[]\n")
+			return nil
+		}
+
+		missing, err := WriteGrantsInPostgreSQL(ctx, exec,
+			[]Grant{{Role: "app", PostgresGrantSpec: v1beta1.PostgresGrantSpec{
+				Database: "db1", Schema: "s1", Privileges: []v1beta1.PostgresPrivilege{"USAGE"},
+			}}},
+			[]Grant{{Role: "old", PostgresGrantSpec: v1beta1.PostgresGrantSpec{
+				Database: "db2", Schema: "s1", Privileges: []v1beta1.PostgresPrivilege{"USAGE"},
+			}}})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, missing, []string{"db1.s1"})
+	})
+}
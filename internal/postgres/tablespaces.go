@@ -0,0 +1,205 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// tablespaceRecord is the definition of the JSON fields of each tablespace
+// specification, in a form accepted by "json_to_record".
+// - https://www.postgresql.org/docs/current/functions-json.html
+const tablespaceRecord = `spec(
+       tablespace text, location text, owner text, options json, grants json, remove boolean)`
+
+// Tablespace describes a tablespace that exists in PostgreSQL.
+type Tablespace struct {
+	// Bytes used by the tablespace on disk.
+	Size int64 `json:"size"`
+
+	// Databases that have objects in the tablespace.
+	Databases []string `json:"databases"`
+}
+
+// TablespaceLocation returns the absolute path of the directory in which
+// PostgreSQL stores the tablespace named name.
+func TablespaceLocation(name string) string {
+	return TablespaceVolumeMount(name).MountPath + "/data"
+}
+
+// ManageTablespacesInPostgreSQL calls exec to create tablespaces that do not
+// exist in PostgreSQL and to bring the owner, options, and grants of those that
+// do exist in line with their specifications. Tablespaces named in removed are
+// dropped when no database has objects in them. It returns the size and users
+// of every specified or removed tablespace that exists, keyed by name.
+func ManageTablespacesInPostgreSQL(
+	ctx context.Context, exec Executor,
+	tablespaces []v1beta1.TablespaceVolume, removed []string,
+) (map[string]Tablespace, error) {
+	log := logging.FromContext(ctx)
+
+	var err error
+	var sql bytes.Buffer
+
+	// Prevent unexpected dereferences by emptying "search_path". The "pg_catalog"
+	// schema is still searched, and only temporary objects can be created.
+	// - https://www.postgresql.org/docs/current/runtime-config-client.html#GUC-SEARCH-PATH
+	_, _ = sql.WriteString(`SET search_path TO '';`)
+
+	// Fill a temporary table with the JSON of the tablespace specifications.
+	// "\copy" reads from subsequent lines until the special line "\.".
+	// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-META-COMMANDS-COPY
+	_, _ = sql.WriteString(`
+CREATE TEMPORARY TABLE input (id serial, data json);
+\copy input (data) from stdin with (format text)
+`)
+
+	encoder := json.NewEncoder(&sql)
+	encoder.SetEscapeHTML(false)
+
+	for i := range tablespaces {
+		spec := map[string]any{
+			"tablespace": tablespaces[i].Name,
+			"location":   TablespaceLocation(tablespaces[i].Name),
+			"remove":     false,
+		}
+		if tablespaces[i].Owner != nil {
+			spec["owner"] = *tablespaces[i].Owner
+		}
+		if len(tablespaces[i].Options) > 0 {
+			spec["options"] = tablespaces[i].Options
+		}
+		if len(tablespaces[i].Grants) > 0 {
+			spec["grants"] = tablespaces[i].Grants
+		}
+		if err == nil {
+			err = encoder.Encode(spec)
+		}
+	}
+	for _, name := range removed {
+		if err == nil {
+			err = encoder.Encode(map[string]any{"tablespace": name, "remove": true})
+		}
+	}
+	_, _ = sql.WriteString(`\.` + "\n")
+
+	// Create tablespaces that do not already exist. Assign an owner only when
+	// that role exists; it might be created after its tablespace.
+	// - https://www.postgresql.org/docs/current/sql-createtablespace.html
+	_, _ = sql.WriteString(`
+SELECT pg_catalog.concat_ws(' ',
+       pg_catalog.format('CREATE TABLESPACE %I', spec.tablespace),
+       CASE WHEN spec.owner IN (SELECT rolname FROM pg_catalog.pg_roles)
+            THEN pg_catalog.format('OWNER %I', spec.owner) END,
+       pg_catalog.format('LOCATION %L', spec.location))
+  FROM input, pg_catalog.json_to_record(input.data) AS ` + tablespaceRecord + `
+ WHERE NOT spec.remove AND NOT EXISTS (
+       SELECT 1 FROM pg_catalog.pg_tablespace WHERE spcname = spec.tablespace)
+ ORDER BY input.id
+\gexec
+`)
+
+	// Give tablespaces to their owner after that role exists.
+	// - https://www.postgresql.org/docs/current/sql-altertablespace.html
+	_, _ = sql.WriteString(`
+SELECT pg_catalog.format('ALTER TABLESPACE %I OWNER TO %I', spec.tablespace, spec.owner)
+  FROM input, pg_catalog.json_to_record(input.data) AS ` + tablespaceRecord + `,
+       pg_catalog.pg_tablespace
+ WHERE NOT spec.remove AND spcname = spec.tablespace
+   AND spec.owner <> pg_catalog.pg_get_userbyid(spcowner)
+   AND spec.owner IN (SELECT rolname FROM pg_catalog.pg_roles)
+ ORDER BY input.id
+\gexec
+`)
+
+	// Reset options that are not specified, then set those that differ.
+	_, _ = sql.WriteString(`
+SELECT pg_catalog.format('ALTER TABLESPACE %I RESET (%s)', spec.tablespace,
+       pg_catalog.string_agg(pg_catalog.quote_ident(pg_catalog.split_part(option, '=', 1)), ', '))
+  FROM input, pg_catalog.json_to_record(input.data) AS ` + tablespaceRecord + `,
+       pg_catalog.pg_tablespace,
+       pg_catalog.unnest(spcoptions) AS option
+ WHERE NOT spec.remove AND spcname = spec.tablespace
+   AND NOT COALESCE(spec.options::jsonb, '{}') ? pg_catalog.split_part(option, '=', 1)
+ GROUP BY spec.tablespace
+\gexec
+
+SELECT pg_catalog.format('ALTER TABLESPACE %I SET (%s)', spec.tablespace,
+       pg_catalog.string_agg(pg_catalog.format('%I = %L', option.key, option.value), ', '))
+  FROM input, pg_catalog.json_to_record(input.data) AS ` + tablespaceRecord + `,
+       pg_catalog.pg_tablespace,
+       pg_catalog.json_each_text(spec.options) AS option
+ WHERE NOT spec.remove AND spcname = spec.tablespace
+   AND NOT pg_catalog.concat(option.key, '=', option.value) = ANY (COALESCE(spcoptions, '{}'))
+ GROUP BY spec.tablespace
+\gexec
+`)
+
+	// Allow roles that exist to create objects in the tablespace.
+	// - https://www.postgresql.org/docs/current/ddl-priv.html
+	_, _ = sql.WriteString(`
+SELECT pg_catalog.format('GRANT CREATE ON TABLESPACE %I TO %I', spec.tablespace, grantee)
+  FROM input, pg_catalog.json_to_record(input.data) AS ` + tablespaceRecord + `,
+       pg_catalog.pg_tablespace,
+       pg_catalog.json_array_elements_text(spec.grants) AS grantee
+ WHERE NOT spec.remove AND spcname = spec.tablespace
+   AND grantee IN (SELECT rolname FROM pg_catalog.pg_roles)
+   AND NOT pg_catalog.has_tablespace_privilege(grantee, spec.tablespace, 'CREATE')
+ ORDER BY input.id
+\gexec
+`)
+
+	// Drop removed tablespaces when no database has objects in them.
+	// - https://www.postgresql.org/docs/current/functions-info.html#FUNCTIONS-INFO-CATALOG
+	_, _ = sql.WriteString(`
+SELECT pg_catalog.format('DROP TABLESPACE %I', spec.tablespace)
+  FROM input, pg_catalog.json_to_record(input.data) AS ` + tablespaceRecord + `,
+       pg_catalog.pg_tablespace AS ts
+ WHERE spec.remove AND ts.spcname = spec.tablespace
+   AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_tablespace_databases(ts.oid))
+ ORDER BY input.id
+\gexec
+`)
+
+	// Report the tablespaces that exist. Store the result in a psql variable
+	// and print only that.
+	// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-META-COMMAND-GSET
+	_, _ = sql.WriteString(`
+SELECT COALESCE(pg_catalog.json_object_agg(ts.spcname, pg_catalog.json_build_object(
+       'size', pg_catalog.pg_tablespace_size(ts.oid),
+       'databases', ARRAY(
+         SELECT datname FROM pg_catalog.pg_database
+          WHERE oid IN (SELECT pg_catalog.pg_tablespace_databases(ts.oid))
+          ORDER BY datname))), '{}') AS tablespaces
+  FROM input, pg_catalog.json_to_record(input.data) AS ` + tablespaceRecord + `,
+       pg_catalog.pg_tablespace AS ts
+ WHERE ts.spcname = spec.tablespace
+\gset
+\echo :tablespaces
+`)
+
+	stdout, stderr, err := exec.Exec(ctx, &sql,
+		map[string]string{
+			"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+			"QUIET":         "on", // Do not print successful statements to stdout.
+		})
+
+	log.V(1).Info("managed PostgreSQL tablespaces", "stdout", stdout, "stderr", stderr)
+
+	var existing map[string]Tablespace
+	if err == nil {
+		if output := strings.TrimSpace(stdout); output != "" {
+			err = json.Unmarshal([]byte(output), &existing)
+		}
+	}
+
+	return existing, err
+}
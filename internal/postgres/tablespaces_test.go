@@ -0,0 +1,91 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestTablespaceLocation(t *testing.T) {
+	assert.Equal(t, TablespaceLocation("trial"), "/tablespaces/trial/data")
+}
+
+func TestManageTablespacesInPostgreSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			return expected
+		}
+
+		_, err := ManageTablespacesInPostgreSQL(ctx, exec, nil, nil)
+		assert.Equal(t, expected, err)
+	})
+
+	t.Run("Full", func(t *testing.T) {
+		calls := 0
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			calls++
+
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(string(b), `
+\copy input (data) from stdin with (format text)
+{"location":"/tablespaces/plain/data","remove":false,"tablespace":"plain"}
+{"grants":["bob","carol"],"location":"/tablespaces/fast/data","options":{"random_page_cost":"1.1","seq_page_cost":"0.5"},"owner":"alice","remove":false,"tablespace":"fast"}
+{"remove":true,"tablespace":"old"}
+\.
+`))
+			assert.Assert(t, cmp.Contains(string(b), `pg_catalog.format('CREATE TABLESPACE %I', spec.tablespace)`))
+			assert.Assert(t, cmp.Contains(string(b), `pg_catalog.format('DROP TABLESPACE %I', spec.tablespace)`))
+			return nil
+		}
+
+		_, err := ManageTablespacesInPostgreSQL(ctx, exec, []v1beta1.TablespaceVolume{
+			{Name: "plain"},
+			{
+				Name:    "fast",
+				Owner:   initialize.Pointer(v1beta1.PostgresIdentifier("alice")),
+				Options: map[string]string{"seq_page_cost": "0.5", "random_page_cost": "1.1"},
+				Grants:  []v1beta1.PostgresIdentifier{"bob", "carol"},
+			},
+		}, []string{"old"})
+		assert.NilError(t, err)
+		assert.Equal(t, calls, 1)
+	})
+
+	t.Run("Existing", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(`{"fast":{"size":8192,"databases":["app"]},"old":{"size":0,"databases":[]}}` + "\n"))
+			return nil
+		}
+
+		existing, err := ManageTablespacesInPostgreSQL(ctx, exec,
+			[]v1beta1.TablespaceVolume{{Name: "fast"}}, []string{"old"})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, existing, map[string]Tablespace{
+			"fast": {Size: 8192, Databases: []string{"app"}},
+			"old":  {Size: 0, Databases: []string{}},
+		})
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// tuningProfile describes how a kind of workload uses memory.
+type tuningProfile struct {
+	// connectionMemory is the memory per connection that decides
+	// max_connections, which is between 20 and maxConnections.
+	connectionMemory, maxConnections int64
+
+	// maintenanceDivisor is the fraction of memory for maintenance_work_mem.
+	maintenanceDivisor int64
+
+	// workMemDivisor is how many work_mem allocations each connection may
+	// use at once.
+	workMemDivisor int64
+}
+
+// tuningProfiles are the profiles of spec.config.tuningProfile. Many short
+// transactions need many connections with little memory each; few large
+// queries need few connections with much memory each.
+var tuningProfiles = map[string]tuningProfile{
+	v1beta1.PostgresTuningOLTP: {
+		connectionMemory: 16 << 20, maxConnections: 500, maintenanceDivisor: 16, workMemDivisor: 3,
+	},
+	v1beta1.PostgresTuningMixed: {
+		connectionMemory: 32 << 20, maxConnections: 200, maintenanceDivisor: 16, workMemDivisor: 2,
+	},
+	v1beta1.PostgresTuningOLAP: {
+		connectionMemory: 64 << 20, maxConnections: 100, maintenanceDivisor: 8, workMemDivisor: 1,
+	},
+}
+
+// instanceMemory returns the memory, in bytes, of instance: its memory limit
+// or, when there is none, its memory request. It returns zero when it has
+// neither.
+func instanceMemory(instance *v1beta1.PostgresInstanceSetSpec) int64 {
+	memory := instance.Resources.Limits.Memory()
+	if memory.IsZero() {
+		memory = instance.Resources.Requests.Memory()
+	}
+	return memory.Value()
+}
+
+// tuningMemory returns the memory, in bytes, of the smallest instance set of
+// cluster. It returns zero when any instance set has no memory.
+func tuningMemory(cluster *v1beta1.PostgresCluster) int64 {
+	var smallest int64
+	for i := range cluster.Spec.InstanceSets {
+		memory := instanceMemory(&cluster.Spec.InstanceSets[i])
+		if memory == 0 {
+			return 0
+		}
+		if smallest == 0 || memory < smallest {
+			smallest = memory
+		}
+	}
+	return smallest
+}
+
+// connections returns max_connections for instances with memory bytes.
+func (tuning tuningProfile) connections(memory int64) int64 {
+	const minConnections = 20
+	return min(max(memory/tuning.connectionMemory, minConnections), tuning.maxConnections)
+}
+
+// parameters returns the memory parameters for an instance with memory bytes
+// that allows connections at once.
+func (tuning tuningProfile) parameters(memory, connections int64) map[string]string {
+	const kB = 1 << 10
+	const maxMaintenance = 2 << 30
+
+	sharedBuffers := memory / 4
+	maintenance := min(memory/tuning.maintenanceDivisor, maxMaintenance)
+
+	// The minimum work_mem is 64kB.
+	work := max((memory-sharedBuffers)/(connections*tuning.workMemDivisor), 64*kB)
+
+	return map[string]string{
+		"effective_cache_size": fmt.Sprintf("%dkB", memory*3/4/kB),
+		"maintenance_work_mem": fmt.Sprintf("%dkB", maintenance/kB),
+		"shared_buffers":       fmt.Sprintf("%dkB", sharedBuffers/kB),
+		"work_mem":             fmt.Sprintf("%dkB", work/kB),
+	}
+}
+
+// minTuningMemory is the least memory, in bytes, that is tuned.
+const minTuningMemory = 256 << 20
+
+// TuningParameters returns the memory parameters of profile for instances
+// with memory bytes. It returns nil when profile is "off" or unknown, or
+// when there is less than 256MiB of memory to tune.
+// - https://www.postgresql.org/docs/current/runtime-config-resource.html
+// - https://www.postgresql.org/docs/current/runtime-config-query.html#GUC-EFFECTIVE-CACHE-SIZE
+func TuningParameters(profile string, memory int64) map[string]string {
+	tuning, ok := tuningProfiles[profile]
+	if !ok || memory < minTuningMemory {
+		return nil
+	}
+
+	connections := tuning.connections(memory)
+	parameters := tuning.parameters(memory, connections)
+	parameters["max_connections"] = fmt.Sprint(connections)
+	return parameters
+}
+
+// SetTuningParameters adds max_connections of cluster.Spec.Config.TuningProfile
+// to the defaults of pgParameters. It must be the same on every instance, so
+// it comes from the smallest instance set. Other settings of this parameter
+// take precedence over the default. See [InstanceTuningParameters] for the
+// parameters that vary by instance set.
+func SetTuningParameters(cluster *v1beta1.PostgresCluster, pgParameters *Parameters) {
+	parameters := TuningParameters(cluster.Spec.Config.TuningProfile, tuningMemory(cluster))
+
+	if value, ok := parameters["max_connections"]; ok {
+		pgParameters.Default.Add("max_connections", value)
+	}
+}
+
+// InstanceTuningParameters returns the memory parameters of
+// cluster.Spec.Config.TuningProfile for the memory of instance. These exclude
+// max_connections which is the same on every instance; work_mem is divided
+// among the connections of the smallest instance set. It returns nil when the
+// profile is "off" or instance has less than 256MiB of memory.
+func InstanceTuningParameters(
+	cluster *v1beta1.PostgresCluster, instance *v1beta1.PostgresInstanceSetSpec,
+) map[string]string {
+	tuning, ok := tuningProfiles[cluster.Spec.Config.TuningProfile]
+	memory := instanceMemory(instance)
+	if !ok || memory < minTuningMemory {
+		return nil
+	}
+
+	// Without a tuned max_connections, PostgreSQL allows 100.
+	// - https://www.postgresql.org/docs/current/runtime-config-connection.html#GUC-MAX-CONNECTIONS
+	var connections int64 = 100
+	if smallest := tuningMemory(cluster); smallest >= minTuningMemory {
+		connections = tuning.connections(smallest)
+	}
+
+	return tuning.parameters(memory, connections)
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestTuningMemory(t *testing.T) {
+	cluster := new(v1beta1.PostgresCluster)
+	assert.Equal(t, tuningMemory(cluster), int64(0))
+
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+		}},
+		{Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+		}},
+	}
+	assert.Equal(t, tuningMemory(cluster), int64(4<<30))
+
+	// Any set without memory prevents tuning.
+	cluster.Spec.InstanceSets = append(cluster.Spec.InstanceSets, v1beta1.PostgresInstanceSetSpec{})
+	assert.Equal(t, tuningMemory(cluster), int64(0))
+}
+
+func TestTuningParameters(t *testing.T) {
+	for _, profile := range []string{"", "off", "unknown"} {
+		assert.Assert(t, TuningParameters(profile, 8<<30) == nil, "profile %q", profile)
+	}
+	assert.Assert(t, TuningParameters("oltp", 128<<20) == nil, "too little memory")
+
+	assert.DeepEqual(t, TuningParameters("oltp", 8<<30), map[string]string{
+		"effective_cache_size": "6291456kB",
+		"maintenance_work_mem": "524288kB",
+		"max_connections":      "500",
+		"shared_buffers":       "2097152kB",
+		"work_mem":             "4194kB",
+	})
+	assert.DeepEqual(t, TuningParameters("mixed", 8<<30), map[string]string{
+		"effective_cache_size": "6291456kB",
+		"maintenance_work_mem": "524288kB",
+		"max_connections":      "200",
+		"shared_buffers":       "2097152kB",
+		"work_mem":             "15728kB",
+	})
+	assert.DeepEqual(t, TuningParameters("olap", 64<<30), map[string]string{
+		"effective_cache_size": "50331648kB",
+		"maintenance_work_mem": "2097152kB",
+		"max_connections":      "100",
+		"shared_buffers":       "16777216kB",
+		"work_mem":             "503316kB",
+	})
+
+	// Small instances have few connections.
+	assert.Equal(t, TuningParameters("olap", 512<<20)["max_connections"], "20")
+}
+
+func TestSetTuningParameters(t *testing.T) {
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}},
+	}
+
+	pgParameters := NewParameters()
+	SetTuningParameters(cluster, &pgParameters)
+	assert.Assert(t, !pgParameters.Default.Has("shared_buffers"))
+
+	cluster.Spec.Config.TuningProfile = "mixed"
+	SetTuningParameters(cluster, &pgParameters)
+	assert.Equal(t, pgParameters.Default.Value("max_connections"), "32")
+	assert.Assert(t, !pgParameters.Mandatory.Has("max_connections"))
+
+	// Memory parameters vary by instance set.
+	assert.Assert(t, !pgParameters.Default.Has("shared_buffers"))
+}
+
+func TestInstanceTuningParameters(t *testing.T) {
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+		{Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}},
+		{Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+		}},
+	}
+
+	assert.Assert(t, InstanceTuningParameters(cluster, &cluster.Spec.InstanceSets[1]) == nil)
+
+	cluster.Spec.Config.TuningProfile = "mixed"
+	assert.DeepEqual(t, InstanceTuningParameters(cluster, &cluster.Spec.InstanceSets[0]), map[string]string{
+		"effective_cache_size": "786432kB",
+		"maintenance_work_mem": "65536kB",
+		"shared_buffers":       "262144kB",
+		"work_mem":             "12288kB",
+	})
+
+	// The larger set gets more memory, but divides work_mem among the
+	// connections of the smaller set.
+	assert.DeepEqual(t, InstanceTuningParameters(cluster, &cluster.Spec.InstanceSets[1]), map[string]string{
+		"effective_cache_size": "6291456kB",
+		"maintenance_work_mem": "524288kB",
+		"shared_buffers":       "2097152kB",
+		"work_mem":             "98304kB",
+	})
+
+	t.Run("Untuned", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.InstanceSets = append(cluster.Spec.InstanceSets, v1beta1.PostgresInstanceSetSpec{})
+
+		// Without memory there is nothing to tune.
+		assert.Assert(t, InstanceTuningParameters(cluster, &cluster.Spec.InstanceSets[2]) == nil)
+
+		// Other sets divide work_mem among the default 100 connections.
+		assert.Equal(t, InstanceTuningParameters(cluster, &cluster.Spec.InstanceSets[0])["work_mem"], "3932kB")
+	})
+}
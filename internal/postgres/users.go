@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"slices"
 	"strings"
 
 	pg_query "github.com/pganalyze/pg_query_go/v5"
@@ -25,6 +26,12 @@ var RESERVED_SCHEMA_NAMES = map[string]bool{
 }
 
 func sanitizeAlterRoleOptions(options string) string {
+	return filterAlterRoleOptions(options, "password")
+}
+
+// filterAlterRoleOptions returns options without those named in exclude. It
+// returns an empty string when options cannot be parsed.
+func filterAlterRoleOptions(options string, exclude ...string) string {
 	const AlterRolePrefix = `ALTER ROLE "any" WITH `
 
 	// Parse the options and discard them completely when incoherent.
@@ -37,7 +44,9 @@ func sanitizeAlterRoleOptions(options string) string {
 	orig := parsed.GetStmts()[0].GetStmt().GetAlterRoleStmt().GetOptions()
 	next := make([]*pg_query.Node, 0, len(orig))
 	for i, option := range orig {
-		if strings.EqualFold(option.GetDefElem().GetDefname(), "password") {
+		if slices.ContainsFunc(exclude, func(name string) bool {
+			return strings.EqualFold(option.GetDefElem().GetDefname(), name)
+		}) {
 			continue
 		}
 		next = append(next, orig[i])
@@ -56,7 +65,7 @@ func sanitizeAlterRoleOptions(options string) string {
 // WriteUsersInPostgreSQL calls exec to create users that do not exist in
 // PostgreSQL. Once they exist, it updates their options and passwords and
 // grants them access to their specified databases. The databases must already
-// exist.
+// exist. When enabled in cluster, it also revokes access to other databases.
 func WriteUsersInPostgreSQL(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, exec Executor,
 	users []v1beta1.PostgresUserSpec, verifiers map[string]string,
@@ -148,6 +157,29 @@ SELECT pg_catalog.format('GRANT ALL PRIVILEGES ON DATABASE %I TO %I',
 \gexec
 `)
 
+	// Revoke access to databases that are not specified. Only privileges
+	// granted directly to each user are revoked.
+	// - https://www.postgresql.org/docs/current/sql-revoke.html
+	// - https://www.postgresql.org/docs/current/functions-info.html#FUNCTIONS-ACLITEM
+	if cluster.Spec.UserPruning != nil && cluster.Spec.UserPruning.Databases {
+		_, _ = sql.WriteString(`
+SELECT pg_catalog.format('REVOKE CONNECT, CREATE ON DATABASE %I FROM %I',
+       db.datname, pg_catalog.json_extract_path_text(input.data, 'username'))
+  FROM input, pg_catalog.pg_database AS db, pg_catalog.pg_roles AS role
+ WHERE role.rolname = pg_catalog.json_extract_path_text(input.data, 'username')
+   AND role.rolname <> 'postgres'
+   AND db.datname NOT IN (
+       SELECT pg_catalog.json_array_elements_text(
+              pg_catalog.json_extract_path(
+              pg_catalog.json_strip_nulls(input.data), 'databases')))
+   AND EXISTS (
+       SELECT 1 FROM pg_catalog.aclexplode(db.datacl) AS acl
+       WHERE acl.grantee = role.oid AND acl.privilege_type IN ('CONNECT', 'CREATE'))
+ ORDER BY input.id, db.datname
+\gexec
+`)
+	}
+
 	// Commit (finish) the transaction.
 	_, _ = sql.WriteString(`COMMIT;`)
 
@@ -173,6 +205,101 @@ SELECT pg_catalog.format('GRANT ALL PRIVILEGES ON DATABASE %I TO %I',
 	return err
 }
 
+// WriteAlternateUsersInPostgreSQL calls exec to make each alternate role a
+// member of its user. Alternate roles assume their user upon login, so the
+// objects they create are owned by that user. Every role must already exist.
+// - https://www.postgresql.org/docs/current/sql-grant.html
+// - https://www.postgresql.org/docs/current/sql-alterrole.html
+func WriteAlternateUsersInPostgreSQL(
+	ctx context.Context, exec Executor, alternates map[string]string,
+) error {
+	log := logging.FromContext(ctx)
+
+	encoded, err := json.Marshal(alternates)
+	if err != nil || len(alternates) == 0 {
+		return err
+	}
+
+	// Quiet the NOTICE about roles that are already members.
+	stdout, stderr, err := exec.Exec(ctx, strings.NewReader(`
+SET client_min_messages = WARNING;
+SELECT pg_catalog.format('GRANT %I TO %I', alternate.value, alternate.key),
+       pg_catalog.format('ALTER ROLE %I SET role TO %L', alternate.key, alternate.value)
+  FROM pg_catalog.json_each_text(:'alternates') AS alternate
+ ORDER BY alternate.key
+\gexec
+`), map[string]string{
+		"alternates": string(encoded),
+
+		"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+		"QUIET":         "on", // Do not print successful statements to stdout.
+	})
+
+	log.V(1).Info("wrote PostgreSQL alternate users", "stdout", stdout, "stderr", stderr)
+
+	return err
+}
+
+// PruneUsersInPostgreSQL calls exec to disable or drop users that have been
+// removed from the cluster spec according to policy. Dropped users give
+// everything they own, in every database, to the "postgres" superuser.
+// - https://www.postgresql.org/docs/current/role-removal.html
+func PruneUsersInPostgreSQL(
+	ctx context.Context, exec Executor, policy string, users []string,
+) error {
+	log := logging.FromContext(ctx)
+
+	encoded, err := json.Marshal(users)
+	if err != nil || len(users) == 0 {
+		return err
+	}
+
+	variables := map[string]string{
+		"users": string(encoded),
+
+		"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+		"QUIET":         "on", // Do not print successful statements to stdout.
+	}
+
+	// Select users that still exist. The "postgres" user is never pruned.
+	const selectUsers = `
+  FROM pg_catalog.pg_roles
+ WHERE rolname IN (SELECT pg_catalog.json_array_elements_text(:'users'))
+   AND rolname <> 'postgres'
+ ORDER BY rolname
+\gexec
+`
+
+	var stdout, stderr string
+	switch policy {
+	case v1beta1.PostgresUserPruningDisable:
+		// - https://www.postgresql.org/docs/current/sql-alterrole.html
+		stdout, stderr, err = exec.Exec(ctx, strings.NewReader(
+			`SELECT pg_catalog.format('ALTER ROLE %I NOLOGIN', rolname)`+selectUsers),
+			variables)
+
+	case v1beta1.PostgresUserPruningDrop:
+		// REASSIGN OWNED and DROP OWNED affect only the current database.
+		// - https://www.postgresql.org/docs/current/sql-reassign-owned.html
+		// - https://www.postgresql.org/docs/current/sql-drop-owned.html
+		stdout, stderr, err = exec.ExecInAllDatabases(ctx,
+			`SELECT pg_catalog.format('REASSIGN OWNED BY %I TO postgres', rolname),
+       pg_catalog.format('DROP OWNED BY %I', rolname)`+selectUsers,
+			variables)
+
+		if err == nil {
+			// - https://www.postgresql.org/docs/current/sql-droprole.html
+			stdout, stderr, err = exec.Exec(ctx, strings.NewReader(
+				`SELECT pg_catalog.format('DROP ROLE %I', rolname)`+selectUsers),
+				variables)
+		}
+	}
+
+	log.V(1).Info("pruned PostgreSQL users", "stdout", stdout, "stderr", stderr)
+
+	return err
+}
+
 // WriteUsersSchemasInPostgreSQL will create a schema for each user in each database that user has access to
 func WriteUsersSchemasInPostgreSQL(ctx context.Context, exec Executor,
 	users []v1beta1.PostgresUserSpec) error {
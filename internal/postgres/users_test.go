@@ -34,6 +34,11 @@ func TestSanitizeAlterRoleOptions(t *testing.T) {
 		assert.Equal(t, sanitizeAlterRoleOptions("login /*"), "")
 		assert.Equal(t, sanitizeAlterRoleOptions("login /* createdb */ createrole"), "LOGIN CREATEROLE")
 	})
+
+	t.Run("RemovesOthers", func(t *testing.T) {
+		assert.Equal(t, filterAlterRoleOptions("login createdb", "password", "canlogin"), "CREATEDB")
+		assert.Equal(t, filterAlterRoleOptions("nologin", "canlogin"), "")
+	})
 }
 
 func TestWriteUsersInPostgreSQL(t *testing.T) {
@@ -185,6 +190,149 @@ COMMIT;`))
 		))
 		assert.Equal(t, calls, 1)
 	})
+
+	t.Run("PruneDatabases", func(t *testing.T) {
+		calls := 0
+		cluster := new(v1beta1.PostgresCluster)
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			calls++
+
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+
+			revoke := strings.Contains(string(b), `REVOKE CONNECT, CREATE ON DATABASE %I FROM %I`)
+			assert.Equal(t, revoke, cluster.Spec.UserPruning != nil)
+			assert.Assert(t, strings.HasSuffix(string(b), "COMMIT;"))
+			return nil
+		}
+
+		assert.NilError(t, WriteUsersInPostgreSQL(ctx, cluster, exec, nil, nil))
+
+		cluster.Spec.UserPruning = &v1beta1.PostgresUserPruningSpec{Databases: true}
+		assert.NilError(t, WriteUsersInPostgreSQL(ctx, cluster, exec, nil, nil))
+		assert.Equal(t, calls, 2)
+	})
+}
+
+func TestPruneUsersInPostgreSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Empty", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, _, _ io.Writer, _ ...string,
+		) error {
+			t.Fatal("expected no calls")
+			return nil
+		}
+
+		assert.NilError(t, PruneUsersInPostgreSQL(ctx, exec, v1beta1.PostgresUserPruningDrop, nil))
+	})
+
+	t.Run("Retain", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, _, _ io.Writer, _ ...string,
+		) error {
+			t.Fatal("expected no calls")
+			return nil
+		}
+
+		assert.NilError(t, PruneUsersInPostgreSQL(ctx, exec,
+			v1beta1.PostgresUserPruningRetain, []string{"alice"}))
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		calls := 0
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			calls++
+
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(string(b), `ALTER ROLE %I NOLOGIN`))
+			assert.Assert(t, cmp.Contains(string(b), `rolname <> 'postgres'`))
+			assert.Assert(t, cmp.Contains(strings.Join(command, " "), `--set=users=["alice","bob"]`))
+			return nil
+		}
+
+		assert.NilError(t, PruneUsersInPostgreSQL(ctx, exec,
+			v1beta1.PostgresUserPruningDisable, []string{"alice", "bob"}))
+		assert.Equal(t, calls, 1)
+	})
+
+	t.Run("Drop", func(t *testing.T) {
+		var scripts []string
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			scripts = append(scripts, string(b))
+			assert.Assert(t, cmp.Contains(strings.Join(command, " "), `--set=users=["alice"]`))
+			return nil
+		}
+
+		assert.NilError(t, PruneUsersInPostgreSQL(ctx, exec,
+			v1beta1.PostgresUserPruningDrop, []string{"alice"}))
+		assert.Equal(t, len(scripts), 2)
+		assert.Assert(t, cmp.Contains(scripts[0], `REASSIGN OWNED BY %I TO postgres`))
+		assert.Assert(t, cmp.Contains(scripts[0], `DROP OWNED BY %I`))
+		assert.Assert(t, cmp.Contains(scripts[1], `DROP ROLE %I`))
+	})
+
+	t.Run("DropError", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		calls := 0
+		exec := func(
+			_ context.Context, _ io.Reader, _, _ io.Writer, _ ...string,
+		) error {
+			calls++
+			return expected
+		}
+
+		assert.Equal(t, expected, PruneUsersInPostgreSQL(ctx, exec,
+			v1beta1.PostgresUserPruningDrop, []string{"alice"}))
+		assert.Equal(t, calls, 1, "expected to stop before DROP ROLE")
+	})
+}
+
+func TestWriteAlternateUsersInPostgreSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Empty", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, _, _ io.Writer, _ ...string,
+		) error {
+			t.Fatal("expected no calls")
+			return nil
+		}
+
+		assert.NilError(t, WriteAlternateUsersInPostgreSQL(ctx, exec, nil))
+	})
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		calls := 0
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			calls++
+
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(string(b), `GRANT %I TO %I`))
+			assert.Assert(t, cmp.Contains(string(b), `ALTER ROLE %I SET role TO %L`))
+			assert.Assert(t, cmp.Contains(strings.Join(command, " "),
+				`--set=alternates={"alice-alt":"alice"}`))
+			return expected
+		}
+
+		assert.Equal(t, expected, WriteAlternateUsersInPostgreSQL(ctx, exec,
+			map[string]string{"alice-alt": "alice"}))
+		assert.Equal(t, calls, 1)
+	})
 }
 
 func TestWriteUsersSchemasInPostgreSQL(t *testing.T) {
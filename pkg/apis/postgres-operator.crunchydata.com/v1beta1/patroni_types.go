@@ -4,6 +4,10 @@
 
 package v1beta1
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 type PatroniSpec struct {
 	// Patroni dynamic configuration settings. Changes to this value will be
 	// automatically reloaded without validation. Changes to certain PostgreSQL
@@ -42,6 +46,13 @@ type PatroniSpec struct {
 	// +optional
 	Switchover *PatroniSwitchover `json:"switchover,omitempty"`
 
+	// Synchronous replication settings. When specified, these take precedence
+	// over "synchronous_mode", "synchronous_mode_strict", and
+	// "synchronous_node_count" in dynamicConfiguration.
+	// More info: https://patroni.readthedocs.io/en/latest/replication_modes.html
+	// +optional
+	Synchronous *PatroniSynchronous `json:"synchronous,omitempty"`
+
 	// TODO(cbandy): Add UseConfigMaps bool, default false.
 	// TODO(cbandy): Allow other DCS: etcd, raft, etc?
 	// N.B. changing this will cause downtime.
@@ -71,6 +82,25 @@ type PatroniSwitchover struct {
 	Type string `json:"type,omitempty"`
 }
 
+type PatroniSynchronous struct {
+	// Whether or not Patroni should replicate synchronously to some replicas.
+	// +required
+	Enabled bool `json:"enabled"`
+
+	// The number of replicas that must confirm each transaction. Must be less
+	// than the number of instances that are not delayed.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	NodeCount *int32 `json:"nodeCount,omitempty"`
+
+	// Whether or not to stop accepting writes when there are not enough
+	// synchronous replicas. When false, Patroni falls back to asynchronous
+	// replication until replicas are available again.
+	// +optional
+	Strict bool `json:"strict,omitempty"`
+}
+
 // PatroniSwitchover types.
 const (
 	PatroniSwitchoverTypeFailover   = "Failover"
@@ -82,6 +112,7 @@ const (
 // - Lock Lease Duration
 // - Patroni's API port
 // - Frequency of syncing with Kube API
+// - Number of synchronous replicas
 func (s *PatroniSpec) Default() {
 	if s.LeaderLeaseDurationSeconds == nil {
 		s.LeaderLeaseDurationSeconds = new(int32)
@@ -95,6 +126,10 @@ func (s *PatroniSpec) Default() {
 		s.SyncPeriodSeconds = new(int32)
 		*s.SyncPeriodSeconds = 10
 	}
+	if s.Synchronous != nil && s.Synchronous.NodeCount == nil {
+		s.Synchronous.NodeCount = new(int32)
+		*s.Synchronous.NodeCount = 1
+	}
 }
 
 type PatroniStatus struct {
@@ -114,4 +149,20 @@ type PatroniStatus struct {
 	// Tracks the current timeline during switchovers
 	// +optional
 	SwitchoverTimeline *int64 `json:"switchoverTimeline,omitempty"`
+
+	// PostgreSQL parameters that have changed but take effect only after
+	// PostgreSQL restarts.
+	// +listType=set
+	// +optional
+	PendingRestart []string `json:"pendingRestart,omitempty"`
+
+	// When parameters were last added to pendingRestart. They are forgotten
+	// once Patroni has had time to notice them and no instance needs to restart.
+	// +optional
+	PendingRestartSince *metav1.Time `json:"pendingRestartSince,omitempty"`
+
+	// The Patroni members that are currently synchronous standbys.
+	// +listType=set
+	// +optional
+	SynchronousStandbys []string `json:"synchronousStandbys,omitempty"`
 }
@@ -407,6 +407,10 @@ type RepoStatus struct {
 	// +optional
 	VolumeName string `json:"volume,omitempty"`
 
+	// Desired Size of the pgBackRest repository volume
+	// +optional
+	DesiredRepoVolume string `json:"desiredRepoVolume,omitempty"`
+
 	// Specifies whether or not a stanza has been successfully created for the repository
 	// +optional
 	StanzaCreated bool `json:"stanzaCreated"`
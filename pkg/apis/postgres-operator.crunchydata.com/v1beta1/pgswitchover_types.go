@@ -0,0 +1,80 @@
+// Copyright 2021 - 2024 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PGSwitchoverSpec defines the desired state of PGSwitchover
+// +kubebuilder:validation:XValidation:rule=`self.sourceClusterName != self.targetClusterName`,message="source and target must be different clusters"
+// +kubebuilder:validation:XValidation:rule=`self == oldSelf`,message="a switchover cannot be changed; delete it and create another"
+type PGSwitchoverSpec struct {
+	// The name of the PostgresCluster that is currently primary. It stops
+	// accepting writes and becomes a standby of the target cluster.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	SourceClusterName string `json:"sourceClusterName"`
+
+	// The name of the standby PostgresCluster to promote. It must follow the
+	// source cluster by streaming replication, and the source cluster streams
+	// from its primary Service afterward. Both clusters must be annotated with
+	// "postgres-operator.crunchydata.com/allow-switchover" set to the name of
+	// this switchover.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	TargetClusterName string `json:"targetClusterName"`
+}
+
+// PGSwitchoverStatus defines the observed state of PGSwitchover
+type PGSwitchoverStatus struct {
+	// conditions represent the observations of PGSwitchover's current state.
+	// Each step of the switchover is reported in a condition of its own.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The last location in the WAL of the source cluster before it stopped
+	// accepting writes.
+	// +optional
+	FinalLSN string `json:"finalLSN,omitempty"`
+
+	// observedGeneration represents the .metadata.generation on which the status was based.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// PGSwitchover is the Schema for the pgswitchovers API. It moves the primary
+// role from one PostgresCluster to a standby PostgresCluster that follows it.
+//
+// A switchover does not give up after the source cluster stops accepting
+// writes. Until the "TargetPromoted" condition is true, it can be rolled back
+// by deleting the switchover and then disabling spec.standby of the source
+// cluster; the source is promoted again with all of its WAL.
+type PGSwitchover struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PGSwitchoverSpec   `json:"spec,omitempty"`
+	Status PGSwitchoverStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PGSwitchoverList contains a list of PGSwitchover
+type PGSwitchoverList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PGSwitchover `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PGSwitchover{}, &PGSwitchoverList{})
+}
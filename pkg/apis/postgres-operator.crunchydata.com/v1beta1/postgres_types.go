@@ -4,6 +4,12 @@
 
 package v1beta1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // PostgreSQL identifiers are limited in length but may contain any character.
 // More info: https://www.postgresql.org/docs/current/sql-syntax-lexical.html#SQL-SYNTAX-IDENTIFIERS
 //
@@ -11,6 +17,162 @@ package v1beta1
 // +kubebuilder:validation:MaxLength=63
 type PostgresIdentifier string
 
+type PostgresDatabaseSpec struct {
+
+	// The name of this PostgreSQL database.
+	// +kubebuilder:validation:Type=string
+	// +required
+	Name PostgresIdentifier `json:"name"`
+
+	// The role that owns this database. When this role does not exist yet, the
+	// database is created and then given to the role after it exists.
+	// More info: https://www.postgresql.org/docs/current/sql-createdatabase.html
+	// +kubebuilder:validation:Type=string
+	// +optional
+	Owner *PostgresIdentifier `json:"owner,omitempty"`
+
+	// The character set encoding of this database, e.g. "UTF8". This cannot
+	// change after the database is created.
+	// More info: https://www.postgresql.org/docs/current/multibyte.html
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_-]+$`
+	// +optional
+	Encoding *string `json:"encoding,omitempty"`
+
+	// The collation order (LC_COLLATE) of this database. This cannot change
+	// after the database is created.
+	// More info: https://www.postgresql.org/docs/current/locale.html
+	// +kubebuilder:validation:MaxLength=100
+	// +optional
+	LCCollate *string `json:"lcCollate,omitempty"`
+
+	// The character classification (LC_CTYPE) of this database. This cannot
+	// change after the database is created.
+	// More info: https://www.postgresql.org/docs/current/locale.html
+	// +kubebuilder:validation:MaxLength=100
+	// +optional
+	LCCType *string `json:"lcCType,omitempty"`
+
+	// The database from which to copy this database. Defaults to "template1"
+	// in PostgreSQL. Use "template0" to choose an encoding or locale that
+	// differs from "template1".
+	// More info: https://www.postgresql.org/docs/current/manage-ag-templatedbs.html
+	// +kubebuilder:validation:Type=string
+	// +optional
+	Template *PostgresIdentifier `json:"template,omitempty"`
+
+	// How many concurrent connections can be made to this database. The
+	// value -1 means no limit.
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	ConnectionLimit *int32 `json:"connectionLimit,omitempty"`
+
+	// The tablespace in which to store this database. The tablespace must
+	// already exist.
+	// More info: https://www.postgresql.org/docs/current/manage-ag-tablespaces.html
+	// +kubebuilder:validation:Type=string
+	// +optional
+	Tablespace *PostgresIdentifier `json:"tablespace,omitempty"`
+
+	// Extensions to create in this database. Removing an extension from this
+	// list does NOT drop the extension. Their shared libraries are loaded when
+	// PostgreSQL starts; changing those causes PostgreSQL to restart. Failures
+	// are reported in status, do not prevent other extensions, and are retried.
+	// More info: https://www.postgresql.org/docs/current/sql-createextension.html
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Extensions []PostgresExtensionSpec `json:"extensions,omitempty"`
+}
+
+type PostgresExtensionSpec struct {
+
+	// The name of the extension.
+	// +kubebuilder:validation:Type=string
+	// +required
+	Name PostgresIdentifier `json:"name"`
+
+	// The version of the extension. When specified, the extension is updated
+	// to this version. Defaults to the version in the extension's control file.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=64
+	// +optional
+	Version *string `json:"version,omitempty"`
+
+	// The schema in which to create the objects of the extension. This has
+	// no effect after the extension is created.
+	// +kubebuilder:validation:Type=string
+	// +optional
+	Schema *PostgresIdentifier `json:"schema,omitempty"`
+
+	// Shared libraries that PostgreSQL must load when it starts for this
+	// extension to work. Defaults to the libraries of well-known extensions,
+	// such as pg_cron and timescaledb, when omitted.
+	// More info: https://www.postgresql.org/docs/current/runtime-config-client.html#GUC-SHARED-PRELOAD-LIBRARIES
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=8
+	// +kubebuilder:validation:items:MinLength=1
+	// +optional
+	Libraries []string `json:"libraries,omitempty"`
+}
+
+type PostgresDatabaseStatus struct {
+
+	// The name of the PostgreSQL database.
+	// +required
+	Name string `json:"name"`
+
+	// How the database in PostgreSQL differs from its specification, e.g.
+	// "owner is alice, not bob".
+	// +listType=atomic
+	// +optional
+	Drift []string `json:"drift,omitempty"`
+
+	// The extensions of the specification that are installed in the database
+	// or that could not be created or updated.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Extensions []PostgresExtensionStatus `json:"extensions,omitempty"`
+}
+
+type PostgresExtensionStatus struct {
+
+	// The name of the extension.
+	// +required
+	Name string `json:"name"`
+
+	// The version of the extension that is installed.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Why the extension could not be created or updated, if it could not.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+type PostgresTablespaceStatus struct {
+
+	// The name of the PostgreSQL tablespace.
+	// +required
+	Name string `json:"name"`
+
+	// Disk space used by the tablespace on the primary.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+
+	// Databases that have objects in the tablespace.
+	// +listType=atomic
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+
+	// Whether or not the tablespace was removed from the spec but still has
+	// objects in it. Its volumes remain until it is empty.
+	// +optional
+	Retained bool `json:"retained,omitempty"`
+}
+
 type PostgresPasswordSpec struct {
 	// Type of password to generate. Defaults to ASCII. Valid options are ASCII
 	// and AlphaNumeric.
@@ -40,8 +202,8 @@ type PostgresUserSpec struct {
 	Name PostgresIdentifier `json:"name"`
 
 	// Databases to which this user can connect and create objects. Removing a
-	// database from this list does NOT revoke access. This field is ignored for
-	// the "postgres" user.
+	// database from this list does NOT revoke access unless that is enabled in
+	// spec.userPruning. This field is ignored for the "postgres" user.
 	// +listType=set
 	// +optional
 	Databases []PostgresIdentifier `json:"databases,omitempty"`
@@ -56,7 +218,302 @@ type PostgresUserSpec struct {
 	// +optional
 	Options string `json:"options,omitempty"`
 
+	// Roles of which this user is a member. The user inherits their
+	// privileges. Roles that do not exist are ignored.
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	MemberOf []PostgresIdentifier `json:"memberOf,omitempty"`
+
+	// Privileges to grant this user on schemas and the objects in them.
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Grants []PostgresGrantSpec `json:"grants,omitempty"`
+
 	// Properties of the password generated for this user.
 	// +optional
 	Password *PostgresPasswordSpec `json:"password,omitempty"`
+
+	// Generate a new password for this user periodically. This field is
+	// ignored for the "postgres" user and when passwordSecretRef is set.
+	// +optional
+	PasswordRotation *PostgresPasswordRotationSpec `json:"passwordRotation,omitempty"`
+
+	// A key in a Secret in the same namespace that contains the password of
+	// this user. The Secret is read but never changed, and changes to it are
+	// applied to PostgreSQL. When set, no password is generated, and the user
+	// is not created until the Secret and key exist.
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// Whether or not to issue a client certificate for this user. The
+	// certificate has the name of this user as its common name and is stored
+	// in the user Secret as "tls.crt" and "tls.key" with the authority in
+	// "ca.crt". This user must then authenticate with that certificate when
+	// connecting over TLS. PgBouncer cannot present this certificate, so this
+	// user cannot connect through spec.proxy.pgBouncer. This is ignored when
+	// customTLSSecret is set.
+	// More info: https://www.postgresql.org/docs/current/auth-cert.html
+	// +optional
+	ClientCertificate bool `json:"clientCertificate,omitempty"`
+}
+
+// PostgresPasswordRotationSpec defines how often the password of a user
+// changes. PostgreSQL roles have only one password. To keep the previous
+// password valid while applications reload their Secret, the user takes turns
+// logging in with its own role and an alternate role named "<name>-alt". The
+// alternate role is a member of the user and assumes it upon login, so objects
+// are always owned by the user.
+type PostgresPasswordRotationSpec struct {
+
+	// How often to generate a new password, e.g. "2160h" for 90 days.
+	// +required
+	Period metav1.Duration `json:"period"`
+
+	// How long the previous password remains valid after a new one is
+	// generated. After this, the previous password expires using VALID UNTIL.
+	// +kubebuilder:default="24h"
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+type PostgresRoleSpec struct {
+
+	// The name of this PostgreSQL role. It cannot be "postgres" nor the name
+	// of a user in spec.users.
+	// +kubebuilder:validation:XValidation:rule=`self != 'postgres' && !self.startsWith('pg_')`,message="cannot be a reserved role"
+	// +required
+	Name PostgresIdentifier `json:"name"`
+
+	// ALTER ROLE options except for PASSWORD and LOGIN. The role is always NOLOGIN.
+	// More info: https://www.postgresql.org/docs/current/role-attributes.html
+	// +kubebuilder:validation:MaxLength=200
+	// +kubebuilder:validation:Pattern=`^[^;]*$`
+	// +kubebuilder:validation:XValidation:rule=`!self.matches("(?i:PASSWORD)")`,message="cannot assign password"
+	// +kubebuilder:validation:XValidation:rule=`!self.matches("(?:--|/[*]|[*]/)")`,message="cannot contain comments"
+	// +optional
+	Options string `json:"options,omitempty"`
+
+	// Roles of which this role is a member. Roles that do not exist are ignored.
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	MemberOf []PostgresIdentifier `json:"memberOf,omitempty"`
+
+	// Privileges to grant this role on schemas and the objects in them.
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Grants []PostgresGrantSpec `json:"grants,omitempty"`
+}
+
+// PostgreSQL privileges that can be granted on schemas and the objects in them.
+// More info: https://www.postgresql.org/docs/current/ddl-priv.html
+//
+// +kubebuilder:validation:Enum={ALL,CREATE,DELETE,EXECUTE,INSERT,REFERENCES,SELECT,TRIGGER,TRUNCATE,UPDATE,USAGE}
+type PostgresPrivilege string
+
+type PostgresGrantSpec struct {
+
+	// The database that contains the schema.
+	// +required
+	Database PostgresIdentifier `json:"database"`
+
+	// The schema in which to grant privileges. Schemas that do not exist are
+	// reported in the "PrivilegesPending" condition and checked every minute
+	// until they do.
+	// +required
+	Schema PostgresIdentifier `json:"schema"`
+
+	// The objects on which to grant privileges: the "Schema" itself or all
+	// "Tables", "Sequences", or "Functions" in the schema.
+	// More info: https://www.postgresql.org/docs/current/sql-grant.html
+	// +kubebuilder:default=Schema
+	// +kubebuilder:validation:Enum={Schema,Tables,Sequences,Functions}
+	// +optional
+	On string `json:"on,omitempty"`
+
+	// The privileges to grant. Only those that apply to the objects are granted.
+	// More info: https://www.postgresql.org/docs/current/ddl-priv.html
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	// +required
+	Privileges []PostgresPrivilege `json:"privileges"`
+
+	// Whether or not to also grant these privileges on objects that the schema
+	// owner creates in the future. This does not apply to the "Schema" itself.
+	// More info: https://www.postgresql.org/docs/current/sql-alterdefaultprivileges.html
+	// +optional
+	Default bool `json:"default,omitempty"`
+}
+
+// PostgresGrantSpec objects.
+const (
+	PostgresGrantOnFunctions = "Functions"
+	PostgresGrantOnSchema    = "Schema"
+	PostgresGrantOnSequences = "Sequences"
+	PostgresGrantOnTables    = "Tables"
+)
+
+type PostgresRoleGrantsStatus struct {
+
+	// The name of the PostgreSQL role.
+	// +required
+	Role string `json:"role"`
+
+	// Roles of which this role was made a member.
+	// +optional
+	MemberOf []string `json:"memberOf,omitempty"`
+
+	// Privileges that were granted to this role.
+	// +optional
+	Grants []PostgresGrantSpec `json:"grants,omitempty"`
+}
+
+type PostgresUserPruningSpec struct {
+
+	// Whether or not to revoke CONNECT and CREATE on databases that are not in
+	// the databases list of a user.
+	// +optional
+	Databases bool `json:"databases,omitempty"`
+
+	// What to do with a user that is removed from spec.users. "Retain" leaves
+	// the user unchanged. "Disable" prevents the user from logging in. "Drop"
+	// gives everything the user owns to the "postgres" superuser and drops the
+	// user.
+	// +kubebuilder:default=Retain
+	// +kubebuilder:validation:Enum={Retain,Disable,Drop}
+	// +optional
+	Users string `json:"users,omitempty"`
+}
+
+// PostgresUserPruningSpec user policies.
+const (
+	PostgresUserPruningDisable = "Disable"
+	PostgresUserPruningDrop    = "Drop"
+	PostgresUserPruningRetain  = "Retain"
+)
+
+type PostgresUserStatus struct {
+
+	// The name of the PostgreSQL user.
+	// +required
+	Name string `json:"name"`
+
+	// What happened to this user after it was removed from spec.users:
+	// "Retained", "Disabled", or "Dropped". Retained and disabled users are
+	// pruned again when spec.userPruning.users changes. Empty while the user
+	// is in spec.users.
+	// +optional
+	Removed string `json:"removed,omitempty"`
+
+	// The PostgreSQL role that logs in with the current password of this user
+	// when its password rotates.
+	// +optional
+	LoginRole string `json:"loginRole,omitempty"`
+
+	// When the password of this user was last rotated.
+	// +optional
+	PasswordRotated *metav1.Time `json:"passwordRotated,omitempty"`
+
+	// When the previous password of this user expires.
+	// +optional
+	PreviousPasswordExpires *metav1.Time `json:"previousPasswordExpires,omitempty"`
+}
+
+// PostgresUserStatus removal outcomes.
+const (
+	PostgresUserDisabled = "Disabled"
+	PostgresUserDropped  = "Dropped"
+	PostgresUserRetained = "Retained"
+)
+
+type PostgresAuthenticationSpec struct {
+
+	// Host-based authentication rules for PostgreSQL. These come after rules
+	// that the operator requires and before those in the "pg_hba" section of
+	// Patroni dynamic configuration. When this list is not empty, the default
+	// rule that allows password authentication over TLS is not added.
+	// More info: https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Rules []PostgresHBARuleSpec `json:"rules,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule=`self.method != 'cert' || !has(self.connection) || self.connection == 'hostssl'`,message="cert requires hostssl connections"
+// +kubebuilder:validation:XValidation:rule=`self.method != 'peer' || (has(self.connection) && self.connection == 'local')`,message="peer requires local connections"
+// +kubebuilder:validation:XValidation:rule=`!has(self.address) || !has(self.connection) || self.connection != 'local'`,message="address does not apply to local connections"
+type PostgresHBARuleSpec struct {
+
+	// The kind of connection to match: "local" for Unix-domain sockets, "host"
+	// for any TCP/IP connection, "hostssl" for TCP/IP with TLS, or "hostnossl"
+	// for TCP/IP without TLS.
+	// +kubebuilder:default=hostssl
+	// +kubebuilder:validation:Enum={local,host,hostssl,hostnossl}
+	// +optional
+	Connection string `json:"connection,omitempty"`
+
+	// The databases to match. When empty, every database matches.
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	Databases []PostgresIdentifier `json:"databases,omitempty"`
+
+	// The users to match. When empty and there are no roles, every user matches.
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	Users []PostgresIdentifier `json:"users,omitempty"`
+
+	// Match users that are members of these roles.
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	Roles []PostgresIdentifier `json:"roles,omitempty"`
+
+	// The client addresses to match, in CIDR notation, or one of "all",
+	// "samehost", or "samenet". When omitted, every address matches. This does
+	// not apply to local connections.
+	// +kubebuilder:validation:MaxLength=50
+	// +kubebuilder:validation:Pattern=`^[0-9A-Fa-f.:/]+$|^(all|samehost|samenet)$`
+	// +optional
+	Address string `json:"address,omitempty"`
+
+	// The authentication method to use when a connection matches this rule.
+	// More info: https://www.postgresql.org/docs/current/auth-methods.html
+	// +kubebuilder:validation:Enum={reject,scram-sha-256,md5,password,gss,sspi,ident,peer,pam,ldap,radius,cert}
+	// +required
+	Method string `json:"method"`
+
+	// Options for the authentication method, such as "ldapserver" or
+	// "clientcert". Values are quoted and stored in plaintext in the
+	// configuration of Patroni. Use secretOptions for secrets.
+	// More info: https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
+	// +kubebuilder:validation:MaxProperties=20
+	// +kubebuilder:validation:XValidation:rule=`self.all(k, k.matches('^[a-z_]+$'))`,message="option names are lowercase letters"
+	// +mapType=granular
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+
+	// Options for the authentication method that come from keys of Secrets in
+	// the namespace of the cluster, such as "ldapbindpasswd" or "radiussecrets".
+	// These take precedence over options of the same name. A rule with these
+	// is written to a file in instance Pods and included in pg_hba.conf, so
+	// their values are not stored in the configuration of Patroni. This
+	// requires PostgreSQL 16 or later.
+	// +kubebuilder:validation:MaxProperties=10
+	// +kubebuilder:validation:XValidation:rule=`self.all(k, k.matches('^[a-z_]+$'))`,message="option names are lowercase letters"
+	// +mapType=granular
+	// +optional
+	SecretOptions map[string]corev1.SecretKeySelector `json:"secretOptions,omitempty"`
 }
+
+// PostgresHBARuleSpec connections.
+const (
+	PostgresHBAHost      = "host"
+	PostgresHBAHostNoSSL = "hostnossl"
+	PostgresHBAHostSSL   = "hostssl"
+	PostgresHBALocal     = "local"
+)
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -46,11 +47,43 @@ type PostgresClusterSpec struct {
 	// +optional
 	CustomReplicationClientTLSSecret *corev1.SecretProjection `json:"customReplicationTLSSecret,omitempty"`
 
+	// Where the certificate authority that signs the certificates of this
+	// cluster comes from. By default, the operator generates a self-signed
+	// root certificate authority that is shared by clusters in the namespace.
+	// Certificates in customTLSSecret and customReplicationTLSSecret are
+	// used as they are.
+	// +optional
+	CertificateIssuer *CertificateIssuerSpec `json:"certificateIssuer,omitempty"`
+
+	// How long before a certificate used by this cluster expires to report
+	// it with a condition and warning events. Certificates in customTLSSecret
+	// and customReplicationTLSSecret are not renewed by the operator.
+	// Defaults to 30 days.
+	// +optional
+	CertificateExpiryWarning *metav1.Duration `json:"certificateExpiryWarning,omitempty"`
+
+	// The keys and lifetimes of certificates that the operator issues for
+	// this cluster. Fields that are not set use the operator defaults.
+	// Certificates are reissued when these change.
+	// +optional
+	CertificatePolicy *CertificatePolicySpec `json:"certificatePolicy,omitempty"`
+
 	// DatabaseInitSQL defines a ConfigMap containing custom SQL that will
 	// be run after the cluster is initialized. This ConfigMap must be in the same
 	// namespace as the cluster.
 	// +optional
 	DatabaseInitSQL *DatabaseInitSQL `json:"databaseInitSQL,omitempty"`
+	// Databases to create inside PostgreSQL. These are created in addition to
+	// the databases of spec.users, and their settings take precedence. Existing
+	// databases are not changed; any differences are reported in status and
+	// the "DatabaseDrift" condition. Databases are compared again every minute
+	// while they differ and every hour otherwise.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Databases []PostgresDatabaseSpec `json:"databases,omitempty"`
+
 	// Whether or not the PostgreSQL cluster should use the defined default
 	// scheduling constraints. If the field is unset or false, the default
 	// scheduling constraints will be used in addition to any custom constraints
@@ -89,6 +122,15 @@ type PostgresClusterSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=2
 	InstanceSets []PostgresInstanceSetSpec `json:"instances"`
 
+	// Times when the operator may perform disruptive work, such as replacing
+	// Pods, restarting PostgreSQL, or starting a PGUpgrade. When empty, that
+	// work happens as soon as it is needed. Instances that are unavailable are
+	// always replaced.
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
 	// Whether or not the PostgreSQL cluster is being deployed to an OpenShift
 	// environment. If the field is unset, the operator will automatically
 	// detect the environment.
@@ -171,16 +213,122 @@ type PostgresClusterSpec struct {
 	// Users to create inside PostgreSQL and the databases they should access.
 	// The default creates one user that can access one database matching the
 	// PostgresCluster name. An empty list creates no users. Removing a user
-	// from this list does NOT drop the user nor revoke their access.
+	// from this list does NOT drop the user nor revoke their access unless
+	// that is enabled in spec.userPruning.
 	// +listType=map
 	// +listMapKey=name
 	// +kubebuilder:validation:MaxItems=64
 	// +optional
 	Users []PostgresUserSpec `json:"users,omitempty"`
 
+	// Whether or not to revoke access and remove users that are no longer
+	// in spec.users. By default, nothing is revoked or removed. Only users
+	// reported in status.users are considered; users removed from spec.users
+	// before status.users existed are not pruned and must be removed by hand.
+	// +optional
+	UserPruning *PostgresUserPruningSpec `json:"userPruning,omitempty"`
+
+	// Group roles to create inside PostgreSQL. These roles cannot log in;
+	// users and other roles gain their privileges through memberOf. Removing
+	// a role from this list does NOT drop the role nor revoke its privileges
+	// and memberships.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Roles []PostgresRoleSpec `json:"roles,omitempty"`
+
+	// Authentication settings for the PostgreSQL server.
+	// +optional
+	Authentication *PostgresAuthenticationSpec `json:"authentication,omitempty"`
+
 	Config PostgresAdditionalConfig `json:"config,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule=`has(self.caSecret) != has(self.certManager)`,message="exactly one of caSecret or certManager is required"
+type CertificateIssuerSpec struct {
+
+	// A Secret in the namespace of the cluster that contains an intermediate
+	// certificate authority: its certificate in "tls.crt", its ECDSA private
+	// key in "tls.key", and the authorities that issued it in "ca.crt".
+	// Certificates are reissued when this Secret changes. The cluster waits
+	// with a "CertificateIssuerReady" condition until this Secret exists.
+	// +optional
+	CASecret *corev1.LocalObjectReference `json:"caSecret,omitempty"`
+
+	// A cert-manager issuer of the certificates of PostgreSQL, replication,
+	// pgBackRest, and PgBouncer. Each is requested as a cert-manager Certificate
+	// that cert-manager renews. The issuer must put its authority in "ca.crt"
+	// of the Secrets it writes, as the CA and Vault issuers do.
+	// More info: https://cert-manager.io/docs/usage/certificate/
+	// +optional
+	CertManager *CertManagerIssuerSpec `json:"certManager,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule=`!has(self.leafLifetime) || !has(self.renewBefore) || duration(self.renewBefore) < duration(self.leafLifetime)`,message="renewBefore must be less than leafLifetime"
+type CertificatePolicySpec struct {
+
+	// The algorithm of private keys. Ed25519 requires OpenSSL 1.1.1 or later
+	// in every client that verifies these certificates.
+	// +kubebuilder:validation:Enum={ECDSA-P256,ECDSA-P384,RSA-2048,RSA-4096,Ed25519}
+	// +optional
+	KeyAlgorithm string `json:"keyAlgorithm,omitempty"`
+
+	// How long certificates are valid. Shortening this reissues certificates
+	// that are valid for longer.
+	// +optional
+	LeafLifetime *metav1.Duration `json:"leafLifetime,omitempty"`
+
+	// How long before they expire to renew certificates. Defaults to one-third
+	// of their lifetime.
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+type CertManagerIssuerSpec struct {
+
+	// The name of the cert-manager issuer.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// The kind of the cert-manager issuer.
+	// +kubebuilder:default=Issuer
+	// +kubebuilder:validation:Enum={Issuer,ClusterIssuer}
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// The API group of the cert-manager issuer.
+	// +kubebuilder:default=cert-manager.io
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// How long certificates are valid. cert-manager renews them before then.
+	// Defaults to certificatePolicy.leafLifetime, then to the cert-manager
+	// default of 90 days.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of time when disruptive work is allowed.
+type MaintenanceWindow struct {
+	// When the window opens, in the five field format of cron: minute, hour,
+	// day of month, month, and day of week.
+	// More info: https://pubs.opengroup.org/onlinepubs/9699919799/utilities/crontab.html
+	// +kubebuilder:validation:MinLength=9
+	// +required
+	Schedule string `json:"schedule"`
+
+	// How long the window stays open, e.g. "2h" or "30m".
+	// +required
+	Duration metav1.Duration `json:"duration"`
+
+	// The time zone of schedule, e.g. "America/New_York". Defaults to UTC.
+	// More info: https://www.iana.org/time-zones
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+}
+
 // DataSource defines data sources for a new PostgresCluster.
 type DataSource struct {
 	// Defines a pgBackRest cloud-based data source that can be used to pre-populate the
@@ -335,6 +483,19 @@ type PostgresClusterStatus struct {
 	// Identifies the databases that have been installed into PostgreSQL.
 	DatabaseRevision string `json:"databaseRevision,omitempty"`
 
+	// Databases of spec.databases that differ from their specification or
+	// that have extensions.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Databases []PostgresDatabaseStatus `json:"databases,omitempty"`
+
+	// Tablespaces of the instance sets that exist in PostgreSQL.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Tablespaces []PostgresTablespaceStatus `json:"tablespaces,omitempty"`
+
 	// Current state of PostgreSQL instances.
 	// +listType=map
 	// +listMapKey=name
@@ -379,6 +540,32 @@ type PostgresClusterStatus struct {
 	// Identifies the users that have been installed into PostgreSQL.
 	UsersRevision string `json:"usersRevision,omitempty"`
 
+	// The users that have been installed into PostgreSQL, including those
+	// that were removed from spec.users and pruned.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Users []PostgresUserStatus `json:"users,omitempty"`
+
+	// The role memberships and privileges that have been granted in PostgreSQL.
+	// Those that are removed from spec.roles and spec.users are revoked.
+	// +listType=map
+	// +listMapKey=role
+	// +optional
+	Grants []PostgresRoleGrantsStatus `json:"grants,omitempty"`
+
+	// The progress of the most recent rotation of the root certificate
+	// authority that this cluster uses.
+	// +optional
+	RootCertificateRotation *RootCertificateRotationStatus `json:"rootCertificateRotation,omitempty"`
+
+	// The certificates used by this cluster and when they expire.
+	// +listType=map
+	// +listMapKey=secret
+	// +listMapKey=key
+	// +optional
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+
 	// Current state of PostgreSQL cluster monitoring tool configuration
 	// +optional
 	Monitoring MonitoringStatus `json:"monitoring,omitempty"`
@@ -393,8 +580,10 @@ type PostgresClusterStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
 	// conditions represent the observations of postgrescluster's current state.
-	// Known .status.conditions.type are: "PersistentVolumeResizing",
-	// "Progressing", "ProxyAvailable"
+	// Known .status.conditions.type are: "CertificateExpiring",
+	// "CertificateIssuerReady", "DatabaseDrift", "MaintenancePending",
+	// "PendingRestart", "PersistentVolumeResizing", "PrivilegesPending",
+	// "Progressing", "ProxyAvailable", "SynchronousReplication"
 	// +optional
 	// +listType=map
 	// +listMapKey=type
@@ -404,10 +593,84 @@ type PostgresClusterStatus struct {
 
 // PostgresClusterStatus condition types.
 const (
+	CertificateExpiring        = "CertificateExpiring"
+	CertificateIssuerReady     = "CertificateIssuerReady"
+	DatabaseDrift              = "DatabaseDrift"
+	MaintenancePending         = "MaintenancePending"
+	PendingRestart             = "PendingRestart"
 	PersistentVolumeResizing   = "PersistentVolumeResizing"
+	PrivilegesPending          = "PrivilegesPending"
 	PostgresClusterProgressing = "Progressing"
 	ProxyAvailable             = "ProxyAvailable"
 	Registered                 = "Registered"
+	SynchronousReplication     = "SynchronousReplication"
+)
+
+// A root certificate authority rotates in phases. "Distributing" adds the new
+// authority to every trusted bundle; certificates are still signed by the old
+// one. "Reissuing" signs new certificates with the new authority while both
+// remain trusted. "Complete" removes the old authority from every bundle.
+// Each phase lasts until PostgreSQL in every running instance of the namespace
+// has loaded the authorities of that phase.
+type RootCertificateRotationStatus struct {
+
+	// The value of the annotation that started the rotation, when this
+	// cluster started it.
+	// +optional
+	Trigger string `json:"trigger,omitempty"`
+
+	// The current phase of the rotation.
+	// +kubebuilder:validation:Enum={Distributing,Reissuing,Complete}
+	// +required
+	Phase string `json:"phase"`
+
+	// When the rotation entered its current phase.
+	// +required
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// RootCertificateRotationStatus phases.
+const (
+	RootCertificateRotationComplete     = "Complete"
+	RootCertificateRotationDistributing = "Distributing"
+	RootCertificateRotationReissuing    = "Reissuing"
+)
+
+type CertificateStatus struct {
+
+	// The name of the Secret that contains the certificate.
+	// +required
+	Secret string `json:"secret"`
+
+	// The key of the certificate in that Secret.
+	// +required
+	Key string `json:"key"`
+
+	// Where the certificate comes from: "Operator" when the operator issued
+	// and renews it, "Issuer" when it comes from spec.certificateIssuer,
+	// or "Custom" when it is provided by the user and not renewed.
+	// +kubebuilder:validation:Enum={Operator,Issuer,Custom}
+	// +required
+	Source string `json:"source"`
+
+	// The distinguished name of the certificate subject.
+	// +optional
+	Subject string `json:"subject,omitempty"`
+
+	// The distinguished name of the authority that issued the certificate.
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+
+	// When the certificate expires.
+	// +required
+	NotAfter metav1.Time `json:"notAfter"`
+}
+
+// CertificateStatus sources.
+const (
+	CertificateSourceCustom   = "Custom"
+	CertificateSourceIssuer   = "Issuer"
+	CertificateSourceOperator = "Operator"
 )
 
 type PostgresInstanceSetSpec struct {
@@ -466,6 +729,31 @@ type PostgresInstanceSetSpec struct {
 	// +kubebuilder:validation:XValidation:rule=`has(self.resources) && has(self.resources.requests) && has(self.resources.requests.storage)`,message=`missing storage request`
 	DataVolumeClaimSpec corev1.PersistentVolumeClaimSpec `json:"dataVolumeClaimSpec"`
 
+	// What to do with data volumes that are larger than dataVolumeClaimSpec
+	// requests, since Kubernetes cannot shrink them. "DryRun" reports the
+	// projected usage of smaller volumes in status. "Enabled" also replaces
+	// instances on larger volumes with new instances when the data fits.
+	// +kubebuilder:validation:Enum={Disabled,DryRun,Enabled}
+	// +optional
+	DataVolumeShrink string `json:"dataVolumeShrink,omitempty"`
+
+	// Priority of instances in this set when Patroni chooses a replica to
+	// promote. Instances with higher values are preferred. Zero means
+	// instances in this set are never promoted, e.g. in a disaster recovery
+	// zone. When omitted, Patroni uses its default of one.
+	// More info: https://patroni.readthedocs.io/en/latest/yaml_configuration.html#tags
+	// ---
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailoverPriority *int32 `json:"failoverPriority,omitempty"`
+
+	// Whether the primary should run in this set. When the primary is in a
+	// set that is not preferred, it is switched over to a replica in a
+	// preferred set during a maintenance window. The primary is not moved
+	// when the cluster has no maintenanceWindows.
+	// +optional
+	PreferredPrimary bool `json:"preferredPrimary,omitempty"`
+
 	// Priority class name for the PostgreSQL pod. Changing this value causes
 	// PostgreSQL to restart.
 	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/
@@ -483,6 +771,36 @@ type PostgresInstanceSetSpec struct {
 	// +optional
 	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
 
+	// PostgreSQL parameters that apply only to instances in this set. These
+	// are merged on top of cluster-wide parameters and rendered as Patroni
+	// local configuration. Parameters that must be the same on every instance,
+	// such as max_connections, are taken from cluster-wide configuration and
+	// ignored here.
+	// More info: https://www.postgresql.org/docs/current/runtime-config.html
+	// ---
+	// +kubebuilder:validation:MaxProperties=50
+	// +mapType=granular
+	// +optional
+	Parameters map[string]intstr.IntOrString `json:"parameters,omitempty"`
+
+	// Delay applying WAL on instances in this set so that they can be used to
+	// recover from human error, such as a dropped table, without a full
+	// point-in-time recovery. Delayed instances are never promoted, never
+	// become synchronous standbys, and do not receive traffic from the replica
+	// Service. At least one instance set must not be delayed. The value is an
+	// integer with an optional unit: ms, s, min, h, or d.
+	// More info: https://www.postgresql.org/docs/current/runtime-config-replication.html#GUC-RECOVERY-MIN-APPLY-DELAY
+	// ---
+	// +kubebuilder:validation:Pattern=`^[0-9]+ ?(ms|s|min|h|d)?$`
+	// +optional
+	RecoveryMinApplyDelay *string `json:"recoveryMinApplyDelay,omitempty"`
+
+	// How Pods in this set are replaced when their specification changes.
+	// When omitted, one Pod in the cluster is replaced at a time and the
+	// primary is replaced last.
+	// +optional
+	RolloutStrategy *PostgresInstanceSetRolloutStrategy `json:"rolloutStrategy,omitempty"`
+
 	// Compute resources of a PostgreSQL container.
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
@@ -561,6 +879,27 @@ type TablespaceVolume struct {
 	// - https://releases.k8s.io/v1.31.0/pkg/apis/core/validation/validation.go#L2318-L2325
 	// +kubebuilder:validation:XValidation:rule=`has(self.resources) && has(self.resources.requests) && has(self.resources.requests.storage)`,message=`missing storage request`
 	DataVolumeClaimSpec corev1.PersistentVolumeClaimSpec `json:"dataVolumeClaimSpec"`
+
+	// The role that owns the tablespace in PostgreSQL. When this role does not
+	// exist yet, the tablespace is created and then given to the role after it
+	// exists. Defaults to the bootstrap superuser.
+	// More info: https://www.postgresql.org/docs/current/sql-createtablespace.html
+	// +kubebuilder:validation:Type=string
+	// +optional
+	Owner *PostgresIdentifier `json:"owner,omitempty"`
+
+	// Options of the tablespace in PostgreSQL, such as "seq_page_cost".
+	// More info: https://www.postgresql.org/docs/current/sql-altertablespace.html
+	// +kubebuilder:validation:XValidation:rule=`self.all(k, k in ['seq_page_cost', 'random_page_cost', 'effective_io_concurrency', 'maintenance_io_concurrency'])`,message=`unsupported tablespace option`
+	// +mapType=granular
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+
+	// Roles that can create objects in the tablespace. Removing a role from
+	// this list does NOT revoke its privilege.
+	// +listType=set
+	// +optional
+	Grants []PostgresIdentifier `json:"grants,omitempty"`
 }
 
 // InstanceSidecars defines the configuration for instance sidecar containers
@@ -600,8 +939,160 @@ type PostgresInstanceSetStatus struct {
 	// Desired Size of the pgData volume
 	// +optional
 	DesiredPGDataVolume map[string]string `json:"desiredPGDataVolume,omitempty"`
+
+	// Desired Size of the pgWAL volume
+	// +optional
+	DesiredPGWALVolume map[string]string `json:"desiredPGWALVolume,omitempty"`
+
+	// Desired Size of each tablespace volume, by tablespace name
+	// +optional
+	DesiredTablespaceVolumes map[string]map[string]string `json:"desiredTablespaceVolumes,omitempty"`
+
+	// PostgreSQL parameters in effect for instances in this set after its
+	// parameters are merged on top of cluster-wide parameters.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// The largest amount of time, in seconds, between now and the last
+	// transaction replayed by an instance in this set. This is reported
+	// only for sets with a recoveryMinApplyDelay. It is measured at most
+	// once a minute.
+	// +optional
+	ApplyLagSeconds *int64 `json:"applyLagSeconds,omitempty"`
+
+	// When applyLagSeconds was last measured.
+	// +optional
+	ApplyLagObserved *metav1.Time `json:"applyLagObserved,omitempty"`
+
+	// Progress replacing Pods that do not have the desired specification.
+	// +optional
+	Rollout *PostgresInstanceSetRolloutStatus `json:"rollout,omitempty"`
+
+	// Progress moving instances in this set to the storage class of its
+	// dataVolumeClaimSpec, by instance name.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	StorageMigration []PostgresInstanceStorageMigrationStatus `json:"storageMigration,omitempty"`
+
+	// Projected usage of data volumes at the size dataVolumeClaimSpec
+	// requests when instances in this set have larger volumes.
+	// +optional
+	DataVolumeShrink *PostgresVolumeShrinkStatus `json:"dataVolumeShrink,omitempty"`
 }
 
+// PostgresInstanceSetSpec dataVolumeShrink options.
+const (
+	VolumeShrinkDisabled = "Disabled"
+	VolumeShrinkDryRun   = "DryRun"
+	VolumeShrinkEnabled  = "Enabled"
+)
+
+type PostgresVolumeShrinkStatus struct {
+	// Number of instances with data volumes larger than requested.
+	// +optional
+	Oversized int32 `json:"oversized,omitempty"`
+
+	// The storage requested by dataVolumeClaimSpec.
+	// +optional
+	Request *resource.Quantity `json:"request,omitempty"`
+
+	// Space used on the data volume of the primary.
+	// +optional
+	Used *resource.Quantity `json:"used,omitempty"`
+
+	// Percentage of the request that the data would use.
+	// +optional
+	ProjectedUsagePercent *int32 `json:"projectedUsagePercent,omitempty"`
+
+	// Whether or not the data fits on volumes of the requested size.
+	Fits bool `json:"fits"`
+
+	// A human readable description of the projection.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type PostgresInstanceSetRolloutStrategy struct {
+	// The number or percentage of replicas in this set that can be unavailable
+	// while Pods are replaced. Percentages are rounded down, but at least one
+	// Pod is replaced at a time. Pods in this set are not replaced while any
+	// instance of another set is unavailable.
+	// +kubebuilder:default=1
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// Number of seconds to wait after a replaced Pod is ready before replacing
+	// another Pod in this set.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PauseSeconds *int32 `json:"pauseSeconds,omitempty"`
+
+	// Wait for replaced replicas in this set to be streaming from the primary
+	// with at most this much replication lag before replacing another Pod.
+	// +optional
+	MaxReplicationLag *resource.Quantity `json:"maxReplicationLag,omitempty"`
+
+	// How to replace the primary when it is in this set. "PrimaryLast"
+	// replaces replicas first then switches over and replaces the former
+	// primary. "SwitchoverFirst" switches over and replaces the former primary
+	// before any replicas.
+	// +kubebuilder:validation:Enum={PrimaryLast,SwitchoverFirst}
+	// +kubebuilder:default=PrimaryLast
+	// +optional
+	Primary string `json:"primary,omitempty"`
+}
+
+// PostgresInstanceSetRolloutStrategy primary options.
+const (
+	RolloutPrimaryLast     = "PrimaryLast"
+	RolloutSwitchoverFirst = "SwitchoverFirst"
+)
+
+type PostgresInstanceSetRolloutStatus struct {
+	// Number of Pods that still need to be replaced.
+	// +optional
+	Pending int32 `json:"pending,omitempty"`
+
+	// Why the rollout is not replacing Pods right now: "MaxUnavailable",
+	// "Pause", "ReplicationLag", or "PrimaryLast".
+	// +optional
+	Waiting string `json:"waiting,omitempty"`
+
+	// A human readable description of the rollout.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type PostgresInstanceStorageMigrationStatus struct {
+	// Name of the instance.
+	Name string `json:"name"`
+
+	// The storage class of the instance's data volume.
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// What is happening to the instance. Instances on the previous storage
+	// class are "Replacing" until their replacements are "Synced", then
+	// "SwitchingOver" when primary and "Retiring" when deleted. Replacements
+	// are "Syncing" until they are ready and streaming from the primary.
+	// +kubebuilder:validation:Enum={Replacing,SwitchingOver,Retiring,Syncing,Synced}
+	Phase string `json:"phase"`
+
+	// A human readable description of the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PostgresInstanceStorageMigrationStatus phases.
+const (
+	StorageMigrationReplacing     = "Replacing"
+	StorageMigrationSwitchingOver = "SwitchingOver"
+	StorageMigrationRetiring      = "Retiring"
+	StorageMigrationSyncing       = "Syncing"
+	StorageMigrationSynced        = "Synced"
+)
+
 // PostgresProxySpec is a union of the supported PostgreSQL proxies.
 type PostgresProxySpec struct {
 
@@ -672,8 +1163,42 @@ type PostgresUserInterfaceStatus struct {
 
 type PostgresAdditionalConfig struct {
 	Files []corev1.VolumeProjection `json:"files,omitempty"`
+
+	// PostgreSQL parameters that apply to every instance. Names and values are
+	// checked against the parameters of spec.postgresVersion; invalid entries
+	// are reported in events and ignored. Names the operator does not know are
+	// reported in events and passed to PostgreSQL as-is. Parameters with a
+	// period in their name belong to extensions and are not checked. These
+	// take precedence over parameters in spec.patroni.dynamicConfiguration,
+	// but parameters required by the operator cannot be changed.
+	// More info: https://www.postgresql.org/docs/current/runtime-config.html
+	// ---
+	// +kubebuilder:validation:MaxProperties=100
+	// +mapType=granular
+	// +optional
+	Parameters map[string]intstr.IntOrString `json:"parameters,omitempty"`
+
+	// Computes shared_buffers, effective_cache_size, work_mem,
+	// maintenance_work_mem, and max_connections from the memory of instances
+	// for a kind of workload: "oltp" for many short transactions, "olap" for
+	// few large queries, or "mixed". Memory parameters use the memory limit,
+	// or request, of each instance set. max_connections must be the same on
+	// every instance, so it uses the smallest instance set. These are
+	// defaults; values in parameters, spec.patroni.dynamicConfiguration, and
+	// the parameters of an instance set take precedence. Defaults to "off".
+	// +kubebuilder:validation:Enum={oltp,olap,mixed,off}
+	// +optional
+	TuningProfile string `json:"tuningProfile,omitempty"`
 }
 
+// PostgresAdditionalConfig tuning profiles.
+const (
+	PostgresTuningMixed = "mixed"
+	PostgresTuningOff   = "off"
+	PostgresTuningOLAP  = "olap"
+	PostgresTuningOLTP  = "oltp"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +operator-sdk:csv:customresourcedefinitions:resources={{ConfigMap,v1},{Secret,v1},{Service,v1},{CronJob,v1beta1},{Deployment,v1},{Job,v1},{StatefulSet,v1},{PersistentVolumeClaim,v1}}
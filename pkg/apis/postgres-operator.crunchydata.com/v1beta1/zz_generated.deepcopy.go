@@ -92,6 +92,92 @@ func (in *Backups) DeepCopy() *Backups {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerSpec) DeepCopyInto(out *CertManagerIssuerSpec) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerSpec.
+func (in *CertManagerIssuerSpec) DeepCopy() *CertManagerIssuerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateIssuerSpec) DeepCopyInto(out *CertificateIssuerSpec) {
+	*out = *in
+	if in.CASecret != nil {
+		in, out := &in.CASecret, &out.CASecret
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerIssuerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateIssuerSpec.
+func (in *CertificateIssuerSpec) DeepCopy() *CertificateIssuerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateIssuerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificatePolicySpec) DeepCopyInto(out *CertificatePolicySpec) {
+	*out = *in
+	if in.LeafLifetime != nil {
+		in, out := &in.LeafLifetime, &out.LeafLifetime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatePolicySpec.
+func (in *CertificatePolicySpec) DeepCopy() *CertificatePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificatePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterUpgrade) DeepCopyInto(out *ClusterUpgrade) {
 	*out = *in
@@ -411,6 +497,27 @@ func (in *InstanceSidecars) DeepCopy() *InstanceSidecars {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Metadata) DeepCopyInto(out *Metadata) {
 	*out = *in
@@ -1318,6 +1425,102 @@ func (in *PGMonitorSpec) DeepCopy() *PGMonitorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGSwitchover) DeepCopyInto(out *PGSwitchover) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGSwitchover.
+func (in *PGSwitchover) DeepCopy() *PGSwitchover {
+	if in == nil {
+		return nil
+	}
+	out := new(PGSwitchover)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PGSwitchover) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGSwitchoverList) DeepCopyInto(out *PGSwitchoverList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PGSwitchover, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGSwitchoverList.
+func (in *PGSwitchoverList) DeepCopy() *PGSwitchoverList {
+	if in == nil {
+		return nil
+	}
+	out := new(PGSwitchoverList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PGSwitchoverList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGSwitchoverSpec) DeepCopyInto(out *PGSwitchoverSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGSwitchoverSpec.
+func (in *PGSwitchoverSpec) DeepCopy() *PGSwitchoverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PGSwitchoverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGSwitchoverStatus) DeepCopyInto(out *PGSwitchoverStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGSwitchoverStatus.
+func (in *PGSwitchoverStatus) DeepCopy() *PGSwitchoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PGSwitchoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PGUpgrade) DeepCopyInto(out *PGUpgrade) {
 	*out = *in
@@ -1471,6 +1674,11 @@ func (in *PatroniSpec) DeepCopyInto(out *PatroniSpec) {
 		*out = new(PatroniSwitchover)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Synchronous != nil {
+		in, out := &in.Synchronous, &out.Synchronous
+		*out = new(PatroniSynchronous)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniSpec.
@@ -1496,6 +1704,20 @@ func (in *PatroniStatus) DeepCopyInto(out *PatroniStatus) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.PendingRestart != nil {
+		in, out := &in.PendingRestart, &out.PendingRestart
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingRestartSince != nil {
+		in, out := &in.PendingRestartSince, &out.PendingRestartSince
+		*out = (*in).DeepCopy()
+	}
+	if in.SynchronousStandbys != nil {
+		in, out := &in.SynchronousStandbys, &out.SynchronousStandbys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniStatus.
@@ -1528,6 +1750,26 @@ func (in *PatroniSwitchover) DeepCopy() *PatroniSwitchover {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniSynchronous) DeepCopyInto(out *PatroniSynchronous) {
+	*out = *in
+	if in.NodeCount != nil {
+		in, out := &in.NodeCount, &out.NodeCount
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniSynchronous.
+func (in *PatroniSynchronous) DeepCopy() *PatroniSynchronous {
+	if in == nil {
+		return nil
+	}
+	out := new(PatroniSynchronous)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresAdditionalConfig) DeepCopyInto(out *PostgresAdditionalConfig) {
 	*out = *in
@@ -1538,6 +1780,13 @@ func (in *PostgresAdditionalConfig) DeepCopyInto(out *PostgresAdditionalConfig)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]intstr.IntOrString, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresAdditionalConfig.
@@ -1550,6 +1799,28 @@ func (in *PostgresAdditionalConfig) DeepCopy() *PostgresAdditionalConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresAuthenticationSpec) DeepCopyInto(out *PostgresAuthenticationSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]PostgresHBARuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresAuthenticationSpec.
+func (in *PostgresAuthenticationSpec) DeepCopy() *PostgresAuthenticationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresAuthenticationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresCluster) DeepCopyInto(out *PostgresCluster) {
 	*out = *in
@@ -1671,11 +1942,33 @@ func (in *PostgresClusterSpec) DeepCopyInto(out *PostgresClusterSpec) {
 		*out = new(corev1.SecretProjection)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CertificateIssuer != nil {
+		in, out := &in.CertificateIssuer, &out.CertificateIssuer
+		*out = new(CertificateIssuerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateExpiryWarning != nil {
+		in, out := &in.CertificateExpiryWarning, &out.CertificateExpiryWarning
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CertificatePolicy != nil {
+		in, out := &in.CertificatePolicy, &out.CertificatePolicy
+		*out = new(CertificatePolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DatabaseInitSQL != nil {
 		in, out := &in.DatabaseInitSQL, &out.DatabaseInitSQL
 		*out = new(DatabaseInitSQL)
 		**out = **in
 	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PostgresDatabaseSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.DisableDefaultPodScheduling != nil {
 		in, out := &in.DisableDefaultPodScheduling, &out.DisableDefaultPodScheduling
 		*out = new(bool)
@@ -1693,6 +1986,13 @@ func (in *PostgresClusterSpec) DeepCopyInto(out *PostgresClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.OpenShift != nil {
 		in, out := &in.OpenShift, &out.OpenShift
 		*out = new(bool)
@@ -1760,6 +2060,23 @@ func (in *PostgresClusterSpec) DeepCopyInto(out *PostgresClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UserPruning != nil {
+		in, out := &in.UserPruning, &out.UserPruning
+		*out = new(PostgresUserPruningSpec)
+		**out = **in
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]PostgresRoleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(PostgresAuthenticationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Config.DeepCopyInto(&out.Config)
 }
 
@@ -1776,6 +2093,20 @@ func (in *PostgresClusterSpec) DeepCopy() *PostgresClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresClusterStatus) DeepCopyInto(out *PostgresClusterStatus) {
 	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PostgresDatabaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tablespaces != nil {
+		in, out := &in.Tablespaces, &out.Tablespaces
+		*out = make([]PostgresTablespaceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.InstanceSets != nil {
 		in, out := &in.InstanceSets, &out.InstanceSets
 		*out = make([]PostgresInstanceSetStatus, len(*in))
@@ -1800,6 +2131,32 @@ func (in *PostgresClusterStatus) DeepCopyInto(out *PostgresClusterStatus) {
 		*out = new(PostgresUserInterfaceStatus)
 		**out = **in
 	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]PostgresUserStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]PostgresRoleGrantsStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RootCertificateRotation != nil {
+		in, out := &in.RootCertificateRotation, &out.RootCertificateRotation
+		*out = new(RootCertificateRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.Monitoring = in.Monitoring
 	if in.DatabaseInitSQL != nil {
 		in, out := &in.DatabaseInitSQL, &out.DatabaseInitSQL
@@ -1826,26 +2183,267 @@ func (in *PostgresClusterStatus) DeepCopy() *PostgresClusterStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostgresInstanceSetSpec) DeepCopyInto(out *PostgresInstanceSetSpec) {
+func (in *PostgresDatabaseSpec) DeepCopyInto(out *PostgresDatabaseSpec) {
 	*out = *in
-	if in.Metadata != nil {
-		in, out := &in.Metadata, &out.Metadata
-		*out = new(Metadata)
-		(*in).DeepCopyInto(*out)
+	if in.Owner != nil {
+		in, out := &in.Owner, &out.Owner
+		*out = new(PostgresIdentifier)
+		**out = **in
 	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(corev1.Affinity)
-		(*in).DeepCopyInto(*out)
+	if in.Encoding != nil {
+		in, out := &in.Encoding, &out.Encoding
+		*out = new(string)
+		**out = **in
 	}
-	if in.Containers != nil {
-		in, out := &in.Containers, &out.Containers
-		*out = make([]corev1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.LCCollate != nil {
+		in, out := &in.LCCollate, &out.LCCollate
+		*out = new(string)
+		**out = **in
+	}
+	if in.LCCType != nil {
+		in, out := &in.LCCType, &out.LCCType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(PostgresIdentifier)
+		**out = **in
+	}
+	if in.ConnectionLimit != nil {
+		in, out := &in.ConnectionLimit, &out.ConnectionLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Tablespace != nil {
+		in, out := &in.Tablespace, &out.Tablespace
+		*out = new(PostgresIdentifier)
+		**out = **in
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]PostgresExtensionSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresDatabaseSpec.
+func (in *PostgresDatabaseSpec) DeepCopy() *PostgresDatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresDatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresDatabaseStatus) DeepCopyInto(out *PostgresDatabaseStatus) {
+	*out = *in
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]PostgresExtensionStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresDatabaseStatus.
+func (in *PostgresDatabaseStatus) DeepCopy() *PostgresDatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresDatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresExtensionSpec) DeepCopyInto(out *PostgresExtensionSpec) {
+	*out = *in
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(PostgresIdentifier)
+		**out = **in
+	}
+	if in.Libraries != nil {
+		in, out := &in.Libraries, &out.Libraries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresExtensionSpec.
+func (in *PostgresExtensionSpec) DeepCopy() *PostgresExtensionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresExtensionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresExtensionStatus) DeepCopyInto(out *PostgresExtensionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresExtensionStatus.
+func (in *PostgresExtensionStatus) DeepCopy() *PostgresExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresGrantSpec) DeepCopyInto(out *PostgresGrantSpec) {
+	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]PostgresPrivilege, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresGrantSpec.
+func (in *PostgresGrantSpec) DeepCopy() *PostgresGrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresHBARuleSpec) DeepCopyInto(out *PostgresHBARuleSpec) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretOptions != nil {
+		in, out := &in.SecretOptions, &out.SecretOptions
+		*out = make(map[string]corev1.SecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresHBARuleSpec.
+func (in *PostgresHBARuleSpec) DeepCopy() *PostgresHBARuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresHBARuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresInstanceSetRolloutStatus) DeepCopyInto(out *PostgresInstanceSetRolloutStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresInstanceSetRolloutStatus.
+func (in *PostgresInstanceSetRolloutStatus) DeepCopy() *PostgresInstanceSetRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresInstanceSetRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresInstanceSetRolloutStrategy) DeepCopyInto(out *PostgresInstanceSetRolloutStrategy) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.PauseSeconds != nil {
+		in, out := &in.PauseSeconds, &out.PauseSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicationLag != nil {
+		in, out := &in.MaxReplicationLag, &out.MaxReplicationLag
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresInstanceSetRolloutStrategy.
+func (in *PostgresInstanceSetRolloutStrategy) DeepCopy() *PostgresInstanceSetRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresInstanceSetRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresInstanceSetSpec) DeepCopyInto(out *PostgresInstanceSetSpec) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(Metadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	in.DataVolumeClaimSpec.DeepCopyInto(&out.DataVolumeClaimSpec)
+	if in.FailoverPriority != nil {
+		in, out := &in.FailoverPriority, &out.FailoverPriority
+		*out = new(int32)
+		**out = **in
+	}
 	if in.PriorityClassName != nil {
 		in, out := &in.PriorityClassName, &out.PriorityClassName
 		*out = new(string)
@@ -1861,6 +2459,23 @@ func (in *PostgresInstanceSetSpec) DeepCopyInto(out *PostgresInstanceSetSpec) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]intstr.IntOrString, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RecoveryMinApplyDelay != nil {
+		in, out := &in.RecoveryMinApplyDelay, &out.RecoveryMinApplyDelay
+		*out = new(string)
+		**out = **in
+	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(PostgresInstanceSetRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.Sidecars != nil {
 		in, out := &in.Sidecars, &out.Sidecars
@@ -1915,6 +2530,62 @@ func (in *PostgresInstanceSetStatus) DeepCopyInto(out *PostgresInstanceSetStatus
 			(*out)[key] = val
 		}
 	}
+	if in.DesiredPGWALVolume != nil {
+		in, out := &in.DesiredPGWALVolume, &out.DesiredPGWALVolume
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DesiredTablespaceVolumes != nil {
+		in, out := &in.DesiredTablespaceVolumes, &out.DesiredTablespaceVolumes
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ApplyLagSeconds != nil {
+		in, out := &in.ApplyLagSeconds, &out.ApplyLagSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ApplyLagObserved != nil {
+		in, out := &in.ApplyLagObserved, &out.ApplyLagObserved
+		*out = (*in).DeepCopy()
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(PostgresInstanceSetRolloutStatus)
+		**out = **in
+	}
+	if in.StorageMigration != nil {
+		in, out := &in.StorageMigration, &out.StorageMigration
+		*out = make([]PostgresInstanceStorageMigrationStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataVolumeShrink != nil {
+		in, out := &in.DataVolumeShrink, &out.DataVolumeShrink
+		*out = new(PostgresVolumeShrinkStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresInstanceSetStatus.
@@ -1927,6 +2598,42 @@ func (in *PostgresInstanceSetStatus) DeepCopy() *PostgresInstanceSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresInstanceStorageMigrationStatus) DeepCopyInto(out *PostgresInstanceStorageMigrationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresInstanceStorageMigrationStatus.
+func (in *PostgresInstanceStorageMigrationStatus) DeepCopy() *PostgresInstanceStorageMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresInstanceStorageMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresPasswordRotationSpec) DeepCopyInto(out *PostgresPasswordRotationSpec) {
+	*out = *in
+	out.Period = in.Period
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresPasswordRotationSpec.
+func (in *PostgresPasswordRotationSpec) DeepCopy() *PostgresPasswordRotationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresPasswordRotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresPasswordSpec) DeepCopyInto(out *PostgresPasswordSpec) {
 	*out = *in
@@ -1978,6 +2685,60 @@ func (in *PostgresProxyStatus) DeepCopy() *PostgresProxyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresRoleGrantsStatus) DeepCopyInto(out *PostgresRoleGrantsStatus) {
+	*out = *in
+	if in.MemberOf != nil {
+		in, out := &in.MemberOf, &out.MemberOf
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]PostgresGrantSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresRoleGrantsStatus.
+func (in *PostgresRoleGrantsStatus) DeepCopy() *PostgresRoleGrantsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresRoleGrantsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresRoleSpec) DeepCopyInto(out *PostgresRoleSpec) {
+	*out = *in
+	if in.MemberOf != nil {
+		in, out := &in.MemberOf, &out.MemberOf
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]PostgresGrantSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresRoleSpec.
+func (in *PostgresRoleSpec) DeepCopy() *PostgresRoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresRoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresStandbySpec) DeepCopyInto(out *PostgresStandbySpec) {
 	*out = *in
@@ -1998,6 +2759,31 @@ func (in *PostgresStandbySpec) DeepCopy() *PostgresStandbySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresTablespaceStatus) DeepCopyInto(out *PostgresTablespaceStatus) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresTablespaceStatus.
+func (in *PostgresTablespaceStatus) DeepCopy() *PostgresTablespaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresTablespaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresUserInterfaceStatus) DeepCopyInto(out *PostgresUserInterfaceStatus) {
 	*out = *in
@@ -2014,6 +2800,21 @@ func (in *PostgresUserInterfaceStatus) DeepCopy() *PostgresUserInterfaceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresUserPruningSpec) DeepCopyInto(out *PostgresUserPruningSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresUserPruningSpec.
+func (in *PostgresUserPruningSpec) DeepCopy() *PostgresUserPruningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresUserPruningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresUserSpec) DeepCopyInto(out *PostgresUserSpec) {
 	*out = *in
@@ -2022,11 +2823,33 @@ func (in *PostgresUserSpec) DeepCopyInto(out *PostgresUserSpec) {
 		*out = make([]PostgresIdentifier, len(*in))
 		copy(*out, *in)
 	}
+	if in.MemberOf != nil {
+		in, out := &in.MemberOf, &out.MemberOf
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]PostgresGrantSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Password != nil {
 		in, out := &in.Password, &out.Password
 		*out = new(PostgresPasswordSpec)
 		**out = **in
 	}
+	if in.PasswordRotation != nil {
+		in, out := &in.PasswordRotation, &out.PasswordRotation
+		*out = new(PostgresPasswordRotationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresUserSpec.
@@ -2039,6 +2862,59 @@ func (in *PostgresUserSpec) DeepCopy() *PostgresUserSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresUserStatus) DeepCopyInto(out *PostgresUserStatus) {
+	*out = *in
+	if in.PasswordRotated != nil {
+		in, out := &in.PasswordRotated, &out.PasswordRotated
+		*out = (*in).DeepCopy()
+	}
+	if in.PreviousPasswordExpires != nil {
+		in, out := &in.PreviousPasswordExpires, &out.PreviousPasswordExpires
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresUserStatus.
+func (in *PostgresUserStatus) DeepCopy() *PostgresUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresVolumeShrinkStatus) DeepCopyInto(out *PostgresVolumeShrinkStatus) {
+	*out = *in
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.ProjectedUsagePercent != nil {
+		in, out := &in.ProjectedUsagePercent, &out.ProjectedUsagePercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresVolumeShrinkStatus.
+func (in *PostgresVolumeShrinkStatus) DeepCopy() *PostgresVolumeShrinkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresVolumeShrinkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegistrationRequirementStatus) DeepCopyInto(out *RegistrationRequirementStatus) {
 	*out = *in
@@ -2146,6 +3022,22 @@ func (in *RepoStatus) DeepCopy() *RepoStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RootCertificateRotationStatus) DeepCopyInto(out *RootCertificateRotationStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RootCertificateRotationStatus.
+func (in *RootCertificateRotationStatus) DeepCopy() *RootCertificateRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RootCertificateRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in SchemalessObject) DeepCopyInto(out *SchemalessObject) {
 	{
@@ -2264,6 +3156,23 @@ func (in *StandalonePGAdminConfiguration) DeepCopy() *StandalonePGAdminConfigura
 func (in *TablespaceVolume) DeepCopyInto(out *TablespaceVolume) {
 	*out = *in
 	in.DataVolumeClaimSpec.DeepCopyInto(&out.DataVolumeClaimSpec)
+	if in.Owner != nil {
+		in, out := &in.Owner, &out.Owner
+		*out = new(PostgresIdentifier)
+		**out = **in
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TablespaceVolume.